@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/config"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// checkWallet is the wallet the database check creates its records for
+const checkWallet = "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY"
+
+// migrate applies pending database migrations
+func migrate(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("migrate up", flag.ExitOnError)
+	fs.Parse(args)
+
+	return withDB(cfg, func(db *database.DB) error {
+		log.Println("Running database migrations...")
+		return db.MigrateUp()
+	})
+}
+
+// migrateDown reverts the most recent migrations
+func migrateDown(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("migrate down", flag.ExitOnError)
+	steps := fs.Int("steps", 1, "number of migrations to revert")
+	fs.Parse(args)
+
+	return withDB(cfg, func(db *database.DB) error {
+		return db.MigrateDown(*steps)
+	})
+}
+
+// migrateRedo reverts and reapplies the most recent migration
+func migrateRedo(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("migrate redo", flag.ExitOnError)
+	fs.Parse(args)
+
+	return withDB(cfg, func(db *database.DB) error {
+		return db.MigrateRedo()
+	})
+}
+
+// migrateStatus lists the migrations and whether they have been applied
+func migrateStatus(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("migrate status", flag.ExitOnError)
+	fs.Parse(args)
+
+	return withDB(cfg, func(db *database.DB) error {
+		statuses, err := db.MigrationStatus()
+		if err != nil {
+			return err
+		}
+
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = "applied " + status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d  %-24s %s\n", status.Version, status.Name, applied)
+		}
+		return nil
+	})
+}
+
+// withDB runs fn with a database connection
+func withDB(cfg *config.Config, fn func(db *database.DB) error) error {
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return fn(db)
+}
+
+// dbCheck migrates the database and round-trips records through the repositories
+func dbCheck(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("db check", flag.ExitOnError)
+	fs.Parse(args)
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	log.Println("Running database migrations...")
+	if err := db.MigrateUp(); err != nil {
+		return fmt.Errorf("failed to run migrations: %v", err)
+	}
+
+	eventRepo := database.NewEventRepository(db)
+	userRepo := database.NewUserRepository(db)
+	permRepo := database.NewPermissionRepository(db)
+
+	user, err := userRepo.GetOrCreate(ctx, checkWallet)
+	if err != nil {
+		return fmt.Errorf("failed to create test user: %v", err)
+	}
+	log.Printf("Using test user with ID: %d", user.ID)
+
+	fetchedUser, err := userRepo.GetByWalletAddress(ctx, checkWallet)
+	if err != nil {
+		return fmt.Errorf("failed to fetch test user: %v", err)
+	}
+	if fetchedUser == nil {
+		return fmt.Errorf("test user not found")
+	}
+	log.Printf("Verified user retrieval by wallet address")
+
+	event := &models.Event{
+		Name:      "Test Event",
+		Date:      "2025-05-01",
+		Location:  "Test Location",
+		Organizer: checkWallet,
+	}
+	if err := eventRepo.Create(ctx, event); err != nil {
+		return fmt.Errorf("failed to create test event: %v", err)
+	}
+	log.Printf("Created test event with ID: %d", event.ID)
+
+	perm := &database.EventPermission{
+		EventID: event.ID,
+		UserID:  user.ID,
+		Role:    database.RoleOwner,
+	}
+	if err := permRepo.Create(ctx, perm); err != nil {
+		return fmt.Errorf("failed to create permission: %v", err)
+	}
+	log.Printf("Created test permission")
+
+	// Clean up test data
+	log.Println("Cleaning up test data...")
+	if err := permRepo.Delete(ctx, user.ID, event.ID); err != nil {
+		log.Printf("Warning: Failed to delete test permission: %v", err)
+	}
+	if _, err := eventRepo.Delete(ctx, event.ID, event.Version); err != nil {
+		log.Printf("Warning: Failed to delete test event: %v", err)
+	}
+
+	fmt.Println("\n✅ Database is properly set up and working!")
+	return nil
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/config"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/polkadot"
+)
+
+// deployFlags are shared by the deploy and upgrade commands
+type deployFlags struct {
+	rpc    string
+	suri   string
+	bundle string
+	record string
+}
+
+func (f *deployFlags) register(fs *flag.FlagSet, cfg *config.Config) {
+	fs.StringVar(&f.rpc, "rpc", cfg.PolkadotRPC, "node websocket URL")
+	fs.StringVar(&f.suri, "suri", getEnv("DEPLOYER_SURI", "//Alice"), "secret URI of the deploying account (env DEPLOYER_SURI)")
+	fs.StringVar(&f.bundle, "bundle", "../contracts/target/ink/attendance_nft.contract", "contract .contract bundle or .wasm file")
+	fs.StringVar(&f.record, "record", "contract-deployment.json", "deployment record used to check upgrades")
+}
+
+// deploy uploads the contract code and instantiates it
+func deploy(ctx context.Context, cfg *config.Config, args []string) error {
+	var common deployFlags
+	fs := flag.NewFlagSet("contract deploy", flag.ExitOnError)
+	common.register(fs, cfg)
+	constructor := fs.String("constructor", "new", "constructor to call")
+	saltFlag := fs.String("salt", "", "instantiation salt as 0x-hex or text (random if empty)")
+	writeConfig := fs.String("write-config", "", "config.json file to store the new contract_address in")
+	refTime := fs.Uint64("gas-ref-time", polkadot.DefaultInstantiateRefTime, "gas limit ref_time")
+	proofSize := fs.Uint64("gas-proof-size", polkadot.DefaultInstantiateProofSize, "gas limit proof_size")
+	fs.Parse(args)
+
+	bundle, err := polkadot.LoadContractBundle(common.bundle)
+	if err != nil {
+		return err
+	}
+
+	selector, err := bundle.ConstructorSelector(*constructor)
+	if err != nil {
+		return err
+	}
+
+	salt, err := parseSalt(*saltFlag)
+	if err != nil {
+		return err
+	}
+
+	deployer, err := polkadot.NewDeployer(common.rpc, common.suri)
+	if err != nil {
+		return err
+	}
+
+	chain := deployer.ChainName()
+	log.Printf("Deploying %s v%s to %s as %s", bundle.Metadata.Contract.Name, bundle.Metadata.Contract.Version, chain, deployer.Signer())
+
+	codeHash, err := deployer.UploadCode(ctx, bundle.Code)
+	if err != nil {
+		return fmt.Errorf("failed to upload code: %v", err)
+	}
+
+	gasLimit := types.NewWeight(types.NewUCompactFromUInt(*refTime), types.NewUCompactFromUInt(*proofSize))
+	contract, err := deployer.Instantiate(ctx, codeHash, selector, salt, gasLimit)
+	if err != nil {
+		return fmt.Errorf("failed to instantiate contract: %v", err)
+	}
+
+	contractAddress := contract.SS58(cfg.SS58Prefix)
+	log.Printf("Salt: %#x", salt)
+	log.Printf("Code hash: %#x", codeHash)
+	fmt.Println(contractAddress)
+
+	deployment := &polkadot.Deployment{
+		Address:    contractAddress,
+		CodeHash:   fmt.Sprintf("%#x", codeHash),
+		Chain:      chain,
+		Metadata:   bundle.Metadata,
+		DeployedAt: time.Now().UTC(),
+	}
+	if err := deployment.Save(common.record); err != nil {
+		return err
+	}
+	log.Printf("Wrote deployment record to %s", common.record)
+
+	if *writeConfig != "" {
+		if err := writeContractAddress(*writeConfig, contractAddress); err != nil {
+			return err
+		}
+		log.Printf("Set contract_address in %s", *writeConfig)
+	}
+
+	return nil
+}
+
+// upgrade uploads new code and points the deployed contract at it
+func upgrade(ctx context.Context, cfg *config.Config, args []string) error {
+	var common deployFlags
+	fs := flag.NewFlagSet("contract upgrade", flag.ExitOnError)
+	common.register(fs, cfg)
+	contractFlag := fs.String("contract", cfg.ContractAddress, "address of the deployed contract")
+	force := fs.Bool("force", false, "upgrade even if the metadata is incompatible or unknown")
+	useSudo := fs.Bool("sudo", true, "wrap Contracts.set_code in Sudo.sudo")
+	fs.Parse(args)
+
+	contract, _, err := address.Parse(*contractFlag)
+	if err != nil {
+		return fmt.Errorf("invalid contract address %q: %v", *contractFlag, err)
+	}
+
+	bundle, err := polkadot.LoadContractBundle(common.bundle)
+	if err != nil {
+		return err
+	}
+
+	deployer, err := polkadot.NewDeployer(common.rpc, common.suri)
+	if err != nil {
+		return err
+	}
+
+	deployed, err := deployer.DeployedCodeHash(contract)
+	if err != nil {
+		return err
+	}
+
+	newHash := bundle.CodeHash()
+	if deployed == newHash {
+		log.Printf("Contract already runs code %#x, nothing to do", newHash)
+		return nil
+	}
+
+	if err := checkUpgrade(common.record, contract, deployed, bundle.Metadata); err != nil {
+		if !*force {
+			return err
+		}
+		log.Printf("Ignoring failed compatibility check (-force): %v", err)
+	}
+
+	if _, err := deployer.UploadCode(ctx, bundle.Code); err != nil {
+		return fmt.Errorf("failed to upload code: %v", err)
+	}
+
+	if err := deployer.SetCode(ctx, contract, newHash, *useSudo); err != nil {
+		return fmt.Errorf("failed to set code: %v", err)
+	}
+
+	current, err := deployer.DeployedCodeHash(contract)
+	if err != nil {
+		return err
+	}
+	if current != newHash {
+		return fmt.Errorf("contract still runs code %#x after set_code", current)
+	}
+
+	log.Printf("Upgraded %s from %#x to %#x", contract.SS58(cfg.SS58Prefix), deployed, newHash)
+
+	deployment := &polkadot.Deployment{
+		Address:    contract.SS58(cfg.SS58Prefix),
+		CodeHash:   fmt.Sprintf("%#x", newHash),
+		Chain:      deployer.ChainName(),
+		Metadata:   bundle.Metadata,
+		DeployedAt: time.Now().UTC(),
+	}
+	if err := deployment.Save(common.record); err != nil {
+		return err
+	}
+	log.Printf("Updated deployment record %s", common.record)
+
+	return nil
+}
+
+// checkUpgrade compares the new metadata with the metadata recorded for the deployed code
+func checkUpgrade(recordPath string, contract address.AccountID, deployed types.H256, next *polkadot.ContractMetadata) error {
+	record, err := polkadot.LoadDeployment(recordPath)
+	if err != nil {
+		return fmt.Errorf("can't check compatibility without a deployment record: %v", err)
+	}
+
+	recorded, _, err := address.Parse(record.Address)
+	if err != nil || recorded != contract {
+		return fmt.Errorf("deployment record %s is for a different contract (%s)", recordPath, record.Address)
+	}
+
+	if !strings.EqualFold(record.CodeHash, fmt.Sprintf("%#x", deployed)) {
+		return fmt.Errorf("deployment record has code %s but the contract runs %#x", record.CodeHash, deployed)
+	}
+
+	if record.Metadata == nil {
+		return fmt.Errorf("deployment record has no metadata")
+	}
+
+	if problems := polkadot.CheckCompatibility(record.Metadata, next); len(problems) > 0 {
+		return fmt.Errorf("new code is incompatible with the deployed contract:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+
+	return nil
+}
+
+// parseSalt accepts a 0x-prefixed hex salt or plain text, generating a random salt if empty
+func parseSalt(value string) ([]byte, error) {
+	if value == "" {
+		salt := make([]byte, 32)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("failed to generate salt: %v", err)
+		}
+		return salt, nil
+	}
+
+	if strings.HasPrefix(value, "0x") {
+		salt, err := hex.DecodeString(value[2:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex salt: %v", err)
+		}
+		return salt, nil
+	}
+
+	return []byte(value), nil
+}
+
+// writeContractAddress sets contract_address in a JSON config file, keeping its other keys
+func writeContractAddress(path, contractAddress string) error {
+	values := make(map[string]interface{})
+
+	data, err := ioutil.ReadFile(path)
+	if err == nil {
+		if err := json.Unmarshal(data, &values); err != nil {
+			return fmt.Errorf("failed to parse %s: %v", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	values["contract_address"] = contractAddress
+
+	data, err = json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %v", path, err)
+	}
+
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
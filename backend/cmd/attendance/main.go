@@ -0,0 +1,112 @@
+// Command attendance runs the API server and the operational tools of the
+// attendance NFT backend. Every command loads the same configuration
+// (config.json in the working directory, overridden by environment variables).
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/config"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+)
+
+const usage = `Usage:
+  attendance serve                       run the API server
+  attendance migrate [up]                apply pending database migrations
+  attendance migrate down [-steps n]     revert the most recent migrations
+  attendance migrate redo                revert and reapply the most recent migration
+  attendance migrate status              list migrations and when they were applied
+  attendance db check                    verify the database connection and repositories
+  attendance contract dump-metadata      summarize contract metadata
+  attendance contract smoke-test         exercise the contract through the client
+  attendance contract deploy             upload and instantiate the contract
+  attendance contract upgrade            switch the deployed contract to new code
+
+Run "attendance <command> -h" for the flags of a command.`
+
+// command runs with the loaded configuration and its remaining arguments.
+// ctx is cancelled when the process receives SIGINT or SIGTERM.
+type command func(ctx context.Context, cfg *config.Config, args []string) error
+
+// commands maps command paths to their implementations
+var commands = map[string]command{
+	"serve":                  serve,
+	"migrate":                migrate,
+	"migrate up":             migrate,
+	"migrate down":           migrateDown,
+	"migrate redo":           migrateRedo,
+	"migrate status":         migrateStatus,
+	"db check":               dbCheck,
+	"contract dump-metadata": dumpMetadata,
+	"contract smoke-test":    smokeTest,
+	"contract deploy":        deploy,
+	"contract upgrade":       upgrade,
+}
+
+func main() {
+	log.SetOutput(os.Stdout)
+	log.SetFlags(log.Ldate | log.Ltime)
+
+	name, run, args := lookup(os.Args[1:])
+	if run == nil {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, config.Load(), args); err != nil {
+		log.Fatalf("%s failed: %v", name, err)
+	}
+}
+
+// lookup finds the command named by the leading arguments
+func lookup(args []string) (string, command, []string) {
+	if len(args) >= 2 {
+		name := args[0] + " " + args[1]
+		if run, exists := commands[name]; exists {
+			return name, run, args[2:]
+		}
+	}
+
+	if len(args) >= 1 {
+		if run, exists := commands[args[0]]; exists {
+			return args[0], run, args[1:]
+		}
+	}
+
+	return "", nil, nil
+}
+
+// openDB connects to the configured database
+func openDB(cfg *config.Config) (*database.DB, error) {
+	log.Printf("Connecting to database at %s:%d...", cfg.Database.Host, cfg.Database.Port)
+
+	db, err := database.New(database.Config{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		DBName:   cfg.Database.DBName,
+		SSLMode:  cfg.Database.SSLMode,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %v", err)
+	}
+
+	return db, nil
+}
+
+// getEnv gets an environment variable or returns a default value
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/api"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/config"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/polkadot"
+)
+
+// serve runs the API server until ctx is cancelled by SIGINT or SIGTERM
+func serve(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	fs.StringVar(&cfg.ServerAddress, "addr", cfg.ServerAddress, "address to listen on")
+	fs.Parse(args)
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Run database migrations
+	if err := db.MigrateUp(); err != nil {
+		return fmt.Errorf("failed to run database migrations: %v", err)
+	}
+
+	// Initialize repositories
+	eventRepo := database.NewEventRepository(db)
+	nftRepo := database.NewNFTRepository(db)
+	userRepo := database.NewUserRepository(db)
+	permRepo := database.NewPermissionRepository(db)
+	claimRepo := database.NewClaimRepository(db)
+	checkinRepo := database.NewCheckInRepository(db)
+	sourceRepo := database.NewEventSourceRepository(db)
+	policyRepo := database.NewClaimPolicyRepository(db)
+
+	// Validate contract address
+	formattedAddress := api.ValidateContractAddress(cfg.ContractAddress)
+
+	// Initialize Polkadot client
+	client := polkadot.NewClient(cfg.PolkadotRPC, formattedAddress)
+
+	// Select the chain backend for each event
+	if err := configureBackends(cfg.Chain, client, eventRepo); err != nil {
+		log.Printf("Failed to configure chain backends, using the contract for all events: %v", err)
+	}
+
+	// Requests and background jobs run under workCtx, which outlives ctx so
+	// they can finish during the shutdown grace period
+	workCtx, cancelWork := context.WithCancel(context.Background())
+	defer cancelWork()
+
+	// Create and configure the router
+	router := api.NewRouter(workCtx, cfg, client, eventRepo, nftRepo, userRepo, permRepo, claimRepo, checkinRepo, sourceRepo, policyRepo)
+
+	// Create HTTP server
+	srv := &http.Server{
+		Addr:        cfg.ServerAddress,
+		Handler:     router,
+		BaseContext: func(net.Listener) context.Context { return workCtx },
+	}
+
+	// Start server in a goroutine
+	go func() {
+		log.Printf("Server listening on %s", cfg.ServerAddress)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Server failed: %v", err)
+		}
+	}()
+
+	// Wait for the shutdown signal
+	<-ctx.Done()
+	log.Println("Shutting down server...")
+
+	// Give outstanding requests a deadline for completion, then cancel
+	// whatever is still running, including chain calls waiting on extrinsics
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = srv.Shutdown(shutdownCtx)
+	cancelWork()
+	if err != nil {
+		return fmt.Errorf("server forced to shutdown: %v", err)
+	}
+
+	// Close database connection
+	if err := db.Close(); err != nil {
+		log.Printf("Error closing database connection: %v", err)
+	}
+
+	log.Println("Server exited gracefully")
+	return nil
+}
+
+// configureBackends connects to the pallet-nfts chain when any event uses it
+func configureBackends(chain config.Chain, client *polkadot.Client, eventRepo *database.EventRepository) error {
+	eventBackends := make(map[uint64]string, len(chain.EventBackends))
+	usesNfts := chain.Backend == polkadot.BackendNfts
+	for key, backend := range chain.EventBackends {
+		eventID, err := strconv.ParseUint(key, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid event ID %q in chain.event_backends", key)
+		}
+		eventBackends[eventID] = backend
+		usesNfts = usesNfts || backend == polkadot.BackendNfts
+	}
+
+	backendCfg := polkadot.BackendConfig{
+		Default:       chain.Backend,
+		EventBackends: eventBackends,
+		ChainEvents:   eventRepo,
+	}
+
+	if usesNfts {
+		nfts, err := polkadot.NewNftsBackend(chain.AssetHubRPC, chain.SignerURI, eventRepo)
+		if err != nil {
+			return err
+		}
+		backendCfg.Nfts = nfts
+	}
+
+	return client.ConfigureBackends(backendCfg)
+}
@@ -0,0 +1,614 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// initialisms keeps Go naming for words that are written in capitals
+var initialisms = map[string]string{
+	"api":   "API",
+	"id":    "ID",
+	"ids":   "IDs",
+	"json":  "JSON",
+	"nft":   "NFT",
+	"nfts":  "NFTs",
+	"psp34": "PSP34",
+	"uri":   "URI",
+	"url":   "URL",
+}
+
+// returnKind describes how a message result is decoded
+type returnKind int
+
+const (
+	returnUnit returnKind = iota
+	returnValue
+	returnOption
+)
+
+// generator turns ink! metadata into Go source
+type generator struct {
+	meta    *inkMetadata
+	pkg     string
+	source  string
+	structs map[int]string // registry ID -> Go struct name
+	order   []int          // structs in the order they were first referenced
+	buf     bytes.Buffer
+}
+
+// generate renders the bindings for metadata
+func generate(meta *inkMetadata, pkg, source string) ([]byte, error) {
+	g := &generator{
+		meta:    meta,
+		pkg:     pkg,
+		source:  source,
+		structs: make(map[int]string),
+	}
+
+	// Resolve every type up front so unsupported types fail before any output
+	body, err := g.body()
+	if err != nil {
+		return nil, err
+	}
+
+	g.p("// Code generated by contract-bindgen from %s. DO NOT EDIT.", source)
+	g.p("")
+	g.p("package %s", pkg)
+	g.p("")
+	g.p("import (")
+	g.p(`"context"`)
+	if len(meta.Spec.Events) > 0 {
+		g.p(`"bytes"`)
+		g.p(`"fmt"`)
+	}
+	if len(g.structs) > 0 || len(meta.Spec.Events) > 0 {
+		g.p("")
+		g.p(`"github.com/centrifuge/go-substrate-rpc-client/v4/scale"`)
+	}
+	g.p(")")
+	g.p("")
+	g.buf.Write(body)
+
+	if err := g.writeStructs(); err != nil {
+		return nil, err
+	}
+
+	formatted, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid Go source: %v", err)
+	}
+	return formatted, nil
+}
+
+// body renders everything but the header and the type declarations
+func (g *generator) body() ([]byte, error) {
+	out := &generator{meta: g.meta, structs: g.structs}
+	defer func() { g.order = out.order }()
+
+	out.p("// Contract identity from the metadata")
+	out.p("const (")
+	out.p("ContractName = %q", g.meta.Contract.Name)
+	out.p("ContractVersion = %q", g.meta.Contract.Version)
+	out.p(")")
+	out.p("")
+
+	if len(g.meta.Spec.Constructors) > 0 {
+		out.p("// Constructor selectors")
+		out.p("var (")
+		for _, constructor := range g.meta.Spec.Constructors {
+			selector, err := selectorLiteral(constructor.Selector)
+			if err != nil {
+				return nil, fmt.Errorf("constructor %s: %v", constructor.Label, err)
+			}
+			out.p("Constructor%s = %s", exportedName(constructor.Label), selector)
+		}
+		out.p(")")
+		out.p("")
+	}
+
+	out.p("// Message selectors")
+	out.p("var (")
+	for _, message := range g.meta.Spec.Messages {
+		selector, err := selectorLiteral(message.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("message %s: %v", message.Label, err)
+		}
+		out.p("Selector%s = %s", exportedName(message.Label), selector)
+	}
+	out.p(")")
+	out.p("")
+
+	out.p("// Contract calls the %s messages through an Executor", g.meta.Contract.Name)
+	out.p("type Contract struct {")
+	out.p("exec Executor")
+	out.p("}")
+	out.p("")
+	out.p("// NewContract creates bindings that run messages with exec")
+	out.p("func NewContract(exec Executor) *Contract {")
+	out.p("return &Contract{exec: exec}")
+	out.p("}")
+	out.p("")
+
+	for _, message := range g.meta.Spec.Messages {
+		if err := out.message(message); err != nil {
+			return nil, fmt.Errorf("message %s: %v", message.Label, err)
+		}
+	}
+
+	if err := out.events(); err != nil {
+		return nil, err
+	}
+
+	return out.buf.Bytes(), nil
+}
+
+// message renders a typed method for a contract message
+func (g *generator) message(message inkMessage) error {
+	name := exportedName(message.Label)
+
+	params := []string{"ctx context.Context"}
+	var args []string
+	for _, arg := range message.Args {
+		goType, err := g.goType(arg.Type.Type)
+		if err != nil {
+			return fmt.Errorf("argument %s: %v", arg.Label, err)
+		}
+		local := localName(arg.Label)
+		params = append(params, local+" "+goType)
+		args = append(args, local)
+	}
+
+	if message.ReturnType == nil {
+		return fmt.Errorf("missing return type")
+	}
+	kind, resultType, err := g.resultType(message.ReturnType.Type)
+	if err != nil {
+		return err
+	}
+
+	call := fmt.Sprintf("execute(ctx, c.exec, %q, Selector%s, %t", message.Label, name, message.Mutates)
+	if len(args) > 0 {
+		call += ", " + strings.Join(args, ", ")
+	}
+	call += ")"
+
+	g.p("// %s calls the %s message.", name, message.Label)
+	g.docs(message.Docs)
+
+	switch kind {
+	case returnUnit:
+		g.p("func (c *Contract) %s(%s) error {", name, strings.Join(params, ", "))
+		g.p("output, err := %s", call)
+		g.p("if err != nil {")
+		g.p("return err")
+		g.p("}")
+		g.p("return decodeResult(output, nil)")
+
+	case returnOption:
+		g.p("func (c *Contract) %s(%s) (*%s, error) {", name, strings.Join(params, ", "), resultType)
+		g.p("output, err := %s", call)
+		g.p("if err != nil {")
+		g.p("return nil, err")
+		g.p("}")
+		g.p("var result %s", resultType)
+		g.p("found, err := decodeOptionResult(output, &result)")
+		g.p("if err != nil || !found {")
+		g.p("return nil, err")
+		g.p("}")
+		g.p("return &result, nil")
+
+	default:
+		g.p("func (c *Contract) %s(%s) (result %s, err error) {", name, strings.Join(params, ", "), resultType)
+		g.p("output, err := %s", call)
+		g.p("if err != nil {")
+		g.p("return result, err")
+		g.p("}")
+		g.p("err = decodeResult(output, &result)")
+		g.p("return result, err")
+	}
+
+	g.p("}")
+	g.p("")
+	return nil
+}
+
+// resultType unwraps the ink! MessageResult a message returns
+func (g *generator) resultType(id int) (returnKind, string, error) {
+	entry, err := g.meta.lookup(id)
+	if err != nil {
+		return 0, "", err
+	}
+
+	if lastPath(entry) != "Result" || len(entry.Type.Params) != 2 || entry.Type.Params[0].Type == nil {
+		return 0, "", fmt.Errorf("return type %d is not an ink! MessageResult", id)
+	}
+
+	inner, err := g.meta.lookup(*entry.Type.Params[0].Type)
+	if err != nil {
+		return 0, "", err
+	}
+
+	if inner.Type.Def.Tuple != nil && len(*inner.Type.Def.Tuple) == 0 {
+		return returnUnit, "", nil
+	}
+
+	if lastPath(inner) == "Option" && len(inner.Type.Params) == 1 && inner.Type.Params[0].Type != nil {
+		goType, err := g.goType(*inner.Type.Params[0].Type)
+		if err != nil {
+			return 0, "", err
+		}
+		return returnOption, goType, nil
+	}
+
+	goType, err := g.goType(inner.ID)
+	if err != nil {
+		return 0, "", err
+	}
+	return returnValue, goType, nil
+}
+
+// events renders event types and DecodeEvent
+func (g *generator) events() error {
+	events := g.meta.Spec.Events
+	if len(events) == 0 {
+		return nil
+	}
+
+	byTopic := true
+	for _, event := range events {
+		if event.SignatureTopic == nil {
+			byTopic = false
+		}
+	}
+
+	for _, event := range events {
+		name := typeName(event.Label)
+
+		var fields []inkField
+		for _, arg := range event.Args {
+			fields = append(fields, inkField{Name: arg.Label, Type: arg.Type.Type})
+		}
+
+		g.p("// %s is emitted by the contract.", name)
+		g.docs(event.Docs)
+		if err := g.structType(name, fields, false); err != nil {
+			return fmt.Errorf("event %s: %v", event.Label, err)
+		}
+	}
+
+	if byTopic {
+		g.p("// Event signature topics")
+		g.p("var (")
+		for _, event := range events {
+			topic, err := hexLiteral(*event.SignatureTopic, 32)
+			if err != nil {
+				return fmt.Errorf("event %s: %v", event.Label, err)
+			}
+			g.p("Topic%s = [32]byte%s", typeName(event.Label), topic)
+		}
+		g.p(")")
+		g.p("")
+
+		g.p("// DecodeEvent decodes a contract event from its signature topic and data")
+		g.p("func DecodeEvent(topic [32]byte, data []byte) (interface{}, error) {")
+		g.p("decoder := scale.NewDecoder(bytes.NewReader(data))")
+		g.p("switch topic {")
+		for _, event := range events {
+			g.p("case Topic%s:", typeName(event.Label))
+			g.decodeEventCase(typeName(event.Label))
+		}
+		g.p("}")
+		g.p(`return nil, fmt.Errorf("unknown event topic %%#x", topic)`)
+		g.p("}")
+		g.p("")
+		return nil
+	}
+
+	// ink! 4 encodes events as a single enum prefixed with the variant index
+	g.p("// DecodeEvent decodes a contract event from its data")
+	g.p("func DecodeEvent(data []byte) (interface{}, error) {")
+	g.p("if len(data) == 0 {")
+	g.p(`return nil, fmt.Errorf("empty event data")`)
+	g.p("}")
+	g.p("decoder := scale.NewDecoder(bytes.NewReader(data[1:]))")
+	g.p("switch data[0] {")
+	for i, event := range events {
+		g.p("case %d:", i)
+		g.decodeEventCase(typeName(event.Label))
+	}
+	g.p("}")
+	g.p(`return nil, fmt.Errorf("unknown event index %%d", data[0])`)
+	g.p("}")
+	g.p("")
+	return nil
+}
+
+// decodeEventCase renders the decoding of one event in DecodeEvent
+func (g *generator) decodeEventCase(name string) {
+	g.p("var event %s", name)
+	g.p("if err := decoder.Decode(&event); err != nil {")
+	g.p(`return nil, fmt.Errorf("failed to decode %s: %%v", err)`, name)
+	g.p("}")
+	g.p("return &event, nil")
+}
+
+// goType maps a registry type to a Go type, registering structs as they're found
+func (g *generator) goType(id int) (string, error) {
+	entry, err := g.meta.lookup(id)
+	if err != nil {
+		return "", err
+	}
+	def := entry.Type.Def
+
+	switch {
+	case def.Primitive != "":
+		return primitiveType(def.Primitive)
+
+	case def.Composite != nil:
+		if lastPath(entry) == "AccountId" {
+			return "AccountID", nil
+		}
+		if len(entry.Type.Path) == 0 {
+			return "", fmt.Errorf("anonymous composite type %d is not supported", id)
+		}
+		return g.registerStruct(entry)
+
+	case def.Array != nil:
+		elem, err := g.goType(def.Array.Type)
+		if err != nil {
+			return "", err
+		}
+		if elem == "uint8" {
+			elem = "byte"
+		}
+		return fmt.Sprintf("[%d]%s", def.Array.Len, elem), nil
+
+	case def.Sequence != nil:
+		elem, err := g.goType(def.Sequence.Type)
+		if err != nil {
+			return "", err
+		}
+		if elem == "uint8" {
+			elem = "byte"
+		}
+		return "[]" + elem, nil
+
+	case def.Variant != nil && lastPath(entry) == "Option" && len(entry.Type.Params) == 1 && entry.Type.Params[0].Type != nil:
+		elem, err := g.goType(*entry.Type.Params[0].Type)
+		if err != nil {
+			return "", err
+		}
+		return "*" + elem, nil
+	}
+
+	return "", fmt.Errorf("type %d (%s) is not supported", id, strings.Join(entry.Type.Path, "::"))
+}
+
+// registerStruct names a composite type and resolves its fields
+func (g *generator) registerStruct(entry *inkTypeEntry) (string, error) {
+	if name, exists := g.structs[entry.ID]; exists {
+		return name, nil
+	}
+
+	name := typeName(lastPath(entry))
+	for id, existing := range g.structs {
+		if existing == name {
+			return "", fmt.Errorf("types %d and %d both map to %s", id, entry.ID, name)
+		}
+	}
+
+	g.structs[entry.ID] = name
+	g.order = append(g.order, entry.ID)
+
+	for _, field := range entry.Type.Def.Composite.Fields {
+		if _, err := g.goType(field.Type); err != nil {
+			return "", fmt.Errorf("%s.%s: %v", name, field.Name, err)
+		}
+	}
+
+	return name, nil
+}
+
+// writeStructs renders the composite types messages and events refer to
+func (g *generator) writeStructs() error {
+	ids := append([]int(nil), g.order...)
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		entry, err := g.meta.lookup(id)
+		if err != nil {
+			return err
+		}
+
+		name := g.structs[id]
+		g.p("// %s mirrors %s", name, strings.Join(entry.Type.Path, "::"))
+		if err := g.structType(name, entry.Type.Def.Composite.Fields, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// structType renders a struct with its SCALE codec. Events only need Decode.
+func (g *generator) structType(name string, fields []inkField, withEncode bool) error {
+	type goField struct {
+		name   string
+		goType string
+	}
+
+	var resolved []goField
+	for i, field := range fields {
+		goType, err := g.goType(field.Type)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %v", name, field.Name, err)
+		}
+
+		fieldName := exportedName(field.Name)
+		if field.Name == "" {
+			fieldName = fmt.Sprintf("Field%d", i)
+		}
+		resolved = append(resolved, goField{name: fieldName, goType: goType})
+	}
+
+	g.p("type %s struct {", name)
+	for _, field := range resolved {
+		g.p("%s %s", field.name, field.goType)
+	}
+	g.p("}")
+	g.p("")
+
+	if withEncode {
+		g.p("// Encode implements scale.Encodeable")
+		g.p("func (v %s) Encode(encoder scale.Encoder) error {", name)
+		for _, field := range resolved {
+			if strings.HasPrefix(field.goType, "*") {
+				g.p("if err := encoder.EncodeOption(v.%s != nil, v.%s); err != nil {", field.name, field.name)
+			} else {
+				g.p("if err := encoder.Encode(v.%s); err != nil {", field.name)
+			}
+			g.p("return err")
+			g.p("}")
+		}
+		g.p("return nil")
+		g.p("}")
+		g.p("")
+	}
+
+	g.p("// Decode implements scale.Decodeable")
+	g.p("func (v *%s) Decode(decoder scale.Decoder) error {", name)
+	for _, field := range resolved {
+		if strings.HasPrefix(field.goType, "*") {
+			local := localName(field.name)
+			g.p("var %s %s", local, field.goType[1:])
+			g.p("var has%s bool", field.name)
+			g.p("if err := decoder.DecodeOption(&has%s, &%s); err != nil {", field.name, local)
+			g.p("return err")
+			g.p("}")
+			g.p("if has%s {", field.name)
+			g.p("v.%s = &%s", field.name, local)
+			g.p("}")
+			continue
+		}
+		g.p("if err := decoder.Decode(&v.%s); err != nil {", field.name)
+		g.p("return err")
+		g.p("}")
+	}
+	g.p("return nil")
+	g.p("}")
+	g.p("")
+
+	return nil
+}
+
+// docs renders metadata docs below a declaration's first comment line
+func (g *generator) docs(docs []string) {
+	for _, line := range docs {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			g.p("//")
+			continue
+		}
+		g.p("// %s", line)
+	}
+}
+
+// p writes a line of output
+func (g *generator) p(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+	g.buf.WriteByte('\n')
+}
+
+// primitiveType maps a SCALE primitive to a Go type
+func primitiveType(primitive string) (string, error) {
+	switch primitive {
+	case "bool":
+		return "bool", nil
+	case "str":
+		return "string", nil
+	case "u8", "u16", "u32", "u64":
+		return "uint" + primitive[1:], nil
+	case "i8", "i16", "i32", "i64":
+		return "int" + primitive[1:], nil
+	}
+	return "", fmt.Errorf("primitive %s is not supported", primitive)
+}
+
+// lastPath returns the last segment of a type's path
+func lastPath(entry *inkTypeEntry) string {
+	if len(entry.Type.Path) == 0 {
+		return ""
+	}
+	return entry.Type.Path[len(entry.Type.Path)-1]
+}
+
+// exportedName converts a snake_case label (or Trait::message) to an exported Go name
+func exportedName(label string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(label, func(r rune) bool { return r == '_' || r == ':' }) {
+		if initialism, exists := initialisms[strings.ToLower(part)]; exists {
+			b.WriteString(initialism)
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// localName converts a snake_case label to an unexported Go identifier
+func localName(label string) string {
+	parts := strings.FieldsFunc(label, func(r rune) bool { return r == '_' || r == ':' })
+	if len(parts) == 0 {
+		return "arg"
+	}
+
+	name := strings.ToLower(parts[0]) + exportedName(strings.Join(parts[1:], "_"))
+	if token.IsKeyword(name) {
+		return name + "Arg"
+	}
+	switch name {
+	case "c", "err", "output", "result", "found":
+		return name + "Arg"
+	}
+	return name
+}
+
+// typeName converts a Rust type or event name to a Go type name
+func typeName(name string) string {
+	if initialism, exists := initialisms[strings.ToLower(name)]; exists {
+		return initialism
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// selectorLiteral renders a 0x-prefixed selector as a [4]byte literal
+func selectorLiteral(selector string) (string, error) {
+	literal, err := hexLiteral(selector, 4)
+	if err != nil {
+		return "", err
+	}
+	return "[4]byte" + literal, nil
+}
+
+// hexLiteral renders 0x-prefixed hex of the given length as a composite literal body
+func hexLiteral(value string, length int) (string, error) {
+	data, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+	if err != nil {
+		return "", fmt.Errorf("invalid hex %q: %v", value, err)
+	}
+	if len(data) != length {
+		return "", fmt.Errorf("%q is %d bytes, expected %d", value, len(data), length)
+	}
+
+	parts := make([]string, len(data))
+	for i, b := range data {
+		parts[i] = fmt.Sprintf("0x%02x", b)
+	}
+	return "{" + strings.Join(parts, ", ") + "}", nil
+}
@@ -0,0 +1,46 @@
+// Command contract-bindgen generates typed Go bindings from ink! contract metadata.
+//
+// It's run through go generate in internal/polkadot/bindings:
+//
+//	go run ./cmd/contract-bindgen -metadata attendance_nft.json -out attendance_nft.go -package bindings
+//
+// The generated code relies on the Executor, AccountID and decoding helpers
+// in that package.
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("contract-bindgen: ")
+
+	metadataPath := flag.String("metadata", "attendance_nft.json", "ink! metadata (.json or .contract)")
+	out := flag.String("out", "", "output Go file (stdout if empty)")
+	pkg := flag.String("package", "bindings", "package name of the generated file")
+	flag.Parse()
+
+	metadata, err := loadMetadata(*metadataPath)
+	if err != nil {
+		log.Fatalf("%s: %v", *metadataPath, err)
+	}
+
+	source, err := generate(metadata, *pkg, filepath.Base(*metadataPath))
+	if err != nil {
+		log.Fatalf("failed to generate bindings: %v", err)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(source)
+		return
+	}
+
+	if err := ioutil.WriteFile(*out, source, 0644); err != nil {
+		log.Fatalf("failed to write %s: %v", *out, err)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// inkMetadata is the subset of ink! 4/5 contract metadata the generator reads
+type inkMetadata struct {
+	Contract struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"contract"`
+	Spec struct {
+		Constructors []inkMessage `json:"constructors"`
+		Messages     []inkMessage `json:"messages"`
+		Events       []inkEvent   `json:"events"`
+	} `json:"spec"`
+	Types []inkTypeEntry `json:"types"`
+}
+
+// inkMessage is a constructor or message
+type inkMessage struct {
+	Label      string      `json:"label"`
+	Selector   string      `json:"selector"`
+	Mutates    bool        `json:"mutates"`
+	Args       []inkArg    `json:"args"`
+	ReturnType *inkTypeRef `json:"returnType"`
+	Docs       []string    `json:"docs"`
+}
+
+// inkEvent is a contract event
+type inkEvent struct {
+	Label          string   `json:"label"`
+	Args           []inkArg `json:"args"`
+	Docs           []string `json:"docs"`
+	SignatureTopic *string  `json:"signature_topic"` // ink! 5 only; nil for anonymous events
+}
+
+// inkArg is a message argument or event field
+type inkArg struct {
+	Label   string     `json:"label"`
+	Type    inkTypeRef `json:"type"`
+	Indexed bool       `json:"indexed"`
+}
+
+// inkTypeRef points into the type registry
+type inkTypeRef struct {
+	DisplayName []string `json:"displayName"`
+	Type        int      `json:"type"`
+}
+
+// inkTypeEntry is a scale-info registry entry
+type inkTypeEntry struct {
+	ID   int `json:"id"`
+	Type struct {
+		Path   []string `json:"path"`
+		Params []struct {
+			Name string `json:"name"`
+			Type *int   `json:"type"`
+		} `json:"params"`
+		Def inkTypeDef `json:"def"`
+	} `json:"type"`
+}
+
+// inkTypeDef is the definition of a registry type; exactly one field is set
+type inkTypeDef struct {
+	Primitive string `json:"primitive"`
+	Composite *struct {
+		Fields []inkField `json:"fields"`
+	} `json:"composite"`
+	Variant *struct {
+		Variants []struct {
+			Name   string     `json:"name"`
+			Index  int        `json:"index"`
+			Fields []inkField `json:"fields"`
+		} `json:"variants"`
+	} `json:"variant"`
+	Sequence *struct {
+		Type int `json:"type"`
+	} `json:"sequence"`
+	Array *struct {
+		Len  int `json:"len"`
+		Type int `json:"type"`
+	} `json:"array"`
+	Tuple *[]int `json:"tuple"`
+}
+
+// inkField is a field of a composite type or variant
+type inkField struct {
+	Name     string `json:"name"`
+	Type     int    `json:"type"`
+	TypeName string `json:"typeName"`
+}
+
+// loadMetadata reads ink! metadata from a .json or .contract file
+func loadMetadata(path string) (*inkMetadata, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata: %v", err)
+	}
+
+	var header struct {
+		Version json.RawMessage `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("failed to parse metadata: %v", err)
+	}
+
+	// ink! 4 writes the version as "4", ink! 5 as 5
+	version := strings.Trim(string(header.Version), `"`)
+	if version != "4" && version != "5" {
+		return nil, fmt.Errorf("unsupported metadata version %s, expected ink! 4 or 5", string(header.Version))
+	}
+
+	var metadata inkMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse metadata: %v", err)
+	}
+
+	if len(metadata.Spec.Messages) == 0 {
+		return nil, fmt.Errorf("metadata has no messages")
+	}
+
+	return &metadata, nil
+}
+
+// lookup returns a registry type by ID
+func (m *inkMetadata) lookup(id int) (*inkTypeEntry, error) {
+	if id >= 0 && id < len(m.Types) && m.Types[id].ID == id {
+		return &m.Types[id], nil
+	}
+
+	for i := range m.Types {
+		if m.Types[i].ID == id {
+			return &m.Types[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("type %d not found in registry", id)
+}
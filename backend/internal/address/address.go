@@ -0,0 +1,136 @@
+package address
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vedhavyas/go-subkey/v2"
+)
+
+// Network prefixes accepted for SS58 addresses
+const (
+	PrefixPolkadot  uint16 = 0
+	PrefixKusama    uint16 = 2
+	PrefixGeneric   uint16 = 42
+	PrefixAlephZero uint16 = 42 // Aleph Zero uses the generic Substrate prefix
+
+	// MaxPrefix is the largest prefix SS58 can encode
+	MaxPrefix = 16383
+)
+
+// networks maps network names to their SS58 prefix
+var networks = map[string]uint16{
+	"polkadot":   PrefixPolkadot,
+	"kusama":     PrefixKusama,
+	"substrate":  PrefixGeneric,
+	"generic":    PrefixGeneric,
+	"westend":    PrefixGeneric,
+	"alephzero":  PrefixAlephZero,
+	"aleph-zero": PrefixAlephZero,
+	"azero":      PrefixAlephZero,
+}
+
+// AccountID is a 32-byte Substrate public key
+type AccountID [32]byte
+
+// Hex returns the canonical 0x-prefixed hex form used for storage
+func (a AccountID) Hex() string {
+	return "0x" + hex.EncodeToString(a[:])
+}
+
+// SS58 encodes the account for the given network prefix
+func (a AccountID) SS58(prefix uint16) string {
+	return subkey.SS58Encode(a[:], prefix)
+}
+
+// IsSupportedPrefix reports whether the prefix belongs to a supported network
+func IsSupportedPrefix(prefix uint16) bool {
+	for _, p := range networks {
+		if p == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePrefix parses a numeric prefix or a network name such as "polkadot"
+func ParsePrefix(value string) (uint16, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	if prefix, ok := networks[value]; ok {
+		return prefix, nil
+	}
+
+	n, err := strconv.ParseUint(value, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("unknown network: %s", value)
+	}
+
+	prefix := uint16(n)
+	if !IsSupportedPrefix(prefix) {
+		return 0, fmt.Errorf("unsupported SS58 prefix: %d", prefix)
+	}
+
+	return prefix, nil
+}
+
+// Parse decodes an SS58 address or a 0x-prefixed hex public key.
+// The returned prefix is the SS58 prefix the address was encoded with, or
+// PrefixGeneric for hex input.
+func Parse(value string) (AccountID, uint16, error) {
+	var id AccountID
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return id, 0, fmt.Errorf("address is empty")
+	}
+
+	if strings.HasPrefix(value, "0x") {
+		pubKey, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+		if err != nil {
+			return id, 0, fmt.Errorf("invalid hex address: %w", err)
+		}
+		if len(pubKey) != len(id) {
+			return id, 0, fmt.Errorf("invalid public key length: %d", len(pubKey))
+		}
+		copy(id[:], pubKey)
+		return id, PrefixGeneric, nil
+	}
+
+	prefix, pubKey, err := subkey.SS58Decode(value)
+	if err != nil {
+		return id, 0, fmt.Errorf("invalid SS58 address: %w", err)
+	}
+
+	if !IsSupportedPrefix(prefix) {
+		return id, 0, fmt.Errorf("unsupported SS58 prefix: %d", prefix)
+	}
+
+	if len(pubKey) != len(id) {
+		return id, 0, fmt.Errorf("invalid public key length: %d", len(pubKey))
+	}
+
+	copy(id[:], pubKey)
+	return id, prefix, nil
+}
+
+// Canonical validates an address and returns its canonical storage form
+func Canonical(value string) (string, error) {
+	id, _, err := Parse(value)
+	if err != nil {
+		return "", err
+	}
+	return id.Hex(), nil
+}
+
+// Format re-encodes a stored address for the given prefix. Values that
+// can't be parsed are returned unchanged.
+func Format(value string, prefix uint16) string {
+	id, _, err := Parse(value)
+	if err != nil {
+		return value
+	}
+	return id.SS58(prefix)
+}
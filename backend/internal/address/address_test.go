@@ -0,0 +1,113 @@
+package address
+
+import "testing"
+
+// Alice's development account in its hex and SS58 forms
+const (
+	aliceHex      = "0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d"
+	aliceGeneric  = "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY"
+	alicePolkadot = "15oF4uVJwmo4TdGW7VfQxNLavjCXviqxT9S1MgbjMNHr6Sp5"
+	aliceKusama   = "HNZata7iMYWmk5RvZRTiAsSDhV8366zq2YGb3tLH5Upf74F"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		prefix uint16
+		ok     bool
+	}{
+		{"hex", aliceHex, PrefixGeneric, true},
+		{"generic", aliceGeneric, PrefixGeneric, true},
+		{"polkadot", alicePolkadot, PrefixPolkadot, true},
+		{"kusama", aliceKusama, PrefixKusama, true},
+		{"surrounding whitespace", "  " + aliceGeneric + "\n", PrefixGeneric, true},
+		{"empty", "", 0, false},
+		{"blank", "   ", 0, false},
+		{"short hex", "0xd43593c7", 0, false},
+		{"invalid hex", "0x" + "zz" + aliceHex[4:], 0, false},
+		{"bad checksum", aliceGeneric[:len(aliceGeneric)-1] + "Z", 0, false},
+		{"not an address", "alice", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, prefix, err := Parse(tt.value)
+			if !tt.ok {
+				if err == nil {
+					t.Fatalf("Parse(%q) succeeded, want an error", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.value, err)
+			}
+			if id.Hex() != aliceHex {
+				t.Errorf("Parse(%q) = %s, want %s", tt.value, id.Hex(), aliceHex)
+			}
+			if prefix != tt.prefix {
+				t.Errorf("Parse(%q) prefix = %d, want %d", tt.value, prefix, tt.prefix)
+			}
+		})
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	for _, value := range []string{aliceHex, aliceGeneric, alicePolkadot, aliceKusama} {
+		canonical, err := Canonical(value)
+		if err != nil {
+			t.Fatalf("Canonical(%q) failed: %v", value, err)
+		}
+		if canonical != aliceHex {
+			t.Errorf("Canonical(%q) = %s, want %s", value, canonical, aliceHex)
+		}
+	}
+
+	if _, err := Canonical("not an address"); err == nil {
+		t.Error("Canonical accepted an invalid address")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		value  string
+		prefix uint16
+		want   string
+	}{
+		{aliceHex, PrefixGeneric, aliceGeneric},
+		{aliceHex, PrefixPolkadot, alicePolkadot},
+		{aliceGeneric, PrefixKusama, aliceKusama},
+		{"not an address", PrefixPolkadot, "not an address"},
+	}
+
+	for _, tt := range tests {
+		if got := Format(tt.value, tt.prefix); got != tt.want {
+			t.Errorf("Format(%q, %d) = %s, want %s", tt.value, tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestParsePrefix(t *testing.T) {
+	tests := []struct {
+		value string
+		want  uint16
+		ok    bool
+	}{
+		{"polkadot", PrefixPolkadot, true},
+		{" Kusama ", PrefixKusama, true},
+		{"42", PrefixGeneric, true},
+		{"0", PrefixPolkadot, true},
+		{"7", 0, false},
+		{"westend-asset-hub", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, err := ParsePrefix(tt.value)
+		if tt.ok && (err != nil || got != tt.want) {
+			t.Errorf("ParsePrefix(%q) = %d, %v; want %d", tt.value, got, err, tt.want)
+		}
+		if !tt.ok && err == nil {
+			t.Errorf("ParsePrefix(%q) succeeded, want an error", tt.value)
+		}
+	}
+}
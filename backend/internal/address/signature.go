@@ -0,0 +1,32 @@
+package address
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/vedhavyas/go-subkey/v2/sr25519"
+)
+
+// VerifySignature checks an sr25519 signature of message by the account at
+// address. Browser wallets sign raw messages wrapped in <Bytes>...</Bytes>,
+// so the wrapped message is accepted too. The signature is hex encoded, with
+// or without a 0x prefix.
+func VerifySignature(address, message, signature string) bool {
+	account, _, err := Parse(address)
+	if err != nil {
+		return false
+	}
+
+	sig, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), "0x"))
+	if err != nil || len(sig) != 64 {
+		return false
+	}
+
+	key, err := sr25519.Scheme{}.FromPublicKey(account[:])
+	if err != nil {
+		return false
+	}
+
+	return key.Verify([]byte(message), sig) ||
+		key.Verify([]byte("<Bytes>"+message+"</Bytes>"), sig)
+}
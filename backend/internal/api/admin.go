@@ -1,49 +1,113 @@
 package api
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/checkin"
 	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/luma"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/minting"
 	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
 	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/polkadot"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/providers"
 )
 
 // AdminHandler handles admin API endpoints
 type AdminHandler struct {
-	polkadotClient *polkadot.Client
-	eventRepo      *database.EventRepository
-	nftRepo        *database.NFTRepository
-	userRepo       *database.UserRepository
-	permRepo       *database.PermissionRepository
+	chain      polkadot.AttendanceChain
+	eventRepo  *database.EventRepository
+	nftRepo    *database.NFTRepository
+	userRepo   *database.UserRepository
+	permRepo   *database.PermissionRepository
+	claimRepo  *database.ClaimRepository
+	mintQueue  *minting.Queue
+	lumaSync   *luma.Syncer
+	checkIns   *checkin.Service
+	sourceRepo *database.EventSourceRepository
+	providers  *providers.Registry
+	policyRepo *database.ClaimPolicyRepository
 }
 
 // NewAdminHandler creates a new admin API handler
 func NewAdminHandler(
-	polkadotClient *polkadot.Client,
+	chain polkadot.AttendanceChain,
 	eventRepo *database.EventRepository,
 	nftRepo *database.NFTRepository,
 	userRepo *database.UserRepository,
 	permRepo *database.PermissionRepository,
+	claimRepo *database.ClaimRepository,
+	mintQueue *minting.Queue,
+	lumaSync *luma.Syncer,
+	checkIns *checkin.Service,
+	sourceRepo *database.EventSourceRepository,
+	registry *providers.Registry,
+	policyRepo *database.ClaimPolicyRepository,
 ) *AdminHandler {
 	return &AdminHandler{
-		polkadotClient: polkadotClient,
-		eventRepo:      eventRepo,
-		nftRepo:        nftRepo,
-		userRepo:       userRepo,
-		permRepo:       permRepo,
+		chain:      chain,
+		eventRepo:  eventRepo,
+		nftRepo:    nftRepo,
+		userRepo:   userRepo,
+		permRepo:   permRepo,
+		claimRepo:  claimRepo,
+		mintQueue:  mintQueue,
+		lumaSync:   lumaSync,
+		checkIns:   checkIns,
+		sourceRepo: sourceRepo,
+		providers:  registry,
+		policyRepo: policyRepo,
 	}
 }
 
-// EventRequest represents a request to create an event
+// EventRequest represents a request to create an event. Either date or
+// starts_at must be given.
 type EventRequest struct {
-	Name     string `json:"name" binding:"required,min=3,max=100"`
-	Date     string `json:"date" binding:"required"`
-	Location string `json:"location" binding:"required,min=2,max=100"`
+	Name      string `json:"name" binding:"required,min=3,max=100"`
+	Date      string `json:"date"`
+	Location  string `json:"location" binding:"omitempty,min=2,max=100"`
 	Organizer string `json:"organizer"`
+	// Transferable defaults to true; false makes the event's badges soulbound
+	Transferable *bool `json:"transferable"`
+	EventDetails
+}
+
+// EventDetails holds the schedule and descriptive fields of an event request
+type EventDetails struct {
+	// StartsAt and EndsAt are RFC 3339 timestamps; an event with only a date
+	// lasts that whole day in its time zone
+	StartsAt      *time.Time `json:"starts_at"`
+	EndsAt        *time.Time `json:"ends_at"`
+	TimeZone      string     `json:"time_zone"` // IANA name, defaults to UTC
+	Type          string     `json:"type"`      // physical (default), online or hybrid
+	Description   string     `json:"description" binding:"max=5000"`
+	CoverImageURL string     `json:"cover_image_url"`
+	LogoURL       string     `json:"logo_url"`
+	URL           string     `json:"url"`
+	Capacity      *int       `json:"capacity" binding:"omitempty,min=0"` // 0 or omitted for unlimited
+}
+
+// apply copies the details onto an event
+func (d EventDetails) apply(event *models.Event) {
+	event.StartsAt = d.StartsAt
+	event.EndsAt = d.EndsAt
+	event.TimeZone = d.TimeZone
+	event.Type = d.Type
+	event.Description = d.Description
+	event.CoverImageURL = d.CoverImageURL
+	event.LogoURL = d.LogoURL
+	event.URL = d.URL
+	event.Capacity = d.Capacity
+	if d.Capacity != nil && *d.Capacity == 0 {
+		event.Capacity = nil
+	}
 }
 
 // validateDate checks if a date string is valid
@@ -52,6 +116,55 @@ func validateDate(date string) bool {
 	return err == nil
 }
 
+// validateEvent checks the editable fields of an event and fills in the
+// defaults for its schedule, type and location
+func validateEvent(event *models.Event) error {
+	event.Name = strings.TrimSpace(event.Name)
+	event.Location = strings.TrimSpace(event.Location)
+
+	if event.Type == "" {
+		event.Type = models.EventPhysical
+	}
+	switch event.Type {
+	case models.EventPhysical, models.EventHybrid:
+	case models.EventOnline:
+		if event.Location == "" {
+			event.Location = "Online"
+		}
+	default:
+		return fmt.Errorf("type must be physical, online or hybrid")
+	}
+
+	if event.Name == "" || event.Location == "" {
+		return fmt.Errorf("name and location cannot be empty or just whitespace")
+	}
+
+	if event.Date == "" && event.StartsAt == nil {
+		return fmt.Errorf("either date or starts_at is required")
+	}
+	if err := event.NormalizeSchedule(); err != nil {
+		return err
+	}
+
+	for name, value := range map[string]string{"cover_image_url": event.CoverImageURL, "logo_url": event.LogoURL, "url": event.URL} {
+		if value != "" && !validWebURL(value) {
+			return fmt.Errorf("%s must be an http or https URL", name)
+		}
+	}
+
+	if event.Capacity != nil && *event.Capacity < 0 {
+		return fmt.Errorf("capacity cannot be negative")
+	}
+
+	return nil
+}
+
+// validWebURL reports whether value is an absolute http(s) URL
+func validWebURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
 // CreateEvent creates a new event
 func (h *AdminHandler) CreateEvent(c *gin.Context) {
 	var req EventRequest
@@ -60,17 +173,6 @@ func (h *AdminHandler) CreateEvent(c *gin.Context) {
 		return
 	}
 
-	// Additional validation
-	if !validateDate(req.Date) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
-		return
-	}
-
-	if strings.TrimSpace(req.Name) == "" || strings.TrimSpace(req.Location) == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Name and location cannot be empty or just whitespace"})
-		return
-	}
-
 	// Get wallet address from request or use a default
 	organizer := req.Organizer
 	if organizer == "" {
@@ -78,62 +180,96 @@ func (h *AdminHandler) CreateEvent(c *gin.Context) {
 		organizer = "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY"
 	}
 
-	// Create event in database
+	organizer, err := address.Canonical(organizer)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organizer address: " + err.Error()})
+		return
+	}
+
+	transferable := true
+	if req.Transferable != nil {
+		transferable = *req.Transferable
+	}
+
 	event := &models.Event{
-		Name:      req.Name,
-		Date:      req.Date,
-		Location:  req.Location,
-		Organizer: organizer,
+		Name:         req.Name,
+		Date:         req.Date,
+		Location:     req.Location,
+		Organizer:    organizer,
+		Transferable: transferable,
 	}
+	req.EventDetails.apply(event)
 
-	if err := h.eventRepo.Create(event); err != nil {
+	if err := validateEvent(event); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Create event in database
+	if err := h.eventRepo.Create(c.Request.Context(), event); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Also create the event in the blockchain
-	eventID, err := h.polkadotClient.CreateEvent(req.Name, req.Date, req.Location)
-	if err != nil {
-		// Log the error but continue (we have the event in the database)
-		c.Error(err)
-	} else {
-		// If blockchain creation succeeds, update the event ID if needed
-		if event.ID != eventID && eventID > 0 {
-			// In a production system, we would handle this discrepancy
-			c.Error(err)
-		}
+	if err := h.registerEvent(c, event); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
 	}
 
-	// Get or create the organizer user
-	user, err := h.userRepo.GetOrCreate(organizer)
-	if err != nil {
-		// Log the error but continue
-		c.Error(err)
-	} else {
-		// Give the user owner permissions for the event
-		perm := &database.EventPermission{
-			EventID: event.ID,
-			UserID:  user.ID,
-			Role:    database.RoleOwner,
-		}
-		if err := h.permRepo.Create(perm); err != nil {
-			// Log the error but continue
-			c.Error(err)
+	c.JSON(http.StatusOK, formatEvent(c, *event))
+}
+
+// registerEvent creates a stored event on the blockchain and makes its
+// organizer the owner. If the chain rejects the event, it's deleted from the
+// database again, since no NFT could be minted for it; the request can then
+// be retried.
+func (h *AdminHandler) registerEvent(c *gin.Context, event *models.Event) error {
+	ctx := c.Request.Context()
+
+	// The chain client records the ID the chain assigns to the event
+	if _, err := h.chain.CreateEvent(ctx, event.ID, event.Name, event.Date, event.Location, event.Transferable); err != nil {
+		// The event may not be left behind even if the request was cancelled
+		if _, deleteErr := h.eventRepo.Delete(context.WithoutCancel(ctx), event.ID, 0); deleteErr != nil {
+			c.Error(deleteErr)
 		}
+		return fmt.Errorf("failed to register event on chain: %v", err)
 	}
 
-	c.JSON(http.StatusOK, event)
+	h.grantOwner(c, event.ID, event.Organizer)
+	return nil
 }
 
-// ListEvents lists all events
+// ListEvents lists a page of events, filtered by the from, to, location and
+// organizer query parameters
 func (h *AdminHandler) ListEvents(c *gin.Context) {
-	events, err := h.eventRepo.GetAll()
+	page, ok := pageFromQuery(c)
+	if !ok {
+		return
+	}
+
+	filter := database.EventFilter{
+		From:     c.Query("from"),
+		To:       c.Query("to"),
+		Location: c.Query("location"),
+	}
+	for _, date := range []string{filter.From, filter.To} {
+		if date != "" && !validateDate(date) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+			return
+		}
+	}
+	if filter.Organizer, ok = addressFromQuery(c, "organizer"); !ok {
+		return
+	}
+
+	events, info, err := h.eventRepo.List(c.Request.Context(), filter, page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondListError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, events)
+	setPageHeaders(c, info)
+	c.JSON(http.StatusOK, formatEvents(c, events))
 }
 
 // GetEvent gets an event by ID
@@ -145,27 +281,54 @@ func (h *AdminHandler) GetEvent(c *gin.Context) {
 		return
 	}
 
-	event, err := h.eventRepo.GetByID(id)
+	event, err := h.eventRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	if event == nil {
+	if event == nil || event.Status == models.EventDeleted {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, event)
+	c.Header("ETag", eventETag(event))
+	c.JSON(http.StatusOK, formatEvent(c, *event))
 }
 
-// ListNFTs lists all NFTs
+// ListNFTs lists a page of NFTs, including burned ones, filtered by the
+// event_id, owner and confirmed query parameters
 func (h *AdminHandler) ListNFTs(c *gin.Context) {
-	nfts, err := h.nftRepo.GetAll()
+	page, ok := pageFromQuery(c)
+	if !ok {
+		return
+	}
+
+	filter, ok := nftFilterFromQuery(c)
+	if !ok {
+		return
+	}
+	if filter.Owner, ok = addressFromQuery(c, "owner"); !ok {
+		return
+	}
+
+	nfts, info, err := h.nftRepo.List(c.Request.Context(), filter, page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondListError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, nfts)
-}
\ No newline at end of file
+	setPageHeaders(c, info)
+	c.JSON(http.StatusOK, formatNFTs(c, nfts))
+}
+
+// GetTxStatus reports the status of an extrinsic submitted by the backend
+func (h *AdminHandler) GetTxStatus(c *gin.Context) {
+	status, err := h.chain.TxStatus(c.Request.Context(), c.Param("hash"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
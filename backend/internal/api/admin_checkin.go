@@ -0,0 +1,134 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/checkin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// CheckInSettingsRequest configures QR check-in for an event
+type CheckInSettingsRequest struct {
+	Enabled       bool `json:"enabled"`
+	PeriodSeconds int  `json:"period_seconds"` // defaults to 30
+	// Set all three for a geofence, or none to allow check-in from anywhere
+	Latitude     *float64 `json:"latitude"`
+	Longitude    *float64 `json:"longitude"`
+	RadiusMeters *float64 `json:"radius_meters"`
+}
+
+// CheckInCodeResponse is the code an organizer displays at the event
+type CheckInCodeResponse struct {
+	Code       string    `json:"code"`
+	ValidUntil time.Time `json:"valid_until"`
+	ExpiresIn  int       `json:"expires_in"` // seconds until the code changes
+	// QRPayload is the check-in link to encode in the QR code
+	QRPayload string `json:"qr_payload"`
+}
+
+// GetCheckInSettings shows an event's QR check-in settings and number of check-ins
+func (h *AdminHandler) GetCheckInSettings(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.checkIns.Settings(c.Request.Context(), event.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if settings == nil {
+		settings = &models.CheckInSettings{EventID: event.ID}
+	}
+
+	count, err := h.checkIns.Count(c.Request.Context(), event.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"settings":  settings,
+		"check_ins": count,
+	})
+}
+
+// UpdateCheckInSettings turns QR check-in on or off for an event and sets
+// its code period and geofence
+func (h *AdminHandler) UpdateCheckInSettings(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	var req CheckInSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings := &models.CheckInSettings{
+		EventID:       event.ID,
+		Enabled:       req.Enabled,
+		PeriodSeconds: req.PeriodSeconds,
+		Latitude:      req.Latitude,
+		Longitude:     req.Longitude,
+		RadiusMeters:  req.RadiusMeters,
+	}
+
+	if err := h.checkIns.Configure(c.Request.Context(), settings); err != nil {
+		respondCheckInError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// GetCheckInCode returns the current check-in code of an event for display
+func (h *AdminHandler) GetCheckInCode(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.checkIns.Settings(c.Request.Context(), event.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if settings == nil || !settings.Enabled {
+		respondCheckInError(c, checkin.ErrNotEnabled)
+		return
+	}
+
+	now := time.Now()
+	code, validUntil, link := h.checkIns.CurrentCode(settings, now)
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, CheckInCodeResponse{
+		Code:       code,
+		ValidUntil: validUntil,
+		ExpiresIn:  int(validUntil.Sub(now).Seconds()),
+		QRPayload:  link,
+	})
+}
+
+// RotateCheckInSecret replaces an event's code secret, invalidating every
+// code issued so far
+func (h *AdminHandler) RotateCheckInSecret(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.checkIns.Rotate(c.Request.Context(), event.ID)
+	if err != nil {
+		respondCheckInError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/claims"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// maxAllowlistBatch caps how many wallets can be added to an allowlist at once
+const maxAllowlistBatch = 5000
+
+// ClaimPolicyRequest sets how an event's badge can be claimed without checking in
+type ClaimPolicyRequest struct {
+	Mode string `json:"mode" binding:"required"` // webhook, code, allowlist or open
+	// Code is the secret code in code mode. It can be left out to keep the current code.
+	Code      string     `json:"code" binding:"max=100"`
+	MaxClaims *int       `json:"max_claims"` // omitted or 0 for unlimited
+	OpensAt   *time.Time `json:"opens_at"`
+	ClosesAt  *time.Time `json:"closes_at"`
+}
+
+// AllowlistRequest adds wallets to an event's allowlist
+type AllowlistRequest struct {
+	Wallets []string `json:"wallets" binding:"required,min=1"`
+}
+
+// GetClaimPolicy shows an event's claim policy and how many badges were claimed under it
+func (h *AdminHandler) GetClaimPolicy(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	claimed, err := h.policyRepo.CountByEvent(c.Request.Context(), event.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"claim_policy": event.ClaimPolicy,
+		"has_code":     event.ClaimPolicy.CodeHash != "",
+		"claimed":      claimed,
+	})
+}
+
+// SetClaimPolicy replaces an event's claim policy
+func (h *AdminHandler) SetClaimPolicy(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	var req ClaimPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	policy := models.ClaimPolicy{
+		Mode:      strings.ToLower(strings.TrimSpace(req.Mode)),
+		MaxClaims: req.MaxClaims,
+		OpensAt:   req.OpensAt,
+		ClosesAt:  req.ClosesAt,
+	}
+	if policy.MaxClaims != nil && *policy.MaxClaims == 0 {
+		policy.MaxClaims = nil
+	}
+	if policy.Mode == models.ClaimModeCode {
+		policy.CodeHash = event.ClaimPolicy.CodeHash
+		if strings.TrimSpace(req.Code) != "" {
+			policy.CodeHash = claims.HashCode(event.ID, req.Code)
+		}
+	}
+
+	if err := claims.ValidatePolicy(&policy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	event.ClaimPolicy = policy
+	if err := h.eventRepo.SetClaimPolicy(c.Request.Context(), event); err != nil {
+		if errors.Is(err, database.ErrEventNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, formatEvent(c, *event))
+}
+
+// GetAllowlist lists the wallets on an event's allowlist
+func (h *AdminHandler) GetAllowlist(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	wallets, err := h.policyRepo.GetAllowlist(c.Request.Context(), event.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	for i, wallet := range wallets {
+		wallets[i] = address.Format(wallet, outputPrefix(c))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"wallets": wallets})
+}
+
+// AddToAllowlist adds wallets to an event's allowlist. Invalid addresses are
+// reported and skipped.
+func (h *AdminHandler) AddToAllowlist(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	var req AllowlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.Wallets) > maxAllowlistBatch {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many wallets in one request"})
+		return
+	}
+
+	wallets := make([]string, 0, len(req.Wallets))
+	invalid := []string{}
+	for _, wallet := range req.Wallets {
+		canonical, err := address.Canonical(strings.TrimSpace(wallet))
+		if err != nil {
+			invalid = append(invalid, wallet)
+			continue
+		}
+		wallets = append(wallets, canonical)
+	}
+
+	added, err := h.policyRepo.AddToAllowlist(c.Request.Context(), event.ID, wallets)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"added":   added,
+		"invalid": invalid,
+	})
+}
+
+// RemoveFromAllowlist removes a wallet from an event's allowlist
+func (h *AdminHandler) RemoveFromAllowlist(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	wallet, err := address.Canonical(c.Param("wallet"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wallet address: " + err.Error()})
+		return
+	}
+
+	removed, err := h.policyRepo.RemoveFromAllowlist(c.Request.Context(), event.ID, wallet)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !removed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet is not on the allowlist"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClaimCounts counts the unclaimed, expired and claimed claims of each
+// event, or of the event given by the event_id query parameter
+func (h *AdminHandler) ClaimCounts(c *gin.Context) {
+	var eventID uint64
+	if eventIDStr := c.Query("event_id"); eventIDStr != "" {
+		var err error
+		if eventID, err = strconv.ParseUint(eventIDStr, 10, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+			return
+		}
+	}
+
+	counts, err := h.claimRepo.CountByEvent(c.Request.Context(), eventID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, counts)
+}
+
+// ListUnclaimed lists the claims of an event that haven't been claimed yet
+func (h *AdminHandler) ListUnclaimed(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	claims, err := h.claimRepo.GetUnclaimedByEvent(c.Request.Context(), event.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, claims)
+}
@@ -0,0 +1,362 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// EventUpdateRequest replaces the editable fields of an event. Either date or
+// starts_at must be given; omitted details are cleared.
+type EventUpdateRequest struct {
+	Name         string `json:"name" binding:"required,min=3,max=100"`
+	Date         string `json:"date"`
+	Location     string `json:"location" binding:"omitempty,min=2,max=100"`
+	Organizer    string `json:"organizer" binding:"required"`
+	Transferable *bool  `json:"transferable" binding:"required"`
+	// Status is models.EventActive or models.EventCancelled; empty keeps the current one
+	Status string `json:"status"`
+	// Version is the version the change is based on; the If-Match header may be used instead
+	Version int `json:"version"`
+	EventDetails
+}
+
+// EventPatchRequest changes some fields of an event
+type EventPatchRequest struct {
+	Name         *string `json:"name" binding:"omitempty,min=3,max=100"`
+	Date         *string `json:"date"`
+	Location     *string `json:"location" binding:"omitempty,min=2,max=100"`
+	Organizer    *string `json:"organizer"`
+	Transferable *bool   `json:"transferable"`
+	Status       *string `json:"status"`
+	Version      int     `json:"version"`
+	// Changing date without starts_at moves the event to that whole day
+	StartsAt      *time.Time `json:"starts_at"`
+	EndsAt        *time.Time `json:"ends_at"`
+	TimeZone      *string    `json:"time_zone"`
+	Type          *string    `json:"type"`
+	Description   *string    `json:"description" binding:"omitempty,max=5000"`
+	CoverImageURL *string    `json:"cover_image_url"`
+	LogoURL       *string    `json:"logo_url"`
+	URL           *string    `json:"url"`
+	// Capacity of 0 removes the limit
+	Capacity *int `json:"capacity" binding:"omitempty,min=0"`
+}
+
+// UpdateEvent replaces an event's editable fields
+func (h *AdminHandler) UpdateEvent(c *gin.Context) {
+	var req EventUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	current, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	updated := *current
+	updated.Name = req.Name
+	updated.Date = req.Date
+	updated.Location = req.Location
+	updated.Organizer = req.Organizer
+	updated.Transferable = *req.Transferable
+	if req.Status != "" {
+		updated.Status = req.Status
+	}
+	req.EventDetails.apply(&updated)
+
+	h.saveEvent(c, current, &updated, req.Version)
+}
+
+// PatchEvent changes the fields of an event present in the request
+func (h *AdminHandler) PatchEvent(c *gin.Context) {
+	var req EventPatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	current, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	updated := *current
+	if req.Name != nil {
+		updated.Name = *req.Name
+	}
+	if req.Date != nil {
+		updated.Date = *req.Date
+		updated.StartsAt, updated.EndsAt = nil, nil
+	}
+	if req.StartsAt != nil {
+		updated.StartsAt = req.StartsAt
+		// The end is recomputed unless it's sent too
+		updated.EndsAt = nil
+	}
+	if req.EndsAt != nil {
+		updated.EndsAt = req.EndsAt
+	}
+	if req.TimeZone != nil {
+		updated.TimeZone = *req.TimeZone
+	}
+	if req.Type != nil {
+		updated.Type = *req.Type
+	}
+	if req.Description != nil {
+		updated.Description = *req.Description
+	}
+	if req.CoverImageURL != nil {
+		updated.CoverImageURL = *req.CoverImageURL
+	}
+	if req.LogoURL != nil {
+		updated.LogoURL = *req.LogoURL
+	}
+	if req.URL != nil {
+		updated.URL = *req.URL
+	}
+	if req.Capacity != nil {
+		updated.Capacity = req.Capacity
+		if *req.Capacity == 0 {
+			updated.Capacity = nil
+		}
+	}
+	if req.Location != nil {
+		updated.Location = *req.Location
+	}
+	if req.Organizer != nil {
+		updated.Organizer = *req.Organizer
+	}
+	if req.Transferable != nil {
+		updated.Transferable = *req.Transferable
+	}
+	if req.Status != nil {
+		updated.Status = *req.Status
+	}
+
+	h.saveEvent(c, current, &updated, req.Version)
+}
+
+// DeleteEvent deletes an event. Events with NFTs are soft-deleted so the
+// NFTs keep their event; the response tells which happened.
+func (h *AdminHandler) DeleteEvent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	// The version check is optional for deletes
+	version := 0
+	if versionStr := c.Query("version"); versionStr != "" {
+		if version, err = strconv.Atoi(versionStr); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid version"})
+			return
+		}
+	}
+	if version == 0 {
+		var ok bool
+		if version, ok = ifMatchVersion(c); !ok {
+			return
+		}
+	}
+
+	soft, err := h.eventRepo.Delete(c.Request.Context(), id, version)
+	if err != nil {
+		respondEventError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":           id,
+		"deleted":      true,
+		"soft_deleted": soft,
+	})
+}
+
+// loadEvent reads the event named by the id path parameter, responding with
+// an error if it's invalid, missing or deleted
+func (h *AdminHandler) loadEvent(c *gin.Context) (*models.Event, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return nil, false
+	}
+
+	event, err := h.eventRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	if event == nil || event.Status == models.EventDeleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return nil, false
+	}
+
+	return event, true
+}
+
+// saveEvent validates an edited event and stores it if current is still the
+// latest version. version comes from the request body or, if zero, If-Match.
+func (h *AdminHandler) saveEvent(c *gin.Context, current, updated *models.Event, version int) {
+	if version == 0 {
+		var ok bool
+		if version, ok = ifMatchVersion(c); !ok {
+			return
+		}
+	}
+	if version == 0 {
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": "Send the event version in the body or an If-Match header"})
+		return
+	}
+	if version != current.Version {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": database.ErrVersionConflict.Error(),
+			"event": formatEvent(c, *current),
+		})
+		return
+	}
+
+	// Validation matches CreateEvent
+	if err := validateEvent(updated); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	organizer, err := address.Canonical(updated.Organizer)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organizer address: " + err.Error()})
+		return
+	}
+	updated.Organizer = organizer
+
+	if updated.Status != models.EventActive && updated.Status != models.EventCancelled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Status must be active or cancelled"})
+		return
+	}
+
+	// The chain stores the transfer policy when the event is registered, so
+	// it's fixed once the event is on chain or any badge has been minted
+	if updated.Transferable != current.Transferable {
+		onChain, err := h.registeredOnChain(c, current.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if onChain {
+			c.JSON(http.StatusConflict, gin.H{"error": "Transferability can't change after the event is registered on chain"})
+			return
+		}
+
+		count, err := h.nftRepo.CountByEventID(c.Request.Context(), current.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if count > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Transferability can't change after NFTs have been minted"})
+			return
+		}
+	}
+
+	updated.Version = version
+	if err := h.eventRepo.Update(c.Request.Context(), updated); err != nil {
+		respondEventError(c, err)
+		return
+	}
+
+	// The new organizer owns the event; the previous one keeps their permission
+	if updated.Organizer != current.Organizer {
+		h.grantOwner(c, updated.ID, updated.Organizer)
+	}
+
+	c.Header("ETag", eventETag(updated))
+	c.JSON(http.StatusOK, formatEvent(c, *updated))
+}
+
+// grantOwner gives a wallet owner permissions for an event. Failures are
+// recorded on the context without failing the request.
+func (h *AdminHandler) grantOwner(c *gin.Context, eventID uint64, wallet string) {
+	ctx := c.Request.Context()
+
+	user, err := h.userRepo.GetOrCreate(ctx, wallet)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	role, err := h.permRepo.GetUserRoleForEvent(ctx, user.ID, eventID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	switch role {
+	case database.RoleOwner:
+	case "":
+		perm := &database.EventPermission{EventID: eventID, UserID: user.ID, Role: database.RoleOwner}
+		if err := h.permRepo.Create(ctx, perm); err != nil {
+			c.Error(err)
+		}
+	default:
+		if err := h.permRepo.UpdateRole(ctx, user.ID, eventID, database.RoleOwner); err != nil {
+			c.Error(err)
+		}
+	}
+}
+
+// respondEventError maps event repository errors to a response
+func respondEventError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, database.ErrEventNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+	case errors.Is(err, database.ErrVersionConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// eventETag is the entity tag of an event version
+func eventETag(event *models.Event) string {
+	return `"` + strconv.Itoa(event.Version) + `"`
+}
+
+// ifMatchVersion reads the event version from the If-Match header, or 0 if
+// the header is absent. It responds with 400 and returns false if it's invalid.
+func ifMatchVersion(c *gin.Context) (int, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		return 0, true
+	}
+
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(header, "W/"), `"`))
+	if err != nil || version <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "If-Match must hold an event version"})
+		return 0, false
+	}
+	return version, true
+}
+
+// registeredOnChain reports whether an event has been created on the
+// contract or as a pallet-nfts collection
+func (h *AdminHandler) registeredOnChain(c *gin.Context, eventID uint64) (bool, error) {
+	ctx := c.Request.Context()
+
+	if _, found, err := h.eventRepo.GetChainEventID(ctx, eventID); err != nil || found {
+		return found, err
+	}
+
+	_, found, err := h.eventRepo.GetCollectionID(ctx, eventID)
+	return found, err
+}
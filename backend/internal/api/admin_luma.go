@@ -0,0 +1,179 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/luma"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// LumaImportRequest selects the Luma events to import
+type LumaImportRequest struct {
+	// EventIDs are Luma event IDs; empty imports every calendar event not yet imported
+	EventIDs  []string `json:"event_ids"`
+	Organizer string   `json:"organizer" binding:"required"`
+	// Transferable defaults to true; false makes the events' badges soulbound
+	Transferable *bool `json:"transferable"`
+}
+
+// LumaEventResponse is a Luma calendar event and the local event imported from it
+type LumaEventResponse struct {
+	luma.Event
+	ImportedEventID uint64 `json:"imported_event_id,omitempty"`
+}
+
+// LumaImportSkip explains why a Luma event wasn't imported
+type LumaImportSkip struct {
+	LumaEventID string `json:"luma_event_id"`
+	EventID     uint64 `json:"event_id,omitempty"`
+	Reason      string `json:"reason"`
+}
+
+// ListLumaEvents lists the events of the Luma calendar and which of them are imported
+func (h *AdminHandler) ListLumaEvents(c *gin.Context) {
+	if !h.lumaConfigured(c) {
+		return
+	}
+
+	lumaEvents, err := h.lumaSync.Client().AllCalendarEvents(c.Request.Context())
+	if err != nil {
+		respondLumaError(c, "Failed to list Luma events", err)
+		return
+	}
+
+	imported, err := h.importedLumaEvents(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]LumaEventResponse, 0, len(lumaEvents))
+	for _, lumaEvent := range lumaEvents {
+		response = append(response, LumaEventResponse{
+			Event:           lumaEvent,
+			ImportedEventID: imported[lumaEvent.ID],
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ImportLumaEvents creates linked local events for Luma calendar events and
+// registers them on chain like created events
+func (h *AdminHandler) ImportLumaEvents(c *gin.Context) {
+	var req LumaImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !h.lumaConfigured(c) {
+		return
+	}
+
+	organizer, err := address.Canonical(req.Organizer)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organizer address: " + err.Error()})
+		return
+	}
+
+	transferable := true
+	if req.Transferable != nil {
+		transferable = *req.Transferable
+	}
+
+	ctx := c.Request.Context()
+	client := h.lumaSync.Client()
+
+	// Without explicit IDs, import the whole calendar
+	var lumaEvents []luma.Event
+	if len(req.EventIDs) == 0 {
+		lumaEvents, err = client.AllCalendarEvents(ctx)
+		if err != nil {
+			respondLumaError(c, "Failed to list Luma events", err)
+			return
+		}
+	}
+
+	skipped := []LumaImportSkip{}
+	for _, lumaEventID := range req.EventIDs {
+		lumaEvent, err := client.GetLumaEvent(ctx, lumaEventID)
+		if errors.Is(err, luma.ErrNotFound) {
+			skipped = append(skipped, LumaImportSkip{LumaEventID: lumaEventID, Reason: "not found in Luma"})
+			continue
+		}
+		if err != nil {
+			respondLumaError(c, "Failed to get Luma event", err)
+			return
+		}
+		lumaEvents = append(lumaEvents, *lumaEvent)
+	}
+
+	imported := []models.Event{}
+	for i := range lumaEvents {
+		lumaEvent := &lumaEvents[i]
+
+		event, err := h.lumaSync.Import(ctx, lumaEvent, organizer, transferable)
+		if errors.Is(err, luma.ErrAlreadyImported) {
+			skipped = append(skipped, LumaImportSkip{LumaEventID: lumaEvent.ID, EventID: event.ID, Reason: err.Error()})
+			continue
+		}
+		if err != nil {
+			skipped = append(skipped, LumaImportSkip{LumaEventID: lumaEvent.ID, Reason: err.Error()})
+			continue
+		}
+
+		if err := h.registerEvent(c, event); err != nil {
+			skipped = append(skipped, LumaImportSkip{LumaEventID: lumaEvent.ID, Reason: err.Error()})
+			continue
+		}
+		imported = append(imported, formatEvent(c, *event))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"imported": imported,
+		"skipped":  skipped,
+	})
+}
+
+// SyncLumaEvents updates imported events from Luma now instead of waiting
+// for the periodic sync
+func (h *AdminHandler) SyncLumaEvents(c *gin.Context) {
+	if !h.lumaConfigured(c) {
+		return
+	}
+
+	result, err := h.lumaSync.Sync(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// lumaConfigured responds with 503 and returns false if there's no Luma API key
+func (h *AdminHandler) lumaConfigured(c *gin.Context) bool {
+	if !h.lumaSync.Client().Configured() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": luma.ErrNotConfigured.Error()})
+		return false
+	}
+	return true
+}
+
+// importedLumaEvents maps the Luma event IDs of imported events to their local IDs
+func (h *AdminHandler) importedLumaEvents(c *gin.Context) (map[string]uint64, error) {
+	events, err := h.eventRepo.GetLinkedToLuma(c.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	imported := make(map[string]uint64, len(events))
+	for _, event := range events {
+		imported[event.LumaEventID] = event.ID
+	}
+	return imported, nil
+}
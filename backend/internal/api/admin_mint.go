@@ -0,0 +1,268 @@
+package api
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/minting"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// Limits of one upload
+const (
+	maxMintRows       = 5000     // recipients
+	maxMintUploadSize = 10 << 20 // bytes
+)
+
+// errTooManyRecipients stops parsing an upload with more than maxMintRows recipients
+var errTooManyRecipients = fmt.Errorf("Too many recipients, maximum is %d", maxMintRows)
+
+// MintRequest represents a JSON request to mint for one or more recipients
+type MintRequest struct {
+	WalletAddress string              `json:"wallet_address"`
+	Name          string              `json:"name"`
+	Recipients    []minting.Recipient `json:"recipients"`
+}
+
+// MintForEvent queues NFT mints for a single recipient or an uploaded attendee list
+func (h *AdminHandler) MintForEvent(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	event, err := h.eventRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if event == nil || event.Status == models.EventDeleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+
+	if !event.Mintable() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Event is cancelled, no more NFTs can be minted"})
+		return
+	}
+
+	recipients, err := parseRecipients(c)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Upload is too large, maximum is %d bytes", maxMintUploadSize)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(recipients) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No recipients provided"})
+		return
+	}
+
+	job, err := h.mintQueue.Enqueue(event, mintRows(recipients))
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, formatMintJob(c, job))
+}
+
+// GetMintJob returns the progress of a mint job
+func (h *AdminHandler) GetMintJob(c *gin.Context) {
+	job, found := h.mintQueue.Get(c.Param("id"))
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Mint job not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, formatMintJob(c, job))
+}
+
+// mintRows turns recipients into job rows. Invalid addresses and wallets
+// listed twice are resolved here; wallets that already hold a badge for the
+// event are skipped by the mint itself.
+func mintRows(recipients []minting.Recipient) []minting.Row {
+	seen := make(map[string]bool)
+	rows := make([]minting.Row, 0, len(recipients))
+	for i, recipient := range recipients {
+		row := minting.Row{
+			Row:           i + 1,
+			WalletAddress: strings.TrimSpace(recipient.WalletAddress),
+			Name:          strings.TrimSpace(recipient.Name),
+			Status:        minting.RowPending,
+		}
+
+		canonical, err := address.Canonical(row.WalletAddress)
+		switch {
+		case err != nil:
+			row.Status = minting.RowInvalid
+			row.Error = err.Error()
+		case seen[canonical]:
+			row.WalletAddress = canonical
+			row.Status = minting.RowSkipped
+			row.Error = "wallet is listed more than once"
+		default:
+			row.WalletAddress = canonical
+			seen[canonical] = true
+		}
+
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// parseRecipients reads recipients from a JSON body, a CSV body or a multipart
+// file upload. Bodies over maxMintUploadSize fail with *http.MaxBytesError, and
+// parsing stops with errTooManyRecipients after maxMintRows recipients.
+func parseRecipients(c *gin.Context) ([]minting.Recipient, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxMintUploadSize)
+	contentType := c.ContentType()
+
+	switch {
+	case contentType == "multipart/form-data":
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("missing upload field \"file\"")
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open upload: %v", err)
+		}
+		defer file.Close()
+
+		if strings.EqualFold(filepath.Ext(fileHeader.Filename), ".json") {
+			return parseRecipientsJSON(file)
+		}
+		return parseRecipientsCSV(file)
+
+	case contentType == "text/csv":
+		return parseRecipientsCSV(c.Request.Body)
+
+	default:
+		return parseRecipientsJSON(c.Request.Body)
+	}
+}
+
+// parseRecipientsJSON accepts a single recipient, a recipients list or a bare array
+func parseRecipientsJSON(r io.Reader) ([]minting.Recipient, error) {
+	reader := bufio.NewReader(r)
+
+	// Skip leading whitespace to tell a bare array from a request object
+	for {
+		next, err := reader.Peek(1)
+		if err != nil || !unicode.IsSpace(rune(next[0])) {
+			break
+		}
+		reader.ReadByte()
+	}
+
+	decoder := json.NewDecoder(reader)
+	if next, err := reader.Peek(1); err == nil && next[0] == '[' {
+		// Read the array one recipient at a time so an oversized list isn't decoded whole
+		if _, err := decoder.Token(); err != nil {
+			return nil, fmt.Errorf("invalid JSON recipient list: %w", err)
+		}
+		var recipients []minting.Recipient
+		for decoder.More() {
+			if len(recipients) == maxMintRows {
+				return nil, errTooManyRecipients
+			}
+			var recipient minting.Recipient
+			if err := decoder.Decode(&recipient); err != nil {
+				return nil, fmt.Errorf("invalid JSON recipient list: %w", err)
+			}
+			recipients = append(recipients, recipient)
+		}
+		return recipients, nil
+	}
+
+	var req MintRequest
+	if err := decoder.Decode(&req); err != nil {
+		return nil, fmt.Errorf("invalid JSON request: %w", err)
+	}
+
+	if req.WalletAddress != "" {
+		req.Recipients = append([]minting.Recipient{{WalletAddress: req.WalletAddress, Name: req.Name}}, req.Recipients...)
+	}
+	if len(req.Recipients) > maxMintRows {
+		return nil, errTooManyRecipients
+	}
+
+	return req.Recipients, nil
+}
+
+// parseRecipientsCSV reads "wallet_address,name" rows with an optional header
+func parseRecipientsCSV(r io.Reader) ([]minting.Recipient, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+
+	// Default column layout when there's no header row
+	addressCol, nameCol := 0, 1
+
+	var recipients []minting.Recipient
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		if first {
+			hasHeader := false
+			for i, column := range record {
+				switch strings.ToLower(strings.TrimSpace(column)) {
+				case "wallet_address", "wallet", "address":
+					addressCol = i
+					hasHeader = true
+				case "name", "attendee":
+					nameCol = i
+					hasHeader = true
+				}
+			}
+			if hasHeader {
+				continue
+			}
+		}
+
+		if len(recipients) == maxMintRows {
+			return nil, errTooManyRecipients
+		}
+
+		var recipient minting.Recipient
+		if addressCol < len(record) {
+			recipient.WalletAddress = record[addressCol]
+		}
+		if nameCol < len(record) {
+			recipient.Name = record[nameCol]
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	return recipients, nil
+}
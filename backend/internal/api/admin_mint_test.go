@@ -0,0 +1,168 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/minting"
+)
+
+const (
+	aliceHex = "0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d"
+	bobSS58  = "5FHneW46xGXgs5mUiveU4sbTyGBzmstUspZC92UhjJM694ty"
+	bobHex   = "0x8eaf04151687736326c9fea17e25fc5287613693c912909cb226aa4794f26a48"
+)
+
+func TestMintRows(t *testing.T) {
+	rows := mintRows([]minting.Recipient{
+		{WalletAddress: " " + aliceSS58 + " ", Name: " Alice "},
+		{WalletAddress: bobSS58, Name: "Bob"},
+		{WalletAddress: aliceHex, Name: "Alice again"},
+		{WalletAddress: "nobody", Name: "Eve"},
+	})
+
+	want := []struct {
+		wallet string
+		status minting.RowStatus
+	}{
+		{aliceHex, minting.RowPending},
+		{bobHex, minting.RowPending},
+		{aliceHex, minting.RowSkipped},
+		{"nobody", minting.RowInvalid},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for i, w := range want {
+		row := rows[i]
+		if row.Row != i+1 || row.WalletAddress != w.wallet || row.Status != w.status {
+			t.Errorf("row %d = %+v, want wallet %s and status %s", i+1, row, w.wallet, w.status)
+		}
+		if (row.Status == minting.RowPending) != (row.Error == "") {
+			t.Errorf("row %d status %s has error %q", i+1, row.Status, row.Error)
+		}
+	}
+	if rows[0].Name != "Alice" {
+		t.Errorf("name = %q, want it trimmed", rows[0].Name)
+	}
+}
+
+func TestParseRecipientsCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+		want []minting.Recipient
+	}{
+		{
+			name: "no header",
+			csv:  aliceSS58 + ",Alice\n" + bobSS58 + "\n",
+			want: []minting.Recipient{{WalletAddress: aliceSS58, Name: "Alice"}, {WalletAddress: bobSS58}},
+		},
+		{
+			name: "header",
+			csv:  "name,email,wallet_address\nAlice,alice@example.com," + aliceSS58 + "\n",
+			want: []minting.Recipient{{WalletAddress: aliceSS58, Name: "Alice"}},
+		},
+		{
+			name: "header only",
+			csv:  "wallet,name\n",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRecipientsCSV(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRecipientsJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []minting.Recipient
+	}{
+		{
+			name: "single",
+			body: `{"wallet_address": "` + aliceSS58 + `", "name": "Alice"}`,
+			want: []minting.Recipient{{WalletAddress: aliceSS58, Name: "Alice"}},
+		},
+		{
+			name: "list",
+			body: `{"recipients": [{"wallet_address": "` + bobSS58 + `"}]}`,
+			want: []minting.Recipient{{WalletAddress: bobSS58}},
+		},
+		{
+			name: "array",
+			body: "\n  [{\"wallet_address\": \"" + aliceSS58 + "\"}, {\"wallet_address\": \"" + bobSS58 + "\", \"name\": \"Bob\"}]",
+			want: []minting.Recipient{{WalletAddress: aliceSS58}, {WalletAddress: bobSS58, Name: "Bob"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRecipientsJSON(strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := parseRecipientsJSON(strings.NewReader(`[{"wallet_address": 5}]`)); err == nil {
+		t.Error("invalid recipient was accepted")
+	}
+}
+
+// TestParseRecipientsTooMany checks parsing stops at the row after the limit
+func TestParseRecipientsTooMany(t *testing.T) {
+	row := aliceSS58 + ",Alice\n"
+	csv := strings.Repeat(row, maxMintRows)
+	if _, err := parseRecipientsCSV(strings.NewReader(csv)); err != nil {
+		t.Fatalf("%d rows were rejected: %v", maxMintRows, err)
+	}
+	// The malformed row after the first extra one must never be read
+	if _, err := parseRecipientsCSV(strings.NewReader(csv + row + "\"unterminated\n")); err != errTooManyRecipients {
+		t.Errorf("error = %v, want %v", err, errTooManyRecipients)
+	}
+
+	item := `{"wallet_address": "` + aliceSS58 + `"},`
+	// Likewise the malformed item after the limit
+	array := "[" + strings.Repeat(item, maxMintRows) + "{]"
+	if _, err := parseRecipientsJSON(strings.NewReader(array)); err != errTooManyRecipients {
+		t.Errorf("array error = %v, want %v", err, errTooManyRecipients)
+	}
+
+	list := `{"recipients": [` + strings.Repeat(item, maxMintRows) + item[:len(item)-1] + `]}`
+	if _, err := parseRecipientsJSON(strings.NewReader(list)); err != errTooManyRecipients {
+		t.Errorf("list error = %v, want %v", err, errTooManyRecipients)
+	}
+}
+
+func TestParseRecipientsTooLarge(t *testing.T) {
+	body := "[" + strings.Repeat(" ", maxMintUploadSize) + "]"
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/mint", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	_, err := parseRecipients(c)
+	var tooLarge *http.MaxBytesError
+	if !errors.As(err, &tooLarge) {
+		t.Errorf("error = %v, want *http.MaxBytesError", err)
+	}
+}
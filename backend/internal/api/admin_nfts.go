@@ -0,0 +1,211 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/polkadot"
+)
+
+// RevokeRequest represents a request to revoke (burn) an NFT
+type RevokeRequest struct {
+	Reason string `json:"reason" binding:"required,min=3,max=500"`
+}
+
+// TransferRequest represents a request to move an NFT to a new wallet
+type TransferRequest struct {
+	To     string `json:"to" binding:"required"`
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// loadNFT parses the NFT ID from the path and loads an NFT that hasn't been burned
+func (h *AdminHandler) loadNFT(c *gin.Context) (*models.NFT, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid NFT ID"})
+		return nil, false
+	}
+
+	nft, err := h.nftRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	if nft == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "NFT not found"})
+		return nil, false
+	}
+
+	if nft.Burned {
+		c.JSON(http.StatusConflict, gin.H{"error": "NFT has already been revoked"})
+		return nil, false
+	}
+
+	return nft, true
+}
+
+// requireChainToken checks that an NFT's mint was confirmed, since transfers
+// and burns address its token by the ID the chain assigned
+func requireChainToken(c *gin.Context, nft *models.NFT) bool {
+	if nft.ChainTokenID == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "NFT has no confirmed token on chain"})
+		return false
+	}
+	return true
+}
+
+// RevokeNFT burns an NFT on chain and marks it as revoked
+func (h *AdminHandler) RevokeNFT(c *gin.Context) {
+	var req RevokeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	nft, ok := h.loadNFT(c)
+	if !ok || !requireChainToken(c, nft) {
+		return
+	}
+
+	// Burn on chain first so the database never claims a revocation that didn't happen
+	success, err := h.chain.BurnNFT(c.Request.Context(), nft.EventID, nft.ChainTokenID)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("Failed to burn NFT: %v", err)})
+		return
+	}
+
+	if !success {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to burn NFT on blockchain"})
+		return
+	}
+
+	if err := h.nftRepo.Burn(c.Request.Context(), nft.ID, strings.TrimSpace(req.Reason)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.respondWithNFT(c, nft.ID)
+}
+
+// TransferNFT moves an NFT to a new wallet
+func (h *AdminHandler) TransferNFT(c *gin.Context) {
+	var req TransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	to, err := address.Canonical(req.To)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recipient address: " + err.Error()})
+		return
+	}
+
+	nft, ok := h.loadNFT(c)
+	if !ok || !requireChainToken(c, nft) {
+		return
+	}
+
+	if nft.Owner == to {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "NFT is already owned by this wallet"})
+		return
+	}
+
+	// Soulbound badges can only be revoked, never moved
+	event, err := h.eventRepo.GetByID(c.Request.Context(), nft.EventID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if event != nil && !event.Transferable {
+		c.JSON(http.StatusForbidden, gin.H{"error": "This event's NFTs are soulbound and cannot be transferred"})
+		return
+	}
+
+	// A wallet holds one NFT per event, so check before moving it on chain
+	owned, err := h.nftRepo.HasOwner(c.Request.Context(), nft.EventID, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if owned {
+		c.JSON(http.StatusConflict, gin.H{"error": database.ErrAlreadyMinted.Error()})
+		return
+	}
+
+	success, err := h.chain.TransferNFT(c.Request.Context(), nft.EventID, nft.ChainTokenID, to)
+	if errors.Is(err, polkadot.ErrNonTransferable) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "This event's NFTs are soulbound and cannot be transferred"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("Failed to transfer NFT: %v", err)})
+		return
+	}
+
+	if !success {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to transfer NFT on blockchain"})
+		return
+	}
+
+	if err := h.nftRepo.Transfer(c.Request.Context(), nft.ID, to, strings.TrimSpace(req.Reason)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Make sure the new owner has a user record
+	if _, err := h.userRepo.GetOrCreate(c.Request.Context(), to); err != nil {
+		// Log the error but continue
+		c.Error(err)
+	}
+
+	h.respondWithNFT(c, nft.ID)
+}
+
+// GetNFTHistory lists the ownership changes of an NFT
+func (h *AdminHandler) GetNFTHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid NFT ID"})
+		return
+	}
+
+	history, err := h.nftRepo.GetOwnershipHistory(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	prefix := outputPrefix(c)
+	for i := range history {
+		if history[i].FromOwner != "" {
+			history[i].FromOwner = address.Format(history[i].FromOwner, prefix)
+		}
+		if history[i].ToOwner != "" {
+			history[i].ToOwner = address.Format(history[i].ToOwner, prefix)
+		}
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// respondWithNFT reloads an NFT and writes it to the response
+func (h *AdminHandler) respondWithNFT(c *gin.Context, id uint64) {
+	nft, err := h.nftRepo.GetByID(c.Request.Context(), id)
+	if err != nil || nft == nil {
+		c.JSON(http.StatusOK, gin.H{"success": true, "nft_id": id})
+		return
+	}
+
+	c.JSON(http.StatusOK, formatNFT(c, *nft))
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// EventSourceRequest links an event to a ticketing provider's event
+type EventSourceRequest struct {
+	// ExternalEventID is the provider's event ID: the Eventbrite or Luma
+	// event ID, organizer/event for pretix or account/event for Tito
+	ExternalEventID string `json:"external_event_id" binding:"required,max=255"`
+	WebhookSecret   string `json:"webhook_secret" binding:"max=255"`  // overrides the provider's configured secret
+	WalletQuestion  string `json:"wallet_question" binding:"max=255"` // overrides the configured wallet question
+}
+
+// EventSourceResponse describes an event source without its webhook secret
+type EventSourceResponse struct {
+	models.EventSource
+	HasWebhookSecret bool   `json:"has_webhook_secret"`
+	WebhookPath      string `json:"webhook_path"`
+}
+
+// ListEventSources lists the ticketing providers an event takes check-ins from
+func (h *AdminHandler) ListEventSources(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	sources, err := h.sourceRepo.ListByEvent(c.Request.Context(), event.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]EventSourceResponse, len(sources))
+	for i, source := range sources {
+		response[i] = formatEventSource(source)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sources":       response,
+		"luma_event_id": event.LumaEventID,
+		"providers":     h.providers.Names(),
+	})
+}
+
+// SaveEventSource links an event to the event of the provider in the URL
+func (h *AdminHandler) SaveEventSource(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	provider := c.Param("provider")
+	if _, _, ok := h.providers.Get(provider); !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown check-in provider"})
+		return
+	}
+
+	var req EventSourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	source := &models.EventSource{
+		EventID:         event.ID,
+		Provider:        provider,
+		ExternalEventID: strings.TrimSpace(req.ExternalEventID),
+		WebhookSecret:   req.WebhookSecret,
+		WalletQuestion:  strings.TrimSpace(req.WalletQuestion),
+	}
+	if source.ExternalEventID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "external_event_id cannot be empty"})
+		return
+	}
+
+	if err := h.sourceRepo.Save(c.Request.Context(), source); err != nil {
+		if errors.Is(err, database.ErrSourceInUse) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, formatEventSource(*source))
+}
+
+// DeleteEventSource stops an event from taking check-ins from a provider
+func (h *AdminHandler) DeleteEventSource(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := h.sourceRepo.Delete(c.Request.Context(), event.ID, c.Param("provider"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event has no source for this provider"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// formatEventSource hides an event source's webhook secret
+func formatEventSource(source models.EventSource) EventSourceResponse {
+	return EventSourceResponse{
+		EventSource:      source,
+		HasWebhookSecret: source.WebhookSecret != "",
+		WebhookPath:      "/api/webhook/" + source.Provider,
+	}
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/badges"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// BadgeHandler serves the badge artwork of events and their NFTs
+type BadgeHandler struct {
+	eventRepo *database.EventRepository
+	nftRepo   *database.NFTRepository
+	renderer  *badges.Renderer
+}
+
+// NewBadgeHandler creates a new badge artwork handler
+func NewBadgeHandler(eventRepo *database.EventRepository, nftRepo *database.NFTRepository, renderer *badges.Renderer) *BadgeHandler {
+	return &BadgeHandler{
+		eventRepo: eventRepo,
+		nftRepo:   nftRepo,
+		renderer:  renderer,
+	}
+}
+
+// GetEventBadgePNG returns an event's badge as PNG
+func (h *BadgeHandler) GetEventBadgePNG(c *gin.Context) {
+	h.eventBadge(c, badges.FormatPNG)
+}
+
+// GetEventBadgeSVG returns an event's badge as SVG
+func (h *BadgeHandler) GetEventBadgeSVG(c *gin.Context) {
+	h.eventBadge(c, badges.FormatSVG)
+}
+
+// eventBadge returns an event's badge, without an attendee number
+func (h *BadgeHandler) eventBadge(c *gin.Context, format string) {
+	event, ok := loadPublicEvent(c, h.eventRepo)
+	if !ok {
+		return
+	}
+
+	h.respondBadge(c, event, 0, format)
+}
+
+// GetNFTImage returns an NFT's badge, numbered for its attendee. It's PNG
+// unless ?format=svg is given. This is the image in the NFT's metadata.
+func (h *BadgeHandler) GetNFTImage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid NFT ID"})
+		return
+	}
+
+	format := c.DefaultQuery("format", badges.FormatPNG)
+	if format != badges.FormatPNG && format != badges.FormatSVG {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format must be png or svg"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	nft, err := h.nftRepo.GetByID(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if nft == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "NFT not found"})
+		return
+	}
+	if nft.Burned {
+		c.JSON(http.StatusGone, gin.H{"error": "NFT has been revoked"})
+		return
+	}
+
+	// NFTs keep their event's badge when the event is soft-deleted
+	event, err := h.eventRepo.GetByID(ctx, nft.EventID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if event == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+
+	number, err := h.nftRepo.AttendeeNumber(ctx, nft)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.respondBadge(c, event, number, format)
+}
+
+// respondBadge renders a badge and writes it with caching headers
+func (h *BadgeHandler) respondBadge(c *gin.Context, event *models.Event, number int, format string) {
+	data, err := h.renderer.Render(c.Request.Context(), event, number, format)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	if format == badges.FormatSVG {
+		// SVGs opened directly mustn't run anything a custom template lets through
+		c.Header("Content-Security-Policy", "default-src 'none'; img-src data:; style-src 'unsafe-inline'")
+	}
+	c.Data(http.StatusOK, badges.ContentType(format), data)
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/checkin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+)
+
+// CheckInHandler handles attendees checking in with an event's QR code
+type CheckInHandler struct {
+	eventRepo *database.EventRepository
+	checkIns  *checkin.Service
+}
+
+// NewCheckInHandler creates a new QR check-in handler
+func NewCheckInHandler(eventRepo *database.EventRepository, checkIns *checkin.Service) *CheckInHandler {
+	return &CheckInHandler{
+		eventRepo: eventRepo,
+		checkIns:  checkIns,
+	}
+}
+
+// CheckInRequest checks a wallet in to an event with the code it scanned
+type CheckInRequest struct {
+	Code          string `json:"code" binding:"required"`
+	WalletAddress string `json:"wallet_address" binding:"required"`
+	// Signature is the wallet's hex sr25519 signature of the check-in message
+	Signature string `json:"signature" binding:"required"`
+	Name      string `json:"name" binding:"max=100"`
+	// Latitude and Longitude are required by events with a geofence
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+// CheckIn mints an event's NFT to a wallet that submits the event's current
+// QR code
+func (h *CheckInHandler) CheckIn(c *gin.Context) {
+	var req CheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	event, ok := loadPublicEvent(c, h.eventRepo)
+	if !ok {
+		return
+	}
+
+	nft, err := h.checkIns.CheckIn(c.Request.Context(), event, checkin.Request{
+		Code:          req.Code,
+		WalletAddress: req.WalletAddress,
+		Signature:     req.Signature,
+		Name:          req.Name,
+		Latitude:      req.Latitude,
+		Longitude:     req.Longitude,
+	})
+	if err != nil && nft == nil {
+		respondCheckInError(c, err)
+		return
+	}
+	if err != nil {
+		// The NFT is stored; only the chain mint failed
+		c.Error(err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"nft":     formatNFT(c, *nft),
+	})
+}
+
+// respondCheckInError maps QR check-in errors to a response
+func respondCheckInError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, checkin.ErrNotEnabled):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, checkin.ErrInvalidWallet),
+		errors.Is(err, checkin.ErrLocationRequired),
+		errors.Is(err, checkin.ErrInvalidGeofence),
+		errors.Is(err, checkin.ErrInvalidPeriod):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, checkin.ErrInvalidCode), errors.Is(err, checkin.ErrInvalidSignature):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, checkin.ErrOutsideGeofence):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, checkin.ErrAlreadyCheckedIn), errors.Is(err, database.ErrAlreadyMinted):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, database.ErrEventNotMintable):
+		c.JSON(http.StatusConflict, gin.H{"error": "Event is not open for minting"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/claims"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/minting"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// ClaimHandler handles the claim links sent to attendees without a wallet
+type ClaimHandler struct {
+	claims *claims.Service
+}
+
+// NewClaimHandler creates a new claim handler
+func NewClaimHandler(claims *claims.Service) *ClaimHandler {
+	return &ClaimHandler{claims: claims}
+}
+
+// ClaimRequest redeems a claim link for a wallet
+type ClaimRequest struct {
+	WalletAddress string `json:"wallet_address" binding:"required"`
+	// Signature is the wallet's hex sr25519 signature of the claim message
+	Signature string `json:"signature" binding:"required"`
+}
+
+// ClaimResponse describes a claim to the attendee holding its link
+type ClaimResponse struct {
+	Status        string               `json:"status"`
+	Name          string               `json:"name,omitempty"`
+	ExpiresAt     time.Time            `json:"expires_at"`
+	WalletAddress string               `json:"wallet_address,omitempty"`
+	NFTID         *uint64              `json:"nft_id,omitempty"`
+	Event         models.Event         `json:"event"`
+	Badge         models.TokenMetadata `json:"badge"`
+	// Message is what the wallet signs to claim the NFT
+	Message string `json:"message"`
+}
+
+// GetClaim shows the badge a claim link is for
+func (h *ClaimHandler) GetClaim(c *gin.Context) {
+	token := c.Param("token")
+
+	claim, event, err := h.claims.Lookup(c.Request.Context(), token)
+	if err != nil {
+		respondClaimError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, formatClaim(c, token, claim, event))
+}
+
+// RedeemClaim mints a claim link's NFT to the wallet that signed the claim message
+func (h *ClaimHandler) RedeemClaim(c *gin.Context) {
+	var req ClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token := c.Param("token")
+
+	claim, nft, err := h.claims.Redeem(c.Request.Context(), token, req.WalletAddress, req.Signature)
+	if err != nil && nft == nil {
+		respondClaimError(c, err)
+		return
+	}
+	if err != nil {
+		// The NFT is minted; recording the claim is what failed
+		c.Error(err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"claim_id": claim.ID,
+		"nft":      formatNFT(c, *nft),
+	})
+}
+
+// formatClaim builds the attendee's view of a claim
+func formatClaim(c *gin.Context, token string, claim *models.Claim, event *models.Event) ClaimResponse {
+	response := ClaimResponse{
+		Status:    claim.Status,
+		Name:      claim.Name,
+		ExpiresAt: claim.ExpiresAt,
+		NFTID:     claim.NFTID,
+		Event:     formatEvent(c, *event),
+		Badge:     minting.Metadata(event, claim.Name),
+		Message:   claims.Message(token),
+	}
+	if claim.WalletAddress != "" {
+		response.WalletAddress = address.Format(claim.WalletAddress, outputPrefix(c))
+	}
+	return response
+}
+
+// respondClaimError maps claim errors to a response
+func respondClaimError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, claims.ErrInvalidToken):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, claims.ErrExpired):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+	case errors.Is(err, claims.ErrInvalidWallet):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, claims.ErrInvalidSignature):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, claims.ErrAlreadyClaimed), errors.Is(err, claims.ErrClaimInProgress),
+		errors.Is(err, database.ErrAlreadyMinted):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, database.ErrEventNotMintable):
+		c.JSON(http.StatusConflict, gin.H{"error": "Event is not open for minting"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
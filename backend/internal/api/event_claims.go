@@ -0,0 +1,177 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/claims"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// EventClaimHandler handles badges claimed under events' claim policies
+type EventClaimHandler struct {
+	eventRepo  *database.EventRepository
+	policyRepo *database.ClaimPolicyRepository
+	claimer    *claims.Claimer
+	limiter    *AttemptLimiter
+}
+
+// NewEventClaimHandler creates a new event claim handler
+func NewEventClaimHandler(
+	eventRepo *database.EventRepository,
+	policyRepo *database.ClaimPolicyRepository,
+	claimer *claims.Claimer,
+	limiter *AttemptLimiter,
+) *EventClaimHandler {
+	return &EventClaimHandler{
+		eventRepo:  eventRepo,
+		policyRepo: policyRepo,
+		claimer:    claimer,
+		limiter:    limiter,
+	}
+}
+
+// EventClaimRequest claims an event's badge for a wallet
+type EventClaimRequest struct {
+	WalletAddress string `json:"wallet_address" binding:"required"`
+	// Signature is the wallet's hex sr25519 signature of the claim message
+	Signature string `json:"signature" binding:"required"`
+	Code      string `json:"code" binding:"max=100"` // required in code mode
+	Name      string `json:"name" binding:"max=100"`
+}
+
+// GetEventClaim shows whether an event's badge can be claimed and what the
+// wallet has to sign
+func (h *EventClaimHandler) GetEventClaim(c *gin.Context) {
+	event, ok := loadPublicEvent(c, h.eventRepo)
+	if !ok {
+		return
+	}
+
+	policy := event.ClaimPolicy
+	response := gin.H{
+		"event":   formatEvent(c, *event),
+		"mode":    policy.Mode,
+		"open":    event.Mintable() && policy.Open(time.Now()),
+		"message": claims.PolicyMessage(event.ID),
+	}
+	if policy.OpensAt != nil {
+		response["opens_at"] = policy.OpensAt
+	}
+	if policy.ClosesAt != nil {
+		response["closes_at"] = policy.ClosesAt
+	}
+
+	if policy.MaxClaims != nil {
+		claimed, err := h.policyRepo.CountByEvent(c.Request.Context(), event.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		remaining := *policy.MaxClaims - claimed
+		if remaining < 0 {
+			remaining = 0
+		}
+		response["remaining"] = remaining
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ClaimEventBadge mints an event's badge to a wallet that signed the claim
+// message, if the event's claim policy allows it. Attempts are rate limited
+// per wallet and per IP address.
+func (h *EventClaimHandler) ClaimEventBadge(c *gin.Context) {
+	var req EventClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	wallet, err := address.Canonical(req.WalletAddress)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wallet address: " + err.Error()})
+		return
+	}
+
+	if !h.limiter.Allow("ip:"+c.ClientIP(), "wallet:"+wallet) {
+		c.Header("Retry-After", "60")
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many claim attempts. Try again in a minute."})
+		return
+	}
+
+	event, ok := loadPublicEvent(c, h.eventRepo)
+	if !ok {
+		return
+	}
+
+	nft, err := h.claimer.Claim(c.Request.Context(), event, claims.DirectRequest{
+		WalletAddress: req.WalletAddress,
+		Signature:     req.Signature,
+		Code:          req.Code,
+		Name:          req.Name,
+		IPAddress:     c.ClientIP(),
+	})
+	if err != nil && nft == nil {
+		respondEventClaimError(c, err)
+		return
+	}
+	if err != nil {
+		// The NFT is stored; only the chain mint failed
+		c.Error(err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"nft":     formatNFT(c, *nft),
+	})
+}
+
+// loadPublicEvent loads the event in the URL for a public endpoint,
+// responding with an error if it doesn't exist
+func loadPublicEvent(c *gin.Context, eventRepo *database.EventRepository) (*models.Event, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return nil, false
+	}
+
+	event, err := eventRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	if event == nil || event.Status == models.EventDeleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return nil, false
+	}
+
+	return event, true
+}
+
+// respondEventClaimError maps claim policy errors to a response
+func respondEventClaimError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, claims.ErrInvalidWallet):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, claims.ErrInvalidSignature), errors.Is(err, claims.ErrWrongCode):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, claims.ErrNotAllowlisted), errors.Is(err, claims.ErrClaimsDisabled):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, claims.ErrNotOpenYet), errors.Is(err, claims.ErrAlreadyClaimed),
+		errors.Is(err, database.ErrAlreadyMinted):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, claims.ErrClaimsClosed), errors.Is(err, database.ErrClaimLimitReached):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+	case errors.Is(err, database.ErrEventNotMintable):
+		c.JSON(http.StatusConflict, gin.H{"error": "Event is not open for minting"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/minting"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// ss58PrefixKey is the context key holding the requested SS58 prefix
+const ss58PrefixKey = "ss58_prefix"
+
+// outputPrefix returns the SS58 prefix addresses should be encoded with
+func outputPrefix(c *gin.Context) uint16 {
+	if prefix, exists := c.Get(ss58PrefixKey); exists {
+		return prefix.(uint16)
+	}
+	return address.PrefixGeneric
+}
+
+// formatEvent re-encodes the addresses of an event for output
+func formatEvent(c *gin.Context, event models.Event) models.Event {
+	event.Organizer = address.Format(event.Organizer, outputPrefix(c))
+	return event
+}
+
+// formatEvents re-encodes the addresses of a list of events for output
+func formatEvents(c *gin.Context, events []models.Event) []models.Event {
+	formatted := make([]models.Event, len(events))
+	for i, event := range events {
+		formatted[i] = formatEvent(c, event)
+	}
+	return formatted
+}
+
+// formatNFT re-encodes the addresses of an NFT for output
+func formatNFT(c *gin.Context, nft models.NFT) models.NFT {
+	nft.Owner = address.Format(nft.Owner, outputPrefix(c))
+	return nft
+}
+
+// formatNFTs re-encodes the addresses of a list of NFTs for output
+func formatNFTs(c *gin.Context, nfts []models.NFT) []models.NFT {
+	formatted := make([]models.NFT, len(nfts))
+	for i, nft := range nfts {
+		formatted[i] = formatNFT(c, nft)
+	}
+	return formatted
+}
+
+// formatUser re-encodes the wallet address of a user for output
+func formatUser(c *gin.Context, user database.User) database.User {
+	user.WalletAddress = address.Format(user.WalletAddress, outputPrefix(c))
+	return user
+}
+
+// formatMintJob re-encodes the recipient addresses of a mint job for output
+func formatMintJob(c *gin.Context, job *minting.Job) *minting.Job {
+	for i := range job.Rows {
+		job.Rows[i].WalletAddress = address.Format(job.Rows[i].WalletAddress, outputPrefix(c))
+	}
+	return job
+}
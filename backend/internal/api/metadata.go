@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// MetadataHandler serves NFT metadata to wallets and marketplaces
+type MetadataHandler struct {
+	nftRepo *database.NFTRepository
+}
+
+// NewMetadataHandler creates a new NFT metadata handler
+func NewMetadataHandler(nftRepo *database.NFTRepository) *MetadataHandler {
+	return &MetadataHandler{
+		nftRepo: nftRepo,
+	}
+}
+
+// GetMetadata returns an NFT's metadata in the common NFT JSON format. This
+// is what the metadata URI stored on chain points at.
+func (h *MetadataHandler) GetMetadata(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("nft_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid NFT ID"})
+		return
+	}
+
+	nft, err := h.nftRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if nft == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "NFT not found"})
+		return
+	}
+	if nft.Burned {
+		c.JSON(http.StatusGone, gin.H{"error": "NFT has been revoked"})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, models.TokenMetadataFromMap(nft.Metadata))
+}
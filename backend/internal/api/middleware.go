@@ -1,15 +1,16 @@
 package api
 
 import (
+	"log"
 	"net/http"
-	"strings"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
-	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/config"
 	"github.com/patrickmn/go-cache"
-	"strconv"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/config"
 )
 
 // BasicAuthMiddleware provides basic authentication for admin routes
@@ -23,9 +24,9 @@ func BasicAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 func CorsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
-		c.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Length")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, If-Match")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Length, ETag, X-Total-Count, X-Next-Cursor")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 
 		if c.Request.Method == "OPTIONS" {
@@ -51,11 +52,11 @@ func RateLimiter(enabled bool, requestsPerMinute int) gin.HandlerFunc {
 
 		// Get client IP
 		ip := c.ClientIP()
-		
+
 		// Get current minute as cache key
 		minute := time.Now().Format("2006-01-02 15:04")
 		key := ip + ":" + minute
-		
+
 		// Check if key exists
 		count, found := requestCache.Get(key)
 		if !found {
@@ -64,7 +65,7 @@ func RateLimiter(enabled bool, requestsPerMinute int) gin.HandlerFunc {
 			c.Next()
 			return
 		}
-		
+
 		// Check rate limit
 		reqCount := count.(int)
 		if reqCount >= requestsPerMinute {
@@ -74,36 +75,91 @@ func RateLimiter(enabled bool, requestsPerMinute int) gin.HandlerFunc {
 			})
 			return
 		}
-		
+
 		// Increment request count
 		requestCache.Set(key, reqCount+1, cache.DefaultExpiration)
-		
+
 		// Add rate limit headers
 		c.Header("X-RateLimit-Limit", strconv.Itoa(requestsPerMinute))
 		c.Header("X-RateLimit-Remaining", strconv.Itoa(requestsPerMinute-reqCount-1))
-		
+
 		c.Next()
 	}
 }
 
+// AttemptLimiter limits how often a wallet or IP address can attempt an
+// action, such as claiming a badge, within a window
+type AttemptLimiter struct {
+	attempts *cache.Cache
+	limit    int
+	window   time.Duration
+}
+
+// NewAttemptLimiter creates a limiter allowing limit attempts per key and
+// window. A limit of 0 or less allows every attempt.
+func NewAttemptLimiter(limit int, window time.Duration) *AttemptLimiter {
+	return &AttemptLimiter{
+		attempts: cache.New(window, 2*window),
+		limit:    limit,
+		window:   window,
+	}
+}
+
+// Allow counts an attempt for each key and reports whether all of them are
+// still within the limit
+func (l *AttemptLimiter) Allow(keys ...string) bool {
+	if l.limit <= 0 {
+		return true
+	}
+
+	allowed := true
+	for _, key := range keys {
+		count := 1
+		if err := l.attempts.Add(key, count, l.window); err != nil {
+			count, _ = l.attempts.IncrementInt(key, 1)
+		}
+		if count > l.limit {
+			allowed = false
+		}
+	}
+	return allowed
+}
+
 // ValidateContractAddress validates and formats contract addresses
-func ValidateContractAddress(address string) string {
+func ValidateContractAddress(contractAddress string) string {
 	// If address is empty, return empty
-	if address == "" {
+	if contractAddress == "" {
 		return ""
 	}
 
-	// If it's a Substrate address (starting with 5)
-	if strings.HasPrefix(address, "5") {
-		return address
+	// Convert SS58 and hex addresses to the canonical hex form
+	canonical, err := address.Canonical(contractAddress)
+	if err != nil {
+		log.Printf("Invalid contract address %s: %v", contractAddress, err)
+		return contractAddress
 	}
 
-	// If address doesn't start with 0x, add it
-	if !strings.HasPrefix(address, "0x") {
-		return "0x" + address
-	}
+	return canonical
+}
 
-	return address
+// AddressFormatMiddleware resolves the SS58 prefix used for addresses in responses.
+// Clients can request a network with ?ss58=<prefix or network name>.
+func AddressFormatMiddleware(defaultPrefix uint16) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		prefix := defaultPrefix
+
+		if value := c.Query("ss58"); value != "" {
+			parsed, err := address.ParsePrefix(value)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			prefix = parsed
+		}
+
+		c.Set(ss58PrefixKey, prefix)
+		c.Next()
+	}
 }
 
 // JWTAuth provides JWT authentication
@@ -172,4 +228,4 @@ func OwnershipMiddleware() gin.HandlerFunc {
 
 		c.Next()
 	}
-} 
\ No newline at end of file
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+)
+
+// Response headers describing a paginated list
+const (
+	totalCountHeader = "X-Total-Count"
+	nextCursorHeader = "X-Next-Cursor"
+)
+
+// pageFromQuery reads the limit, cursor and sort query parameters.
+// It responds with 400 and returns false if they are invalid.
+func pageFromQuery(c *gin.Context) (database.PageRequest, bool) {
+	page := database.PageRequest{
+		Cursor: c.Query("cursor"),
+		Sort:   c.Query("sort"),
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return page, false
+		}
+		page.Limit = limit
+	}
+
+	return page, true
+}
+
+// setPageHeaders reports the total count and the cursor of the next page
+func setPageHeaders(c *gin.Context, info *database.PageInfo) {
+	c.Header(totalCountHeader, strconv.Itoa(info.Total))
+	if info.NextCursor != "" {
+		c.Header(nextCursorHeader, info.NextCursor)
+	}
+}
+
+// respondListError maps list errors to a response, treating invalid page
+// requests as client errors
+func respondListError(c *gin.Context, err error) {
+	if errors.Is(err, database.ErrInvalidPage) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// nftFilterFromQuery reads the event_id and confirmed query parameters.
+// It responds with 400 and returns false if they are invalid.
+func nftFilterFromQuery(c *gin.Context) (database.NFTFilter, bool) {
+	var filter database.NFTFilter
+
+	if eventIDStr := c.Query("event_id"); eventIDStr != "" {
+		eventID, err := strconv.ParseUint(eventIDStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+			return filter, false
+		}
+		filter.EventID = eventID
+	}
+
+	if confirmedStr := c.Query("confirmed"); confirmedStr != "" {
+		confirmed, err := strconv.ParseBool(confirmedStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "confirmed must be true or false"})
+			return filter, false
+		}
+		filter.Confirmed = &confirmed
+	}
+
+	return filter, true
+}
+
+// addressFromQuery reads an optional address query parameter in canonical form.
+// It responds with 400 and returns false if the address is invalid.
+func addressFromQuery(c *gin.Context, name string) (string, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return "", true
+	}
+
+	canonical, err := address.Canonical(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name + " address: " + err.Error()})
+		return "", false
+	}
+	return canonical, true
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+)
+
+const aliceSS58 = "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY"
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// listRouter serves a list endpoint the way the handlers do: it reads the page
+// and filters from the query, lists through newPage and writes the page headers
+func listRouter(newPage func(database.PageRequest) (*database.PageInfo, error)) *gin.Engine {
+	router := gin.New()
+	router.GET("/list", func(c *gin.Context) {
+		page, ok := pageFromQuery(c)
+		if !ok {
+			return
+		}
+		filter, ok := nftFilterFromQuery(c)
+		if !ok {
+			return
+		}
+		owner, ok := addressFromQuery(c, "owner")
+		if !ok {
+			return
+		}
+
+		info, err := newPage(page)
+		if err != nil {
+			respondListError(c, err)
+			return
+		}
+
+		setPageHeaders(c, info)
+		c.JSON(http.StatusOK, gin.H{
+			"limit":     page.Limit,
+			"cursor":    page.Cursor,
+			"sort":      page.Sort,
+			"event_id":  filter.EventID,
+			"confirmed": filter.Confirmed,
+			"owner":     owner,
+		})
+	})
+	return router
+}
+
+func get(router *gin.Engine, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+func TestListQuery(t *testing.T) {
+	var got database.PageRequest
+	router := listRouter(func(page database.PageRequest) (*database.PageInfo, error) {
+		got = page
+		return &database.PageInfo{Total: 3}, nil
+	})
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantPage   database.PageRequest
+	}{
+		{"defaults", "/list", http.StatusOK, database.PageRequest{}},
+		{"page", "/list?limit=20&cursor=abc&sort=-created_at", http.StatusOK, database.PageRequest{Limit: 20, Cursor: "abc", Sort: "-created_at"}},
+		{"filters", "/list?event_id=4&confirmed=true&owner=" + aliceSS58, http.StatusOK, database.PageRequest{}},
+		{"zero limit", "/list?limit=0", http.StatusBadRequest, database.PageRequest{}},
+		{"negative limit", "/list?limit=-5", http.StatusBadRequest, database.PageRequest{}},
+		{"limit not a number", "/list?limit=ten", http.StatusBadRequest, database.PageRequest{}},
+		{"bad event ID", "/list?event_id=x", http.StatusBadRequest, database.PageRequest{}},
+		{"bad confirmed", "/list?confirmed=maybe", http.StatusBadRequest, database.PageRequest{}},
+		{"bad owner", "/list?owner=nobody", http.StatusBadRequest, database.PageRequest{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = database.PageRequest{}
+			w := get(router, tt.target)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if got != tt.wantPage {
+				t.Errorf("page = %+v, want %+v", got, tt.wantPage)
+			}
+		})
+	}
+}
+
+func TestListPageHeaders(t *testing.T) {
+	tests := []struct {
+		name       string
+		info       database.PageInfo
+		wantTotal  string
+		wantCursor string
+	}{
+		{"more pages", database.PageInfo{Total: 120, NextCursor: "eyJpZCI6NTB9"}, "120", "eyJpZCI6NTB9"},
+		{"last page", database.PageInfo{Total: 7}, "7", ""},
+		{"empty", database.PageInfo{}, "0", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := listRouter(func(database.PageRequest) (*database.PageInfo, error) {
+				return &tt.info, nil
+			})
+
+			w := get(router, "/list")
+			if got := w.Header().Get(totalCountHeader); got != tt.wantTotal {
+				t.Errorf("%s = %q, want %q", totalCountHeader, got, tt.wantTotal)
+			}
+			if _, set := w.Header()[nextCursorHeader]; set != (tt.wantCursor != "") {
+				t.Errorf("%s set = %v, want %v", nextCursorHeader, set, tt.wantCursor != "")
+			}
+			if got := w.Header().Get(nextCursorHeader); got != tt.wantCursor {
+				t.Errorf("%s = %q, want %q", nextCursorHeader, got, tt.wantCursor)
+			}
+		})
+	}
+}
+
+func TestListErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"invalid page", fmt.Errorf("%w: malformed cursor", database.ErrInvalidPage), http.StatusBadRequest},
+		{"database", fmt.Errorf("failed to list NFTs: connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := listRouter(func(database.PageRequest) (*database.PageInfo, error) {
+				return nil, tt.err
+			})
+
+			w := get(router, "/list?cursor=bad")
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if _, set := w.Header()[totalCountHeader]; set {
+				t.Errorf("%s set on an error", totalCountHeader)
+			}
+		})
+	}
+}
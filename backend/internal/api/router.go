@@ -1,46 +1,142 @@
 package api
 
 import (
+	"context"
+	"log"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/badges"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/checkin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/claims"
 	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/config"
 	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
 	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/luma"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/minting"
 	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/polkadot"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/providers"
 )
 
-// NewRouter creates a new gin router with configured routes
+// NewRouter creates a new gin router with configured routes.
+// Background work started by the router, such as the mint queue, stops when ctx is cancelled.
 func NewRouter(
+	ctx context.Context,
 	cfg *config.Config, 
-	polkadotClient *polkadot.Client,
+	chain polkadot.AttendanceChain,
 	eventRepo *database.EventRepository,
 	nftRepo *database.NFTRepository,
 	userRepo *database.UserRepository,
 	permRepo *database.PermissionRepository,
+	claimRepo *database.ClaimRepository,
+	checkinRepo *database.CheckInRepository,
+	sourceRepo *database.EventSourceRepository,
+	policyRepo *database.ClaimPolicyRepository,
 ) *gin.Engine {
 	r := gin.Default()
 
 	// Middleware
 	r.Use(CorsMiddleware())
 	r.Use(RateLimiter(cfg.RateLimit.Enabled, cfg.RateLimit.RequestsPerMinute))
+	r.Use(AddressFormatMiddleware(cfg.SS58Prefix))
 
 	// Health check
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
 
+	// Shared NFT minting path and background mint queue
+	metadataStore := minting.NewMetadataStore(cfg.MetadataBaseURL, cfg.IPFSAPIURL)
+	minter := minting.NewMinter(chain, nftRepo, userRepo, metadataStore, cfg.NFTImageBaseURL)
+	mintQueue := minting.NewQueue(ctx, minter)
+
+	// Luma API client and the periodic sync of imported events
+	lumaClient := luma.NewClient(cfg.LumaAPIKey, cfg.LumaAPIURL, cfg.LumaWalletQuestion)
+	lumaSync := luma.NewSyncer(lumaClient, eventRepo)
+	if lumaClient.Configured() && cfg.LumaSyncMinutes > 0 {
+		go lumaSync.Run(ctx, time.Duration(cfg.LumaSyncMinutes)*time.Minute)
+	}
+
+	// Ticketing providers whose check-in webhooks mint NFTs
+	if cfg.Providers.AllowUnsigned {
+		log.Printf("Warning: accepting unsigned check-in webhooks from providers without a secret")
+	}
+	registry := providers.NewRegistry(cfg.Providers.AllowUnsigned)
+	registry.Register(providers.NewLuma(lumaClient), cfg.LumaWebhookKey)
+	registry.Register(providers.NewEventbrite(cfg.Providers.EventbriteToken, "", cfg.Providers.EventbriteWalletQuestion), cfg.Providers.EventbriteWebhookSecret)
+	registry.Register(providers.NewPretix(cfg.Providers.PretixToken, cfg.Providers.PretixURL, cfg.Providers.PretixWalletQuestion), cfg.Providers.PretixWebhookSecret)
+	registry.Register(providers.NewTito(cfg.Providers.TitoWalletQuestion), cfg.Providers.TitoWebhookSecret)
+	registry.Register(providers.NewGeneric(cfg.Providers.GenericWalletQuestion), cfg.Providers.GenericWebhookSecret)
+
 	// API routes
 	api := r.Group("/api")
 
+	// Claim links for attendees who checked in without a wallet
+	claimSecret := cfg.ClaimSecret
+	if claimSecret == "" {
+		claimSecret = cfg.JWTSecret
+	}
+	claimService := claims.NewService(
+		claimRepo,
+		eventRepo,
+		minter,
+		claims.NewNotifier(cfg.SMTP),
+		cfg.ClaimBaseURL,
+		claimSecret,
+		time.Duration(cfg.ClaimLinkTTLHours)*time.Hour,
+	)
+
+	// Badges claimed with a secret code, from an allowlist or while claims are open
+	claimer := claims.NewClaimer(policyRepo, nftRepo, minter)
+	claimLimit := 0
+	if cfg.RateLimit.Enabled {
+		claimLimit = cfg.RateLimit.ClaimsPerMinute
+	}
+	claimLimiter := NewAttemptLimiter(claimLimit, time.Minute)
+
+	// Badge artwork, drawn from the configured template
+	badgeTemplate, err := badges.LoadTemplate(cfg.BadgeTemplate)
+	if err != nil {
+		log.Printf("Failed to load badge template, using the default: %v", err)
+		badgeTemplate = badges.DefaultTemplate()
+	}
+	badgeRenderer := badges.NewRenderer(badgeTemplate)
+
+	// QR check-in with rotating codes, independent of Luma
+	checkInService := checkin.NewService(checkinRepo, nftRepo, minter, cfg.CheckInBaseURL)
+
 	// Public routes
 	{
 		// Initialize handlers
-		lumaClient := luma.NewClient(cfg.LumaAPIKey)
-		lumaHandler := NewLumaHandler(lumaClient, polkadotClient, nftRepo, eventRepo, userRepo)
+		webhookHandler := NewWebhookHandler(registry, eventRepo, sourceRepo, minter, claimService)
+		claimHandler := NewClaimHandler(claimService)
+		checkInHandler := NewCheckInHandler(eventRepo, checkInService)
+		eventClaimHandler := NewEventClaimHandler(eventRepo, policyRepo, claimer, claimLimiter)
+		metadataHandler := NewMetadataHandler(nftRepo)
+		badgeHandler := NewBadgeHandler(eventRepo, nftRepo, badgeRenderer)
 
-		// Webhook endpoint for Luma check-ins
-		api.POST("/webhook/check-in", lumaHandler.CheckInWebhook)
+		// Webhook endpoints for check-ins; the original route takes Luma check-ins
+		api.POST("/webhook/check-in", webhookHandler.CheckInWebhook)
+		api.POST("/webhook/:provider", webhookHandler.CheckInWebhook)
+
+		// Claim links
+		api.GET("/claim/:token", claimHandler.GetClaim)
+		api.POST("/claim/:token", claimHandler.RedeemClaim)
+
+		// QR check-in
+		api.POST("/events/:id/checkin", checkInHandler.CheckIn)
+
+		// Badges claimed under the event's claim policy
+		api.GET("/events/:id/claim", eventClaimHandler.GetEventClaim)
+		api.POST("/events/:id/claim", eventClaimHandler.ClaimEventBadge)
+
+		// NFT metadata the chain's metadata URIs point at
+		api.GET("/metadata/:nft_id", metadataHandler.GetMetadata)
+
+		// Badge artwork of events and their NFTs
+		api.GET("/events/:id/badge.png", badgeHandler.GetEventBadgePNG)
+		api.GET("/events/:id/badge.svg", badgeHandler.GetEventBadgeSVG)
+		api.GET("/nfts/:id/image", badgeHandler.GetNFTImage)
 	}
 
 	// Admin routes (protected)
@@ -48,15 +144,53 @@ func NewRouter(
 	admin.Use(BasicAuthMiddleware(cfg))
 	{
 		// Initialize handlers
-		adminHandler := NewAdminHandler(polkadotClient, eventRepo, nftRepo, userRepo, permRepo)
+		adminHandler := NewAdminHandler(chain, eventRepo, nftRepo, userRepo, permRepo, claimRepo, mintQueue, lumaSync, checkInService, sourceRepo, registry, policyRepo)
 
 		// Event management
 		admin.POST("/events", adminHandler.CreateEvent)
 		admin.GET("/events", adminHandler.ListEvents)
 		admin.GET("/events/:id", adminHandler.GetEvent)
+		admin.PUT("/events/:id", adminHandler.UpdateEvent)
+		admin.PATCH("/events/:id", adminHandler.PatchEvent)
+		admin.DELETE("/events/:id", adminHandler.DeleteEvent)
+		admin.POST("/events/:id/mint", adminHandler.MintForEvent)
+		admin.GET("/mint-jobs/:id", adminHandler.GetMintJob)
+
+		// Claims parked for attendees without a wallet
+		admin.GET("/claims", adminHandler.ClaimCounts)
+		admin.GET("/events/:id/claims", adminHandler.ListUnclaimed)
+
+		// Claim policies and allowlists
+		admin.GET("/events/:id/claim-policy", adminHandler.GetClaimPolicy)
+		admin.PUT("/events/:id/claim-policy", adminHandler.SetClaimPolicy)
+		admin.GET("/events/:id/allowlist", adminHandler.GetAllowlist)
+		admin.POST("/events/:id/allowlist", adminHandler.AddToAllowlist)
+		admin.DELETE("/events/:id/allowlist/:wallet", adminHandler.RemoveFromAllowlist)
+
+		// QR check-in settings and codes
+		admin.GET("/events/:id/checkin", adminHandler.GetCheckInSettings)
+		admin.PUT("/events/:id/checkin", adminHandler.UpdateCheckInSettings)
+		admin.GET("/events/:id/checkin/code", adminHandler.GetCheckInCode)
+		admin.POST("/events/:id/checkin/rotate", adminHandler.RotateCheckInSecret)
+
+		// Ticketing providers events take check-ins from
+		admin.GET("/events/:id/sources", adminHandler.ListEventSources)
+		admin.PUT("/events/:id/sources/:provider", adminHandler.SaveEventSource)
+		admin.DELETE("/events/:id/sources/:provider", adminHandler.DeleteEventSource)
+
+		// Luma import and sync
+		admin.GET("/luma/events", adminHandler.ListLumaEvents)
+		admin.POST("/luma/import", adminHandler.ImportLumaEvents)
+		admin.POST("/luma/sync", adminHandler.SyncLumaEvents)
 
 		// NFT management
 		admin.GET("/nfts", adminHandler.ListNFTs)
+		admin.POST("/nfts/:id/transfer", adminHandler.TransferNFT)
+		admin.POST("/nfts/:id/revoke", adminHandler.RevokeNFT)
+		admin.GET("/nfts/:id/history", adminHandler.GetNFTHistory)
+
+		// Chain transactions
+		admin.GET("/transactions/:hash", adminHandler.GetTxStatus)
 	}
 
 	// User routes (protected with JWT)
@@ -64,7 +198,7 @@ func NewRouter(
 	user.Use(JWTAuth(cfg.JWTSecret))
 	{
 		// Initialize handlers
-		userHandler := NewUserHandler(polkadotClient, eventRepo, nftRepo, userRepo)
+		userHandler := NewUserHandler(chain, eventRepo, nftRepo, userRepo)
 
 		// User profile
 		user.GET("/profile", userHandler.GetProfile)
@@ -4,44 +4,61 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
 	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
 	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/polkadot"
 )
 
 // UserHandler handles user-related API endpoints
 type UserHandler struct {
-	polkadotClient *polkadot.Client
-	eventRepo      *database.EventRepository
-	nftRepo        *database.NFTRepository
-	userRepo       *database.UserRepository
+	chain     polkadot.AttendanceChain
+	eventRepo *database.EventRepository
+	nftRepo   *database.NFTRepository
+	userRepo  *database.UserRepository
 }
 
 // NewUserHandler creates a new user API handler
 func NewUserHandler(
-	polkadotClient *polkadot.Client,
+	chain polkadot.AttendanceChain,
 	eventRepo *database.EventRepository,
 	nftRepo *database.NFTRepository,
 	userRepo *database.UserRepository,
 ) *UserHandler {
 	return &UserHandler{
-		polkadotClient: polkadotClient,
-		eventRepo:      eventRepo,
-		nftRepo:        nftRepo,
-		userRepo:       userRepo,
+		chain:     chain,
+		eventRepo: eventRepo,
+		nftRepo:   nftRepo,
+		userRepo:  userRepo,
 	}
 }
 
-// GetProfile gets the user's profile information
-func (h *UserHandler) GetProfile(c *gin.Context) {
-	// Get user ID from the JWT
+// walletFromContext returns the canonical wallet address of the authenticated user
+func walletFromContext(c *gin.Context) (string, bool) {
 	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in token"})
+		return "", false
+	}
+
+	wallet, err := address.Canonical(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid wallet address in token"})
+		return "", false
+	}
+
+	return wallet, true
+}
+
+// GetProfile gets the user's profile information
+func (h *UserHandler) GetProfile(c *gin.Context) {
+	// Get wallet address from the JWT
+	wallet, ok := walletFromContext(c)
+	if !ok {
 		return
 	}
 
 	// Get user by wallet address (user ID is the wallet address)
-	user, err := h.userRepo.GetByWalletAddress(userID.(string))
+	user, err := h.userRepo.GetByWalletAddress(c.Request.Context(), wallet)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -53,25 +70,24 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 	}
 
 	// Update last login time
-	if err := h.userRepo.UpdateLastLogin(user.ID); err != nil {
+	if err := h.userRepo.UpdateLastLogin(c.Request.Context(), user.ID); err != nil {
 		// Log the error but continue
 		c.Error(err)
 	}
 
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, formatUser(c, *user))
 }
 
 // GetUserEvents gets all events the user has permission for
 func (h *UserHandler) GetUserEvents(c *gin.Context) {
-	// Get user ID from the JWT
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in token"})
+	// Get wallet address from the JWT
+	wallet, ok := walletFromContext(c)
+	if !ok {
 		return
 	}
 
 	// Get user by wallet address
-	user, err := h.userRepo.GetByWalletAddress(userID.(string))
+	user, err := h.userRepo.GetByWalletAddress(c.Request.Context(), wallet)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -83,30 +99,45 @@ func (h *UserHandler) GetUserEvents(c *gin.Context) {
 	}
 
 	// For now, return all events (we'll implement permissions later)
-	events, err := h.eventRepo.GetAll()
+	events, err := h.eventRepo.GetAll(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, events)
+	c.JSON(http.StatusOK, formatEvents(c, events))
 }
 
-// GetUserNFTs gets all NFTs owned by the user
+// GetUserNFTs gets a page of the NFTs owned by the user, filtered by the
+// event_id and confirmed query parameters
 func (h *UserHandler) GetUserNFTs(c *gin.Context) {
-	// Get user ID from the JWT
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in token"})
+	// Get wallet address from the JWT
+	wallet, ok := walletFromContext(c)
+	if !ok {
+		return
+	}
+
+	page, ok := pageFromQuery(c)
+	if !ok {
 		return
 	}
 
-	// Get NFTs owned by the user
-	nfts, err := h.nftRepo.GetAllByOwner(userID.(string))
+	filter, ok := nftFilterFromQuery(c)
+	if !ok {
+		return
+	}
+
+	// Only the user's NFTs that haven't been burned
+	burned := false
+	filter.Owner = wallet
+	filter.Burned = &burned
+
+	nfts, info, err := h.nftRepo.List(c.Request.Context(), filter, page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondListError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, nfts)
-} 
\ No newline at end of file
+	setPageHeaders(c, info)
+	c.JSON(http.StatusOK, formatNFTs(c, nfts))
+}
@@ -0,0 +1,238 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/claims"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/luma"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/minting"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/providers"
+)
+
+// maxWebhookSize caps the size of webhook bodies
+const maxWebhookSize = 1 << 20
+
+// WebhookHandler handles check-in webhooks from ticketing providers
+type WebhookHandler struct {
+	providers  *providers.Registry
+	eventRepo  *database.EventRepository
+	sourceRepo *database.EventSourceRepository
+	minter     *minting.Minter
+	claims     *claims.Service
+}
+
+// NewWebhookHandler creates a new check-in webhook handler
+func NewWebhookHandler(
+	registry *providers.Registry,
+	eventRepo *database.EventRepository,
+	sourceRepo *database.EventSourceRepository,
+	minter *minting.Minter,
+	claims *claims.Service,
+) *WebhookHandler {
+	return &WebhookHandler{
+		providers:  registry,
+		eventRepo:  eventRepo,
+		sourceRepo: sourceRepo,
+		minter:     minter,
+		claims:     claims,
+	}
+}
+
+// CheckInWebhook handles a check-in webhook from the provider in the URL,
+// or from Luma on the original check-in route
+func (h *WebhookHandler) CheckInWebhook(c *gin.Context) {
+	name := c.Param("provider")
+	if name == "" {
+		name = "luma"
+	}
+
+	provider, secret, ok := h.providers.Get(name)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown check-in provider"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	// Parse webhook payload
+	checkIn, err := provider.ParseCheckIn(body)
+	if errors.Is(err, providers.ErrIgnored) {
+		c.JSON(http.StatusOK, gin.H{"success": true, "ignored": true, "message": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	// Events linked to the provider's event can set their own webhook secret and wallet question
+	source, err := h.sourceRepo.GetByExternalID(c.Request.Context(), name, checkIn.EventID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get event source: %v", err)})
+		return
+	}
+	walletQuestion := ""
+	if source != nil {
+		if source.WebhookSecret != "" {
+			secret = source.WebhookSecret
+		}
+		walletQuestion = source.WalletQuestion
+	}
+
+	if err := h.providers.Verify(provider, c.Request, body, secret); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Find the local event; the chain client maps it to its chain event ID
+	eventDetails, err := h.findEvent(c.Request.Context(), name, source, checkIn)
+	if err != nil {
+		respondProviderError(c, "Failed to get event", err)
+		return
+	}
+	if eventDetails == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("No event takes check-ins from %s event %s", name, checkIn.EventID)})
+		return
+	}
+
+	if !eventDetails.Mintable() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Event is not open for minting"})
+		return
+	}
+
+	// Get attendee details from the provider
+	attendee, err := provider.GetAttendee(c.Request.Context(), checkIn, walletQuestion)
+	if err != nil {
+		respondProviderError(c, "Failed to get attendee", err)
+		return
+	}
+	if name != "luma" {
+		// Keep attendee IDs of different providers apart in claims
+		attendee.ID = name + ":" + attendee.ID
+	}
+
+	// Attendees without a valid wallet address get a link to claim their NFT later
+	wallet, err := address.Canonical(attendee.WalletAddress)
+	if err != nil {
+		h.parkClaim(c, eventDetails, attendee, err)
+		return
+	}
+
+	// Store and mint the NFT
+	nft, err := h.minter.Mint(c.Request.Context(), eventDetails, wallet, attendee.Name)
+	if errors.Is(err, database.ErrEventNotMintable) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Event is not open for minting"})
+		return
+	}
+	if errors.Is(err, database.ErrAlreadyMinted) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to mint NFT: %v", err)})
+		return
+	}
+
+	// Return success response
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"nft_id":  nft.ID,
+		"message": fmt.Sprintf("Successfully minted NFT for %s at %s", attendee.Name, eventDetails.Name),
+	})
+}
+
+// findEvent finds the local event a check-in is for: the event linked to the
+// provider's event or, for Luma, the event imported from it
+func (h *WebhookHandler) findEvent(ctx context.Context, provider string, source *models.EventSource, checkIn *models.CheckInEvent) (*models.Event, error) {
+	if source != nil {
+		event, err := h.eventRepo.GetByID(ctx, source.EventID)
+		if err != nil || event == nil || event.Status == models.EventDeleted {
+			return nil, err
+		}
+		return event, nil
+	}
+
+	if provider != "luma" {
+		return nil, nil
+	}
+
+	return h.eventRepo.GetByLumaEventID(ctx, checkIn.EventID)
+}
+
+// parkClaim stores a pending claim for an attendee whose wallet address is
+// missing or invalid and sends them a claim link
+func (h *WebhookHandler) parkClaim(c *gin.Context, event *models.Event, attendee *models.Attendee, walletErr error) {
+	reason := "Attendee has no wallet address"
+	if attendee.WalletAddress != "" {
+		reason = fmt.Sprintf("Attendee wallet address is invalid: %v", walletErr)
+	}
+
+	claim, err := h.claims.Park(c.Request.Context(), event, attendee)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to park claim: %v", err)})
+		return
+	}
+
+	if claim.Status == models.ClaimClaimed {
+		c.JSON(http.StatusOK, gin.H{
+			"success":  true,
+			"claim_id": claim.ID,
+			"status":   claim.Status,
+			"nft_id":   claim.NFTID,
+			"message":  fmt.Sprintf("%s has already claimed their NFT for %s", attendee.Name, event.Name),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success":  true,
+		"claim_id": claim.ID,
+		"status":   claim.Status,
+		"message":  fmt.Sprintf("%s. Sent a claim link for %s instead", reason, event.Name),
+	})
+}
+
+// respondProviderError maps ticketing provider errors to a response
+func respondProviderError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, providers.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("%s: %v", message, err)})
+	case errors.Is(err, providers.ErrUnavailable):
+		c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("%s: %v", message, err)})
+	default:
+		respondLumaError(c, message, err)
+	}
+}
+
+// respondLumaError maps Luma client errors to a response
+func respondLumaError(c *gin.Context, message string, err error) {
+	var apiErr *luma.APIError
+	switch {
+	case errors.Is(err, luma.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("%s: %v", message, err)})
+	case errors.Is(err, luma.ErrNotConfigured):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": fmt.Sprintf("%s: %v", message, err)})
+	case errors.As(err, &apiErr) && errors.Is(err, luma.ErrRateLimited):
+		if apiErr.RetryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(apiErr.RetryAfter.Seconds())))
+		}
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": fmt.Sprintf("%s: %v", message, err)})
+	case errors.Is(err, luma.ErrUnauthorized), errors.As(err, &apiErr):
+		c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("%s: %v", message, err)})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("%s: %v", message, err)})
+	}
+}
@@ -0,0 +1,177 @@
+package badges
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Badge geometry, in pixels. The SVG and PNG renderers share it.
+const (
+	size       = 600
+	center     = size / 2
+	ringRadius = 282
+	ringWidth  = 12
+	textWidth  = 420 // widest line inside the ring
+	logoSize   = 100
+	logoY      = 70
+	titleSize  = 18
+	detailSize = 22
+	numberSize = 32
+	nameLines  = 3
+	// The event name shrinks from maxNameSize until it fits on nameLines lines
+	maxNameSize = 40
+	minNameSize = 28
+)
+
+var (
+	regularFont = mustParseFont(goregular.TTF)
+	boldFont    = mustParseFont(gobold.TTF)
+)
+
+func mustParseFont(data []byte) *opentype.Font {
+	f, err := opentype.Parse(data)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// newFace returns a face of a font. Faces aren't safe for concurrent use,
+// so each render makes its own.
+func newFace(f *opentype.Font, size float64) font.Face {
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		panic(err)
+	}
+	return face
+}
+
+// line is a line of text centered at a baseline
+type line struct {
+	Text string
+	Y    int
+}
+
+// layout places the text of a badge
+type layout struct {
+	Title     string
+	HasLogo   bool
+	TitleY    int
+	Name      []line
+	NameSize  int
+	Date      string
+	DateY     int
+	Location  string
+	LocationY int
+	Number    string
+	NumberY   int
+}
+
+// newLayout lays out a badge. The event name is set in the largest size
+// that fits it on nameLines lines.
+func newLayout(title string, badge Badge, hasLogo bool) layout {
+	l := layout{Title: title, HasLogo: hasLogo}
+
+	y := 200
+	if hasLogo {
+		y = logoY + logoSize + 40
+	}
+	l.TitleY = y
+
+	l.NameSize = maxNameSize
+	var lines []string
+	for ; ; l.NameSize -= 4 {
+		face := newFace(boldFont, float64(l.NameSize))
+		lines = wrap(face, badge.EventName, textWidth)
+		if len(lines) > nameLines && l.NameSize <= minNameSize {
+			lines = lines[:nameLines]
+			lines[nameLines-1] = truncate(face, lines[nameLines-1]+" …", textWidth)
+		}
+		face.Close()
+		if len(lines) <= nameLines {
+			break
+		}
+	}
+
+	y += l.NameSize + 16
+	for _, text := range lines {
+		l.Name = append(l.Name, line{Text: text, Y: y})
+		y += l.NameSize * 6 / 5
+	}
+
+	detail := newFace(regularFont, detailSize)
+	defer detail.Close()
+	l.Date = truncate(detail, formatDate(badge.Date), textWidth)
+	l.Location = truncate(detail, badge.Location, textWidth)
+	l.DateY = y + 16
+	l.LocationY = l.DateY + detailSize*3/2
+
+	if badge.Number > 0 {
+		l.Number = "#" + strconv.Itoa(badge.Number)
+		l.NumberY = size - 70
+	}
+
+	return l
+}
+
+// wrap breaks text into lines no wider than width
+func wrap(face font.Face, text string, width int) []string {
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if current != "" && font.MeasureString(face, candidate).Ceil() > width {
+			lines = append(lines, current)
+			candidate = word
+		}
+		current = candidate
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	for i, text := range lines {
+		lines[i] = truncate(face, text, width)
+	}
+	return lines
+}
+
+// truncate shortens text to fit width, ending it with an ellipsis
+func truncate(face font.Face, text string, width int) string {
+	if font.MeasureString(face, text).Ceil() <= width {
+		return text
+	}
+	runes := []rune(strings.TrimSuffix(text, " …"))
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := strings.TrimSpace(string(runes)) + "…"
+		if font.MeasureString(face, candidate).Ceil() <= width {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// formatDate spells out a YYYY-MM-DD date
+func formatDate(date string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return t.Format("January 2, 2006")
+}
+
+// centered returns the dot that centers text horizontally at a baseline
+func centered(face font.Face, text string, baseline int) fixed.Point26_6 {
+	width := font.MeasureString(face, text)
+	return fixed.Point26_6{X: fixed.I(center) - width/2, Y: fixed.I(baseline)}
+}
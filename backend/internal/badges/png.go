@@ -0,0 +1,104 @@
+package badges
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+)
+
+// drawBadge draws a badge with the same layout as the built-in SVG
+func drawBadge(tmpl *Template, l layout, logo image.Image) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	background := rgba(tmpl.Background)
+	accent := rgba(tmpl.Accent)
+	foreground := rgba(tmpl.Foreground)
+
+	draw.Draw(img, img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	drawRing(img, accent)
+
+	if logo != nil {
+		drawLogo(img, logo)
+	}
+
+	drawText(img, regularFont, titleSize, accent, l.Title, l.TitleY)
+	for _, name := range l.Name {
+		drawText(img, boldFont, l.NameSize, foreground, name.Text, name.Y)
+	}
+	drawText(img, regularFont, detailSize, foreground, l.Date, l.DateY)
+	drawText(img, regularFont, detailSize, foreground, l.Location, l.LocationY)
+	if l.Number != "" {
+		drawText(img, boldFont, numberSize, accent, l.Number, l.NumberY)
+	}
+
+	return img
+}
+
+// drawRing draws the circle around the badge, antialiased by how much of
+// each pixel it covers
+func drawRing(img *image.RGBA, c color.RGBA) {
+	inner := float64(ringRadius - ringWidth/2)
+	outer := float64(ringRadius + ringWidth/2)
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			d := math.Hypot(float64(x)+0.5-center, float64(y)+0.5-center)
+			coverage := math.Min(d-inner, outer-d) + 0.5
+			if coverage <= 0 {
+				continue
+			}
+			if coverage > 1 {
+				coverage = 1
+			}
+			img.SetRGBA(x, y, blend(img.RGBAAt(x, y), c, coverage))
+		}
+	}
+}
+
+// blend mixes c over dst by alpha
+func blend(dst, c color.RGBA, alpha float64) color.RGBA {
+	mix := func(a, b uint8) uint8 {
+		return uint8(float64(a)*(1-alpha) + float64(b)*alpha + 0.5)
+	}
+	return color.RGBA{R: mix(dst.R, c.R), G: mix(dst.G, c.G), B: mix(dst.B, c.B), A: 0xff}
+}
+
+// drawLogo scales the logo into its box, keeping its aspect ratio
+func drawLogo(img *image.RGBA, logo image.Image) {
+	bounds := logo.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return
+	}
+
+	width, height := logoSize, logoSize
+	if bounds.Dx() > bounds.Dy() {
+		height = logoSize * bounds.Dy() / bounds.Dx()
+	} else {
+		width = logoSize * bounds.Dx() / bounds.Dy()
+	}
+
+	x := center - width/2
+	y := logoY + (logoSize-height)/2
+	draw.CatmullRom.Scale(img, image.Rect(x, y, x+width, y+height), logo, bounds, draw.Over, nil)
+}
+
+// drawText draws a line of text centered at a baseline
+func drawText(img *image.RGBA, f *opentype.Font, fontSize int, c color.RGBA, text string, baseline int) {
+	if text == "" {
+		return
+	}
+
+	face := newFace(f, float64(fontSize))
+	defer face.Close()
+
+	drawer := font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  centered(face, text, baseline),
+	}
+	drawer.DrawString(text)
+}
@@ -0,0 +1,296 @@
+package badges
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"image"
+	_ "image/gif" // logo formats
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// Badge formats
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+)
+
+// maxLogoSize caps the size of a logo download, in bytes
+const maxLogoSize = 2 << 20
+
+// maxLogoPixels caps the width times height of a logo, since a small file
+// can decode to a huge image
+const maxLogoPixels = 4 << 20
+
+// ErrUnknownFormat is returned for formats other than FormatPNG and FormatSVG
+var ErrUnknownFormat = errors.New("unknown badge format")
+
+// Badge is what a badge shows
+type Badge struct {
+	EventName string
+	Date      string // YYYY-MM-DD
+	Location  string
+	LogoURL   string
+	Number    int // attendee number, 0 for the event's own badge
+}
+
+// ContentType returns the MIME type of a badge format
+func ContentType(format string) string {
+	if format == FormatSVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+// Renderer draws badges from a template and caches them
+type Renderer struct {
+	template   *Template
+	httpClient *http.Client
+	badges     *cache.Cache
+	logos      *cache.Cache
+}
+
+// NewRenderer creates a badge renderer for a template
+func NewRenderer(tmpl *Template) *Renderer {
+	return &Renderer{
+		template:   tmpl,
+		httpClient: newLogoClient(),
+		badges:     cache.New(time.Hour, 10*time.Minute),
+		logos:      cache.New(time.Hour, 10*time.Minute),
+	}
+}
+
+// Render draws the badge of an event, numbered for an attendee's NFT.
+// Badges are cached per event version, so edits show up right away.
+func (r *Renderer) Render(ctx context.Context, event *models.Event, number int, format string) ([]byte, error) {
+	if format != FormatPNG && format != FormatSVG {
+		return nil, ErrUnknownFormat
+	}
+
+	key := fmt.Sprintf("%d:%d:%d:%s", event.ID, event.Version, number, format)
+	if data, ok := r.badges.Get(key); ok {
+		return data.([]byte), nil
+	}
+
+	badge := Badge{
+		EventName: event.Name,
+		Date:      event.Date,
+		Location:  event.Location,
+		LogoURL:   event.LogoURL,
+		Number:    number,
+	}
+	if badge.LogoURL == "" {
+		badge.LogoURL = r.template.LogoURL
+	}
+
+	var data []byte
+	var err error
+	if format == FormatSVG {
+		data, err = r.renderSVG(ctx, badge)
+	} else {
+		data, err = r.renderPNG(ctx, badge)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r.badges.SetDefault(key, data)
+	return data, nil
+}
+
+// logo is a downloaded logo
+type logo struct {
+	data        []byte
+	contentType string
+	image       image.Image
+}
+
+// loadLogo downloads and decodes a logo. Logos that can't be loaded are left
+// off the badge, and the failure is cached for a few minutes.
+func (r *Renderer) loadLogo(ctx context.Context, url string) *logo {
+	if url == "" {
+		return nil
+	}
+	if cached, ok := r.logos.Get(url); ok {
+		return cached.(*logo)
+	}
+
+	// A request that goes away mustn't leave the logo off for everyone else
+	l, err := r.fetchLogo(context.WithoutCancel(ctx), url)
+	if err != nil {
+		log.Printf("Failed to load badge logo %s: %v", url, err)
+		r.logos.Set(url, l, 5*time.Minute)
+		return nil
+	}
+
+	r.logos.SetDefault(url, l)
+	return l
+}
+
+// fetchLogo downloads a PNG, JPEG or GIF logo
+func (r *Renderer) fetchLogo(ctx context.Context, logoURL string) (*logo, error) {
+	parsed, err := url.Parse(logoURL)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported logo URL scheme %q", parsed.Scheme)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, logoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxLogoSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxLogoSize {
+		return nil, fmt.Errorf("logo is larger than %d bytes", maxLogoSize)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode logo: %w", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxLogoPixels {
+		return nil, fmt.Errorf("logo is %dx%d, more than %d pixels", config.Width, config.Height, maxLogoPixels)
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode logo: %w", err)
+	}
+
+	return &logo{data: data, contentType: "image/" + format, image: img}, nil
+}
+
+// newLogoClient creates the HTTP client logos are downloaded with. Logo URLs
+// are set by organizers, so it only connects to public addresses, which
+// redirects can't get around either.
+func newLogoClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("logo host %s is not a public address", host)
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			// No proxy, since the dialer would check the proxy's address
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+		},
+	}
+}
+
+// isPublicIP reports whether an IP address is reachable on the internet
+// rather than a loopback, private, link-local or unspecified address
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast()
+}
+
+// svgData is what the SVG template is executed with. Text is XML-escaped.
+type svgData struct {
+	layout
+	Size, Center, RingRadius, RingWidth int
+	LogoX, LogoY, LogoSize              int
+	TitleSize, DetailSize, NumberSize   int
+	Background, Accent, Foreground      string
+	Logo                                string // data: URI, so the SVG is self-contained
+	// The event's details before they're wrapped and formatted
+	EventName, EventDate, EventLocation string
+}
+
+// renderSVG executes the template's SVG layout
+func (r *Renderer) renderSVG(ctx context.Context, badge Badge) ([]byte, error) {
+	l := r.loadLogo(ctx, badge.LogoURL)
+	data := svgData{
+		layout:        newLayout(r.template.Title, badge, l != nil),
+		Size:          size,
+		Center:        center,
+		RingRadius:    ringRadius,
+		RingWidth:     ringWidth,
+		LogoX:         center - logoSize/2,
+		LogoY:         logoY,
+		LogoSize:      logoSize,
+		TitleSize:     titleSize,
+		DetailSize:    detailSize,
+		NumberSize:    numberSize,
+		Background:    r.template.Background,
+		Accent:        r.template.Accent,
+		Foreground:    r.template.Foreground,
+		EventName:     html.EscapeString(badge.EventName),
+		EventDate:     html.EscapeString(badge.Date),
+		EventLocation: html.EscapeString(badge.Location),
+	}
+	if l != nil {
+		data.Logo = "data:" + l.contentType + ";base64," + base64.StdEncoding.EncodeToString(l.data)
+	}
+
+	data.Title = html.EscapeString(data.Title)
+	data.Date = html.EscapeString(data.Date)
+	data.Location = html.EscapeString(data.Location)
+	for i := range data.Name {
+		data.Name[i].Text = html.EscapeString(data.Name[i].Text)
+	}
+
+	var buf bytes.Buffer
+	if err := r.template.svg.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render SVG badge: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// renderPNG draws the badge and encodes it as PNG
+func (r *Renderer) renderPNG(ctx context.Context, badge Badge) ([]byte, error) {
+	l := r.loadLogo(ctx, badge.LogoURL)
+	var logoImage image.Image
+	if l != nil {
+		logoImage = l.image
+	}
+
+	img := drawBadge(r.template, newLayout(r.template.Title, badge, l != nil), logoImage)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode PNG badge: %w", err)
+	}
+	return buf.Bytes(), nil
+}
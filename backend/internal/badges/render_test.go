@@ -0,0 +1,279 @@
+package badges
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+func testEvent() *models.Event {
+	return &models.Event{
+		ID:       4,
+		Version:  1,
+		Name:     "Sub0 <&> Co",
+		Date:     "2025-06-01",
+		Location: "Lisbon",
+	}
+}
+
+// encodePNG encodes a blank image of the given size
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// logoServer serves PNG logos by path and lets the renderer reach it even
+// though it listens on a loopback address
+func logoServer(t *testing.T, r *Renderer, logos map[string][]byte) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		data, ok := logos[req.URL.Path]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	r.httpClient = server.Client()
+	return server
+}
+
+func TestRenderPNG(t *testing.T) {
+	r := NewRenderer(DefaultTemplate())
+
+	data, err := r.Render(context.Background(), testEvent(), 12, FormatPNG)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("badge isn't a PNG: %v", err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() != size || bounds.Dy() != size {
+		t.Errorf("badge is %dx%d, want %dx%d", bounds.Dx(), bounds.Dy(), size, size)
+	}
+
+	// The corner is outside the ring, so it's the background
+	if got, want := img.At(0, 0), rgba(DefaultTemplate().Background); got != want {
+		t.Errorf("corner = %v, want the background %v", got, want)
+	}
+}
+
+func TestRenderSVG(t *testing.T) {
+	r := NewRenderer(DefaultTemplate())
+
+	data, err := r.Render(context.Background(), testEvent(), 12, FormatSVG)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The event name is escaped, so the SVG stays well-formed
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("badge isn't valid XML: %v\n%s", err, data)
+		}
+		if chars, ok := token.(xml.CharData); ok {
+			text.Write(chars)
+		}
+	}
+
+	for _, want := range []string{"PROOF OF ATTENDANCE", "Sub0 <&> Co", "June 1, 2025", "Lisbon", "#12"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("badge text doesn't contain %q", want)
+		}
+	}
+	if bytes.Contains(data, []byte("<image")) {
+		t.Error("badge without a logo has an image")
+	}
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	r := NewRenderer(DefaultTemplate())
+	if _, err := r.Render(context.Background(), testEvent(), 0, "gif"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("error = %v, want %v", err, ErrUnknownFormat)
+	}
+}
+
+// TestRenderCachesByVersion checks an edited event gets a new badge
+func TestRenderCachesByVersion(t *testing.T) {
+	r := NewRenderer(DefaultTemplate())
+	ctx := context.Background()
+	event := testEvent()
+
+	first, err := r.Render(ctx, event, 0, FormatSVG)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event.Name = "Sub0 Reset"
+	cached, err := r.Render(ctx, event, 0, FormatSVG)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(cached, first) {
+		t.Error("the same version was rendered again")
+	}
+
+	event.Version++
+	edited, err := r.Render(ctx, event, 0, FormatSVG)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(edited, first) || !bytes.Contains(edited, []byte("Sub0 Reset")) {
+		t.Error("the new version shows the old badge")
+	}
+}
+
+func TestRenderLogo(t *testing.T) {
+	r := NewRenderer(DefaultTemplate())
+	server := logoServer(t, r, map[string][]byte{
+		"/logo.png":  encodePNG(t, 64, 64),
+		"/huge.png":  encodePNG(t, 2049, 2049),
+		"/notes.txt": []byte("not an image"),
+	})
+
+	tests := []struct {
+		path     string
+		wantLogo bool
+	}{
+		{"/logo.png", true},
+		{"/huge.png", false},
+		{"/notes.txt", false},
+		{"/missing.png", false},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			// Badges are cached by event, so each logo gets its own
+			event := testEvent()
+			event.ID = uint64(10 + i)
+			event.LogoURL = server.URL + tt.path
+
+			data, err := r.Render(context.Background(), event, 0, FormatSVG)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hasLogo := bytes.Contains(data, []byte(`href="data:image/png;base64,`))
+			if hasLogo != tt.wantLogo {
+				t.Errorf("has logo = %v, want %v", hasLogo, tt.wantLogo)
+			}
+		})
+	}
+}
+
+// TestLogoClientRefusesPrivateAddresses checks organizers can't point the
+// logo fetch at the backend's own network
+func TestLogoClientRefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write(encodePNG(t, 64, 64))
+	}))
+	defer server.Close()
+
+	r := NewRenderer(DefaultTemplate())
+	if _, err := r.fetchLogo(context.Background(), server.URL+"/logo.png"); err == nil {
+		t.Error("logo was fetched from a loopback address")
+	}
+	if _, err := r.fetchLogo(context.Background(), "file:///etc/passwd"); err == nil {
+		t.Error("logo was fetched from a file URL")
+	}
+
+	tests := []struct {
+		ip     string
+		public bool
+	}{
+		{"1.1.1.1", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"10.0.0.8", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"0.0.0.0", false},
+		{"::1", false},
+		{"fd00::1", false},
+	}
+	for _, tt := range tests {
+		if got := isPublicIP(net.ParseIP(tt.ip)); got != tt.public {
+			t.Errorf("isPublicIP(%s) = %v, want %v", tt.ip, got, tt.public)
+		}
+	}
+}
+
+func TestNewLayout(t *testing.T) {
+	short := newLayout("PROOF OF ATTENDANCE", Badge{EventName: "Sub0", Date: "2025-06-01"}, false)
+	if len(short.Name) != 1 || short.NameSize != maxNameSize {
+		t.Errorf("short name has %d lines at size %d, want 1 at %d", len(short.Name), short.NameSize, maxNameSize)
+	}
+	if short.Date != "June 1, 2025" || short.Number != "" {
+		t.Errorf("date = %q, number = %q", short.Date, short.Number)
+	}
+
+	long := newLayout("PROOF OF ATTENDANCE", Badge{
+		EventName: strings.Repeat("Polkadot Decoded Community Meetup ", 10),
+		Number:    7,
+	}, true)
+	if len(long.Name) != nameLines || long.NameSize != minNameSize {
+		t.Errorf("long name has %d lines at size %d, want %d at %d", len(long.Name), long.NameSize, nameLines, minNameSize)
+	}
+	if last := long.Name[nameLines-1].Text; !strings.HasSuffix(last, "…") {
+		t.Errorf("last line %q isn't truncated", last)
+	}
+	if long.Number != "#7" {
+		t.Errorf("number = %q, want #7", long.Number)
+	}
+	if long.TitleY <= short.TitleY {
+		t.Error("the title doesn't move down to make room for the logo")
+	}
+}
+
+func TestLoadTemplate(t *testing.T) {
+	tmpl, err := LoadTemplate("")
+	if err != nil || tmpl.Title != DefaultTemplate().Title {
+		t.Fatalf("LoadTemplate(\"\") = %+v, %v", tmpl, err)
+	}
+
+	dir := t.TempDir()
+	path := dir + "/badge.json"
+	for _, tt := range []struct {
+		json    string
+		wantErr bool
+	}{
+		{`{"title": "I WAS THERE", "accent": "#00ff00"}`, false},
+		{`{"accent": "green"}`, true},
+		{`{"svg_file": "` + dir + `/missing.svg"}`, true},
+	} {
+		if err := os.WriteFile(path, []byte(tt.json), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		tmpl, err := LoadTemplate(path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("LoadTemplate(%s) error = %v, want error %v", tt.json, err, tt.wantErr)
+		}
+		if err == nil && (tmpl.Title != "I WAS THERE" || tmpl.Background != DefaultTemplate().Background) {
+			t.Errorf("LoadTemplate(%s) = %+v, want the title changed and the other defaults kept", tt.json, tmpl)
+		}
+	}
+}
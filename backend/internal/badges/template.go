@@ -0,0 +1,101 @@
+package badges
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"os"
+	"regexp"
+	"text/template"
+)
+
+// hexColor matches the #rrggbb colors a template can use
+var hexColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Template sets how badges look. It's read from a JSON file; fields that are
+// left out keep their defaults.
+type Template struct {
+	Title      string `json:"title"`      // heading above the event name
+	Background string `json:"background"` // #rrggbb
+	Accent     string `json:"accent"`     // ring, heading and attendee number
+	Foreground string `json:"foreground"` // event details
+	LogoURL    string `json:"logo_url"`   // logo of events that don't set their own
+	// SVGFile is a text/template file replacing the built-in SVG layout.
+	// The values it's given are already XML-escaped.
+	SVGFile string `json:"svg_file"`
+
+	svg *template.Template
+}
+
+// DefaultTemplate returns the built-in badge template
+func DefaultTemplate() *Template {
+	return &Template{
+		Title:      "PROOF OF ATTENDANCE",
+		Background: "#1c0533",
+		Accent:     "#e6007a",
+		Foreground: "#ffffff",
+		svg:        template.Must(template.New("badge.svg").Parse(defaultSVG)),
+	}
+}
+
+// LoadTemplate reads a badge template from a JSON file. An empty path
+// returns the default template.
+func LoadTemplate(path string) (*Template, error) {
+	tmpl := DefaultTemplate()
+	if path == "" {
+		return tmpl, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read badge template: %w", err)
+	}
+	if err := json.Unmarshal(data, tmpl); err != nil {
+		return nil, fmt.Errorf("failed to parse badge template: %w", err)
+	}
+
+	for name, value := range map[string]string{"background": tmpl.Background, "accent": tmpl.Accent, "foreground": tmpl.Foreground} {
+		if !hexColor.MatchString(value) {
+			return nil, fmt.Errorf("badge template %s must be a #rrggbb color", name)
+		}
+	}
+
+	if tmpl.SVGFile != "" {
+		svg, err := template.ParseFiles(tmpl.SVGFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse badge SVG template: %w", err)
+		}
+		tmpl.svg = svg
+	}
+
+	return tmpl, nil
+}
+
+// rgba parses a color checked by LoadTemplate
+func rgba(hex string) color.RGBA {
+	var r, g, b uint8
+	fmt.Sscanf(hex, "#%02x%02x%02x", &r, &g, &b)
+	return color.RGBA{R: r, G: g, B: b, A: 0xff}
+}
+
+// defaultSVG is the built-in SVG layout, matching the PNG renderer
+const defaultSVG = `<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="{{.Size}}" height="{{.Size}}" viewBox="0 0 {{.Size}} {{.Size}}">
+  <rect width="{{.Size}}" height="{{.Size}}" fill="{{.Background}}"/>
+  <circle cx="{{.Center}}" cy="{{.Center}}" r="{{.RingRadius}}" fill="none" stroke="{{.Accent}}" stroke-width="{{.RingWidth}}"/>
+  {{- if .Logo}}
+  <image href="{{.Logo}}" x="{{.LogoX}}" y="{{.LogoY}}" width="{{.LogoSize}}" height="{{.LogoSize}}" preserveAspectRatio="xMidYMid meet"/>
+  {{- end}}
+  <g font-family="Go, Helvetica, Arial, sans-serif" text-anchor="middle">
+    <text x="{{.Center}}" y="{{.TitleY}}" font-size="{{.TitleSize}}" fill="{{.Accent}}" letter-spacing="2">{{.Title}}</text>
+    {{- range .Name}}
+    <text x="{{$.Center}}" y="{{.Y}}" font-size="{{$.NameSize}}" font-weight="bold" fill="{{$.Foreground}}">{{.Text}}</text>
+    {{- end}}
+    <text x="{{.Center}}" y="{{.DateY}}" font-size="{{.DetailSize}}" fill="{{.Foreground}}">{{.Date}}</text>
+    <text x="{{.Center}}" y="{{.LocationY}}" font-size="{{.DetailSize}}" fill="{{.Foreground}}">{{.Location}}</text>
+    {{- if .Number}}
+    <text x="{{.Center}}" y="{{.NumberY}}" font-size="{{.NumberSize}}" font-weight="bold" fill="{{.Accent}}">{{.Number}}</text>
+    {{- end}}
+  </g>
+</svg>
+`
@@ -0,0 +1,233 @@
+package checkin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/minting"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// Errors returned when checking in with a QR code
+var (
+	ErrNotEnabled       = errors.New("QR check-in is not enabled for this event")
+	ErrInvalidCode      = errors.New("check-in code is invalid or has expired")
+	ErrInvalidSignature = errors.New("signature doesn't match the wallet address")
+	ErrInvalidWallet    = errors.New("invalid wallet address")
+	ErrLocationRequired = errors.New("location is required to check in to this event")
+	ErrOutsideGeofence  = errors.New("location is too far from the event")
+	ErrAlreadyCheckedIn = errors.New("wallet has already checked in to this event")
+	ErrInvalidGeofence  = errors.New("geofence needs a latitude, longitude and positive radius")
+	ErrInvalidPeriod    = errors.New("code period must be between 10 and 300 seconds")
+)
+
+// Limits on how often codes change
+const (
+	minPeriod = 10 * time.Second
+	maxPeriod = 5 * time.Minute
+)
+
+// Request is an attendee's check-in with a scanned code
+type Request struct {
+	Code          string
+	WalletAddress string
+	Signature     string
+	Name          string
+	Latitude      *float64
+	Longitude     *float64
+}
+
+// Service runs QR check-in: organizers display a rotating code and
+// attendees who submit it with a signature from their wallet get an NFT
+type Service struct {
+	checkinRepo *database.CheckInRepository
+	nftRepo     *database.NFTRepository
+	minter      *minting.Minter
+	linkBase    string
+}
+
+// NewService creates a new check-in service. QR codes link to linkBase
+// followed by the event ID and the code.
+func NewService(
+	checkinRepo *database.CheckInRepository,
+	nftRepo *database.NFTRepository,
+	minter *minting.Minter,
+	linkBase string,
+) *Service {
+	return &Service{
+		checkinRepo: checkinRepo,
+		nftRepo:     nftRepo,
+		minter:      minter,
+		linkBase:    strings.TrimRight(linkBase, "/"),
+	}
+}
+
+// Message returns the message a wallet signs to check in to an event
+func Message(eventID uint64, code string) string {
+	return fmt.Sprintf("Check in to event %d with code %s", eventID, code)
+}
+
+// Settings gets an event's check-in settings, or nil if check-in was never set up
+func (s *Service) Settings(ctx context.Context, eventID uint64) (*models.CheckInSettings, error) {
+	return s.checkinRepo.GetSettings(ctx, eventID)
+}
+
+// Count counts an event's QR check-ins
+func (s *Service) Count(ctx context.Context, eventID uint64) (int, error) {
+	return s.checkinRepo.CountByEvent(ctx, eventID)
+}
+
+// Configure saves an event's check-in settings, generating the code secret
+// the first time. The geofence is either fully set or cleared.
+func (s *Service) Configure(ctx context.Context, settings *models.CheckInSettings) error {
+	if settings.PeriodSeconds == 0 {
+		settings.PeriodSeconds = int(DefaultPeriod / time.Second)
+	}
+	period := settings.Period()
+	if period < minPeriod || period > maxPeriod {
+		return ErrInvalidPeriod
+	}
+
+	geofence := []*float64{settings.Latitude, settings.Longitude, settings.RadiusMeters}
+	set := 0
+	for _, value := range geofence {
+		if value != nil {
+			set++
+		}
+	}
+	if set != 0 && set != len(geofence) {
+		return ErrInvalidGeofence
+	}
+	if settings.HasGeofence() && (*settings.RadiusMeters <= 0 ||
+		*settings.Latitude < -90 || *settings.Latitude > 90 ||
+		*settings.Longitude < -180 || *settings.Longitude > 180) {
+		return ErrInvalidGeofence
+	}
+
+	existing, err := s.checkinRepo.GetSettings(ctx, settings.EventID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		settings.Secret = existing.Secret
+	} else if settings.Secret, err = NewSecret(); err != nil {
+		return err
+	}
+
+	return s.checkinRepo.SaveSettings(ctx, settings)
+}
+
+// Rotate replaces an event's code secret, so codes that were already
+// displayed or shared stop working
+func (s *Service) Rotate(ctx context.Context, eventID uint64) (*models.CheckInSettings, error) {
+	settings, err := s.checkinRepo.GetSettings(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		return nil, ErrNotEnabled
+	}
+
+	if settings.Secret, err = NewSecret(); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkinRepo.SaveSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// CurrentCode returns the code to display for an event, when it changes and
+// the link to encode in the QR code
+func (s *Service) CurrentCode(settings *models.CheckInSettings, now time.Time) (string, time.Time, string) {
+	code, validUntil := Code(settings.Secret, settings.Period(), now)
+	link := fmt.Sprintf("%s/%d?code=%s", s.linkBase, settings.EventID, code)
+	return code, validUntil, link
+}
+
+// CheckIn mints an event's NFT to the wallet in req once the code, signature
+// and location check out. Each wallet checks in once per event. As with
+// other mints, an NFT that is stored but fails on chain is returned along
+// with the error.
+func (s *Service) CheckIn(ctx context.Context, event *models.Event, req Request) (*models.NFT, error) {
+	settings, err := s.checkinRepo.GetSettings(ctx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil || !settings.Enabled {
+		return nil, ErrNotEnabled
+	}
+
+	if !event.Mintable() {
+		return nil, database.ErrEventNotMintable
+	}
+
+	wallet, err := address.Canonical(req.WalletAddress)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWallet, err)
+	}
+
+	code := strings.TrimSpace(req.Code)
+	if !VerifyCode(settings.Secret, settings.Period(), code, time.Now()) {
+		return nil, ErrInvalidCode
+	}
+
+	if !address.VerifySignature(req.WalletAddress, Message(event.ID, code), req.Signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	if settings.HasGeofence() {
+		if req.Latitude == nil || req.Longitude == nil {
+			return nil, ErrLocationRequired
+		}
+		distance := distanceMeters(*settings.Latitude, *settings.Longitude, *req.Latitude, *req.Longitude)
+		if distance > *settings.RadiusMeters {
+			return nil, ErrOutsideGeofence
+		}
+	}
+
+	// Wallets that already hold the event's NFT, however it was minted,
+	// don't get another one
+	owned, err := s.nftRepo.HasOwner(ctx, event.ID, wallet)
+	if err != nil {
+		return nil, err
+	}
+	if owned {
+		return nil, ErrAlreadyCheckedIn
+	}
+
+	record := &models.CheckIn{EventID: event.ID, WalletAddress: wallet}
+	recorded, err := s.checkinRepo.Record(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	if !recorded {
+		return nil, ErrAlreadyCheckedIn
+	}
+
+	nft, err := s.minter.Mint(ctx, event, wallet, req.Name)
+	if nft == nil {
+		// Nothing was stored, so the wallet can check in again
+		if deleteErr := s.checkinRepo.Delete(context.WithoutCancel(ctx), record.ID); deleteErr != nil {
+			log.Printf("Failed to delete check-in %d: %v", record.ID, deleteErr)
+		}
+		return nil, err
+	}
+	if err != nil {
+		log.Printf("NFT %d for check-in %d is stored but not minted on chain: %v", nft.ID, record.ID, err)
+	}
+
+	if linkErr := s.checkinRepo.SetNFT(context.WithoutCancel(ctx), record.ID, nft.ID); linkErr != nil {
+		log.Printf("Failed to link check-in %d to NFT %d: %v", record.ID, nft.ID, linkErr)
+	}
+
+	return nft, err
+}
@@ -0,0 +1,19 @@
+package checkin
+
+import "math"
+
+// earthRadiusMeters is the mean radius of the Earth
+const earthRadiusMeters = 6371000
+
+// distanceMeters is the great-circle distance between two coordinates in degrees
+func distanceMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(a)))
+}
@@ -0,0 +1,81 @@
+package checkin
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// Codes follow TOTP (RFC 6238): HMAC-SHA1 of the time step, truncated to
+// six digits
+const (
+	codeDigits = 6
+	secretSize = 20
+	// DefaultPeriod is how often codes change unless the event sets its own period
+	DefaultPeriod = 30 * time.Second
+	// acceptedSteps is how many time steps before the current one are still
+	// accepted, to allow for the time between scanning and submitting
+	acceptedSteps = 1
+)
+
+// NewSecret generates a random code secret
+func NewSecret() ([]byte, error) {
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate check-in secret: %v", err)
+	}
+	return secret, nil
+}
+
+// Code returns the code for the time step containing now, and when it stops
+// being the current code
+func Code(secret []byte, period time.Duration, now time.Time) (string, time.Time) {
+	step := timeStep(period, now)
+	validUntil := time.Unix(int64((step+1)*uint64(period/time.Second)), 0)
+	return codeForStep(secret, step), validUntil
+}
+
+// VerifyCode reports whether code is the current code or one of the
+// acceptedSteps codes before it
+func VerifyCode(secret []byte, period time.Duration, code string, now time.Time) bool {
+	if len(code) != codeDigits {
+		return false
+	}
+
+	step := timeStep(period, now)
+	for i := uint64(0); i <= acceptedSteps && i <= step; i++ {
+		if subtle.ConstantTimeCompare([]byte(codeForStep(secret, step-i)), []byte(code)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// timeStep is the number of periods since the Unix epoch
+func timeStep(period time.Duration, now time.Time) uint64 {
+	seconds := uint64(period / time.Second)
+	if seconds == 0 {
+		seconds = uint64(DefaultPeriod / time.Second)
+	}
+	return uint64(now.Unix()) / seconds
+}
+
+// codeForStep computes the code for a time step
+func codeForStep(secret []byte, step uint64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], step)
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", codeDigits, value%1000000)
+}
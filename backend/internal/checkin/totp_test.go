@@ -0,0 +1,82 @@
+package checkin
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 secret of the RFC 6238 test vectors
+var rfcSecret = []byte("12345678901234567890")
+
+func TestCodeMatchesRFC6238(t *testing.T) {
+	// The RFC's eight-digit codes, truncated to six
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+	}
+
+	for _, tt := range tests {
+		code, _ := Code(rfcSecret, 30*time.Second, time.Unix(tt.unix, 0))
+		if code != tt.code {
+			t.Errorf("Code at %d = %s, want %s", tt.unix, code, tt.code)
+		}
+	}
+}
+
+func TestCodeValidUntil(t *testing.T) {
+	_, validUntil := Code(rfcSecret, 30*time.Second, time.Unix(59, 0))
+	if !validUntil.Equal(time.Unix(60, 0)) {
+		t.Errorf("validUntil = %d, want 60", validUntil.Unix())
+	}
+}
+
+func TestVerifyCodeWindow(t *testing.T) {
+	period := 30 * time.Second
+	issued := time.Unix(1234567890, 0)
+	code, _ := Code(rfcSecret, period, issued)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		ok   bool
+	}{
+		{"same step", issued, true},
+		{"end of step", time.Unix(1234567919, 0), true},
+		{"next step", issued.Add(period), true},
+		{"two steps later", issued.Add(2 * period), false},
+		{"step before", issued.Add(-period), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyCode(rfcSecret, period, code, tt.now); got != tt.ok {
+				t.Errorf("VerifyCode = %v, want %v", got, tt.ok)
+			}
+		})
+	}
+}
+
+func TestVerifyCodeRejectsMalformedCodes(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	for _, code := range []string{"", "00592", "0059240", "abcdef"} {
+		if VerifyCode(rfcSecret, 30*time.Second, code, now) {
+			t.Errorf("VerifyCode accepted %q", code)
+		}
+	}
+	if VerifyCode([]byte("another secret"), 30*time.Second, "005924", now) {
+		t.Error("VerifyCode accepted a code for another secret")
+	}
+}
+
+func TestZeroPeriodUsesDefault(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	withDefault, _ := Code(rfcSecret, DefaultPeriod, now)
+	withZero, _ := Code(rfcSecret, 0, now)
+	if withDefault != withZero {
+		t.Errorf("Code with a zero period = %s, want %s", withZero, withDefault)
+	}
+}
@@ -0,0 +1,190 @@
+package claims
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/minting"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// Errors returned when looking up or redeeming a claim link
+var (
+	ErrInvalidToken     = errors.New("claim link is invalid or has been replaced")
+	ErrExpired          = errors.New("claim link has expired")
+	ErrAlreadyClaimed   = errors.New("NFT has already been claimed")
+	ErrClaimInProgress  = errors.New("NFT is already being claimed")
+	ErrInvalidSignature = errors.New("signature doesn't match the wallet address")
+	ErrInvalidWallet    = errors.New("invalid wallet address")
+)
+
+// Service parks NFTs for attendees without a wallet, sends them claim links
+// and mints the NFTs when the links are redeemed
+type Service struct {
+	claimRepo *database.ClaimRepository
+	eventRepo *database.EventRepository
+	minter    *minting.Minter
+	notifier  Notifier
+	linkBase  string
+	secret    []byte
+	linkTTL   time.Duration
+}
+
+// NewService creates a new claim service. Claim links are linkBase followed
+// by a slash and the claim token; tokens are signed with secret and expire
+// after linkTTL.
+func NewService(
+	claimRepo *database.ClaimRepository,
+	eventRepo *database.EventRepository,
+	minter *minting.Minter,
+	notifier Notifier,
+	linkBase string,
+	secret string,
+	linkTTL time.Duration,
+) *Service {
+	return &Service{
+		claimRepo: claimRepo,
+		eventRepo: eventRepo,
+		minter:    minter,
+		notifier:  notifier,
+		linkBase:  strings.TrimRight(linkBase, "/"),
+		secret:    []byte(secret),
+		linkTTL:   linkTTL,
+	}
+}
+
+// Message returns the message a wallet signs to redeem a claim link
+func Message(token string) string {
+	return "Claim attendance NFT: " + token
+}
+
+// Park creates a pending claim for an attendee and sends them a claim link.
+// Checking in again sends a new link that replaces the previous one. If the
+// attendee has already claimed their NFT, the claimed claim is returned and
+// nothing is sent. A failure to send is logged, not returned, since the
+// claim is stored either way.
+func (s *Service) Park(ctx context.Context, event *models.Event, attendee *models.Attendee) (*models.Claim, error) {
+	claim := &models.Claim{
+		EventID:    event.ID,
+		AttendeeID: attendee.ID,
+		Name:       attendee.Name,
+		Email:      attendee.Email,
+		ExpiresAt:  time.Now().Add(s.linkTTL).Truncate(time.Second),
+	}
+
+	token, err := signToken(s.secret, claim.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	parked, err := s.claimRepo.Park(ctx, claim, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if !parked {
+		return claim, nil
+	}
+
+	if err := s.notifier.SendClaimLink(ctx, claim, event, s.link(token)); err != nil {
+		log.Printf("Failed to send claim link for claim %d: %v", claim.ID, err)
+	}
+
+	return claim, nil
+}
+
+// Lookup gets the claim a link's token belongs to and its event. It fails
+// with ErrInvalidToken or ErrExpired if the link doesn't work.
+func (s *Service) Lookup(ctx context.Context, token string) (*models.Claim, *models.Event, error) {
+	if err := verifyToken(s.secret, token); err != nil {
+		return nil, nil, err
+	}
+
+	claim, err := s.claimRepo.GetByTokenHash(ctx, hashToken(token))
+	if err != nil {
+		return nil, nil, err
+	}
+	if claim == nil {
+		return nil, nil, ErrInvalidToken
+	}
+
+	event, err := s.eventRepo.GetByID(ctx, claim.EventID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if event == nil {
+		return nil, nil, fmt.Errorf("event %d of claim %d not found", claim.EventID, claim.ID)
+	}
+
+	return claim, event, nil
+}
+
+// Redeem mints a claim's NFT to wallet, which must have signed Message(token).
+// Each link can be redeemed once. If the NFT is stored but minting it on
+// chain fails, the claim still counts as claimed and the NFT stays
+// unconfirmed, as for other mints.
+func (s *Service) Redeem(ctx context.Context, token, wallet, signature string) (*models.Claim, *models.NFT, error) {
+	claim, event, err := s.Lookup(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if claim.Status == models.ClaimClaimed {
+		return claim, nil, ErrAlreadyClaimed
+	}
+
+	if !event.Mintable() {
+		return claim, nil, database.ErrEventNotMintable
+	}
+
+	canonical, err := address.Canonical(wallet)
+	if err != nil {
+		return claim, nil, fmt.Errorf("%w: %v", ErrInvalidWallet, err)
+	}
+
+	if !address.VerifySignature(wallet, Message(token), signature) {
+		return claim, nil, ErrInvalidSignature
+	}
+
+	reserved, err := s.claimRepo.Reserve(ctx, claim.ID)
+	if err != nil {
+		return claim, nil, err
+	}
+	if !reserved {
+		return claim, nil, ErrClaimInProgress
+	}
+
+	nft, err := s.minter.Mint(ctx, event, canonical, claim.Name)
+	if nft == nil {
+		// Nothing was stored, so the link can be used again
+		if releaseErr := s.claimRepo.Release(context.WithoutCancel(ctx), claim.ID); releaseErr != nil {
+			log.Printf("Failed to release claim %d: %v", claim.ID, releaseErr)
+		}
+		return claim, nil, err
+	}
+	if err != nil {
+		log.Printf("NFT %d for claim %d is stored but not minted on chain: %v", nft.ID, claim.ID, err)
+	}
+
+	if err := s.claimRepo.MarkClaimed(context.WithoutCancel(ctx), claim.ID, canonical, nft.ID); err != nil {
+		return claim, nft, err
+	}
+
+	now := time.Now()
+	claim.Status = models.ClaimClaimed
+	claim.WalletAddress = canonical
+	claim.NFTID = &nft.ID
+	claim.ClaimedAt = &now
+
+	return claim, nft, nil
+}
+
+// link returns the claim link for a token
+func (s *Service) link(token string) string {
+	return s.linkBase + "/" + token
+}
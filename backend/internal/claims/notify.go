@@ -0,0 +1,85 @@
+package claims
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/smtp"
+	"strings"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/config"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// Notifier sends attendees the link to claim their NFT
+type Notifier interface {
+	SendClaimLink(ctx context.Context, claim *models.Claim, event *models.Event, link string) error
+}
+
+// NewNotifier returns an SMTP notifier if SMTP is configured, and a notifier
+// that only logs the links otherwise
+func NewNotifier(cfg config.SMTP) Notifier {
+	if cfg.Host == "" {
+		return LogNotifier{}
+	}
+	return &SMTPNotifier{cfg: cfg}
+}
+
+// LogNotifier logs claim links instead of sending them, for development
+type LogNotifier struct{}
+
+// SendClaimLink logs the claim link
+func (LogNotifier) SendClaimLink(ctx context.Context, claim *models.Claim, event *models.Event, link string) error {
+	log.Printf("Claim link for attendee %s of event %d: %s", claim.AttendeeID, event.ID, link)
+	return nil
+}
+
+// SMTPNotifier emails claim links
+type SMTPNotifier struct {
+	cfg config.SMTP
+}
+
+// SendClaimLink emails the claim link to the attendee
+func (n *SMTPNotifier) SendClaimLink(ctx context.Context, claim *models.Claim, event *models.Event, link string) error {
+	if claim.Email == "" {
+		return fmt.Errorf("attendee %s has no email address", claim.AttendeeID)
+	}
+
+	name := claim.Name
+	if name == "" {
+		name = "there"
+	}
+
+	message := strings.Join([]string{
+		"From: " + n.cfg.From,
+		"To: " + claim.Email,
+		"Subject: Claim your attendance NFT for " + headerSafe(event.Name),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		fmt.Sprintf("Hi %s,", name),
+		"",
+		fmt.Sprintf("Thanks for attending %s. You checked in without a Polkadot wallet address,", event.Name),
+		"so your attendance NFT is waiting for you. Open this link to claim it:",
+		"",
+		link,
+		"",
+	}, "\r\n")
+
+	addr := fmt.Sprintf("%s:%d", n.cfg.Host, n.cfg.Port)
+	var auth smtp.Auth
+	if n.cfg.Username != "" {
+		auth = smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)
+	}
+
+	if err := smtp.SendMail(addr, auth, n.cfg.From, []string{claim.Email}, []byte(message)); err != nil {
+		return fmt.Errorf("failed to email claim link: %w", err)
+	}
+
+	return nil
+}
+
+// headerSafe strips line breaks so a value can't add mail headers
+func headerSafe(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
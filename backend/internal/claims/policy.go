@@ -0,0 +1,179 @@
+package claims
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/minting"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// Errors returned when claiming a badge under an event's claim policy
+var (
+	ErrClaimsDisabled = errors.New("event only mints badges on check-in")
+	ErrNotOpenYet     = errors.New("claims for this event haven't opened yet")
+	ErrClaimsClosed   = errors.New("claims for this event have closed")
+	ErrWrongCode      = errors.New("claim code is wrong")
+	ErrNotAllowlisted = errors.New("wallet is not on this event's allowlist")
+	ErrInvalidPolicy  = errors.New("invalid claim policy")
+)
+
+// DirectRequest is a wallet's claim of an event's badge under its claim policy
+type DirectRequest struct {
+	WalletAddress string
+	Signature     string
+	Code          string
+	Name          string
+	IPAddress     string
+}
+
+// Claimer mints badges that organizers hand out with a secret code, an
+// allowlist or an open claim window instead of check-ins
+type Claimer struct {
+	policyRepo *database.ClaimPolicyRepository
+	nftRepo    *database.NFTRepository
+	minter     *minting.Minter
+}
+
+// NewClaimer creates a new claimer for event claim policies
+func NewClaimer(policyRepo *database.ClaimPolicyRepository, nftRepo *database.NFTRepository, minter *minting.Minter) *Claimer {
+	return &Claimer{
+		policyRepo: policyRepo,
+		nftRepo:    nftRepo,
+		minter:     minter,
+	}
+}
+
+// PolicyMessage returns the message a wallet signs to claim an event's badge
+func PolicyMessage(eventID uint64) string {
+	return fmt.Sprintf("Claim attendance NFT for event %d", eventID)
+}
+
+// HashCode hashes an event's secret claim code for storage. Codes ignore
+// case and surrounding whitespace.
+func HashCode(eventID uint64, code string) string {
+	sum := sha256.Sum256([]byte(strconv.FormatUint(eventID, 10) + ":" + strings.ToLower(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidatePolicy checks a claim policy. Code mode needs a code hash and open
+// mode needs a closing time, so badges can't be claimed forever.
+func ValidatePolicy(policy *models.ClaimPolicy) error {
+	switch policy.Mode {
+	case models.ClaimModeWebhook, models.ClaimModeAllowlist:
+	case models.ClaimModeCode:
+		if policy.CodeHash == "" {
+			return fmt.Errorf("%w: code mode needs a code", ErrInvalidPolicy)
+		}
+	case models.ClaimModeOpen:
+		if policy.ClosesAt == nil {
+			return fmt.Errorf("%w: open mode needs closes_at", ErrInvalidPolicy)
+		}
+	default:
+		return fmt.Errorf("%w: mode must be webhook, code, allowlist or open", ErrInvalidPolicy)
+	}
+
+	if policy.OpensAt != nil && policy.ClosesAt != nil && !policy.ClosesAt.After(*policy.OpensAt) {
+		return fmt.Errorf("%w: claims must close after they open", ErrInvalidPolicy)
+	}
+
+	if policy.MaxClaims != nil && *policy.MaxClaims < 1 {
+		return fmt.Errorf("%w: max_claims must be at least 1", ErrInvalidPolicy)
+	}
+
+	return nil
+}
+
+// Claim mints an event's badge to the wallet in req if the event's claim
+// policy allows it. The wallet must sign PolicyMessage, and each wallet
+// claims once. As with other mints, an NFT that is stored but fails on
+// chain is returned along with the error.
+func (c *Claimer) Claim(ctx context.Context, event *models.Event, req DirectRequest) (*models.NFT, error) {
+	if !event.Mintable() {
+		return nil, database.ErrEventNotMintable
+	}
+
+	policy := event.ClaimPolicy
+	if policy.Mode == "" || policy.Mode == models.ClaimModeWebhook {
+		return nil, ErrClaimsDisabled
+	}
+
+	now := time.Now()
+	if policy.OpensAt != nil && now.Before(*policy.OpensAt) {
+		return nil, ErrNotOpenYet
+	}
+	if !policy.Open(now) {
+		return nil, ErrClaimsClosed
+	}
+
+	wallet, err := address.Canonical(req.WalletAddress)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWallet, err)
+	}
+
+	if !address.VerifySignature(req.WalletAddress, PolicyMessage(event.ID), req.Signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	switch policy.Mode {
+	case models.ClaimModeCode:
+		hash := HashCode(event.ID, req.Code)
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(policy.CodeHash)) != 1 {
+			return nil, ErrWrongCode
+		}
+	case models.ClaimModeAllowlist:
+		allowed, err := c.policyRepo.IsAllowlisted(ctx, event.ID, wallet)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, ErrNotAllowlisted
+		}
+	}
+
+	// Wallets that already hold the event's NFT, however it was minted,
+	// don't get another one
+	owned, err := c.nftRepo.HasOwner(ctx, event.ID, wallet)
+	if err != nil {
+		return nil, err
+	}
+	if owned {
+		return nil, ErrAlreadyClaimed
+	}
+
+	claim := &models.DirectClaim{EventID: event.ID, WalletAddress: wallet, IPAddress: req.IPAddress}
+	if err := c.policyRepo.Reserve(ctx, claim, policy.MaxClaims); err != nil {
+		if errors.Is(err, database.ErrWalletClaimed) {
+			return nil, ErrAlreadyClaimed
+		}
+		return nil, err
+	}
+
+	nft, err := c.minter.Mint(ctx, event, wallet, req.Name)
+	if nft == nil {
+		// Nothing was stored, so the wallet can claim again
+		if deleteErr := c.policyRepo.Delete(context.WithoutCancel(ctx), claim.ID); deleteErr != nil {
+			log.Printf("Failed to delete claim %d: %v", claim.ID, deleteErr)
+		}
+		return nil, err
+	}
+	if err != nil {
+		log.Printf("NFT %d for direct claim %d is stored but not minted on chain: %v", nft.ID, claim.ID, err)
+	}
+
+	if linkErr := c.policyRepo.SetNFT(context.WithoutCancel(ctx), claim.ID, nft.ID); linkErr != nil {
+		log.Printf("Failed to link direct claim %d to NFT %d: %v", claim.ID, nft.ID, linkErr)
+	}
+
+	return nft, err
+}
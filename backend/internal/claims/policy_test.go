@@ -0,0 +1,57 @@
+package claims
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+func TestValidatePolicy(t *testing.T) {
+	opens := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	closes := opens.Add(8 * time.Hour)
+	before := opens.Add(-time.Hour)
+	zero, one := 0, 1
+
+	tests := []struct {
+		name   string
+		policy models.ClaimPolicy
+		ok     bool
+	}{
+		{"webhook", models.ClaimPolicy{Mode: models.ClaimModeWebhook}, true},
+		{"allowlist", models.ClaimPolicy{Mode: models.ClaimModeAllowlist}, true},
+		{"code", models.ClaimPolicy{Mode: models.ClaimModeCode, CodeHash: HashCode(1, "secret")}, true},
+		{"code without code", models.ClaimPolicy{Mode: models.ClaimModeCode}, false},
+		{"open", models.ClaimPolicy{Mode: models.ClaimModeOpen, ClosesAt: &closes}, true},
+		{"open forever", models.ClaimPolicy{Mode: models.ClaimModeOpen}, false},
+		{"open window", models.ClaimPolicy{Mode: models.ClaimModeOpen, OpensAt: &opens, ClosesAt: &closes}, true},
+		{"closes before opening", models.ClaimPolicy{Mode: models.ClaimModeOpen, OpensAt: &opens, ClosesAt: &before}, false},
+		{"closes as it opens", models.ClaimPolicy{Mode: models.ClaimModeAllowlist, OpensAt: &opens, ClosesAt: &opens}, false},
+		{"one claim", models.ClaimPolicy{Mode: models.ClaimModeAllowlist, MaxClaims: &one}, true},
+		{"no claims", models.ClaimPolicy{Mode: models.ClaimModeAllowlist, MaxClaims: &zero}, false},
+		{"no mode", models.ClaimPolicy{}, false},
+		{"unknown mode", models.ClaimPolicy{Mode: "lottery"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePolicy(&tt.policy)
+			if tt.ok && err != nil {
+				t.Errorf("ValidatePolicy failed: %v", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrInvalidPolicy) {
+				t.Errorf("ValidatePolicy = %v, want %v", err, ErrInvalidPolicy)
+			}
+		})
+	}
+}
+
+func TestHashCodeIgnoresCaseAndWhitespace(t *testing.T) {
+	if HashCode(1, " Secret ") != HashCode(1, "secret") {
+		t.Error("HashCode depends on case or surrounding whitespace")
+	}
+	if HashCode(1, "secret") == HashCode(2, "secret") {
+		t.Error("HashCode is the same for different events")
+	}
+}
@@ -0,0 +1,71 @@
+package claims
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Claim tokens are "<payload>.<mac>" in unpadded base64url. The payload is a
+// random nonce followed by the expiry as big-endian Unix seconds, and the mac
+// is its HMAC-SHA256 under the claim secret.
+const (
+	nonceSize   = 16
+	payloadSize = nonceSize + 8
+)
+
+// signToken creates a claim token that expires at expiresAt
+func signToken(secret []byte, expiresAt time.Time) (string, error) {
+	payload := make([]byte, payloadSize)
+	if _, err := rand.Read(payload[:nonceSize]); err != nil {
+		return "", fmt.Errorf("failed to generate claim token: %v", err)
+	}
+	binary.BigEndian.PutUint64(payload[nonceSize:], uint64(expiresAt.Unix()))
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(tokenMAC(secret, payload)), nil
+}
+
+// verifyToken checks a claim token's signature and expiry
+func verifyToken(secret []byte, token string) error {
+	encodedPayload, encodedMAC, found := strings.Cut(token, ".")
+	if !found {
+		return ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil || len(payload) != payloadSize {
+		return ErrInvalidToken
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMAC)
+	if err != nil || !hmac.Equal(mac, tokenMAC(secret, payload)) {
+		return ErrInvalidToken
+	}
+
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(payload[nonceSize:])), 0)
+	if !time.Now().Before(expiresAt) {
+		return ErrExpired
+	}
+
+	return nil
+}
+
+// tokenMAC signs a token payload
+func tokenMAC(secret, payload []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// hashToken returns the hex SHA-256 of a claim token, which is what's stored
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"log"
 	"os"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
 )
 
 // RateLimit holds rate limiting configuration
 type RateLimit struct {
 	Enabled           bool `json:"enabled"`
 	RequestsPerMinute int  `json:"requests_per_minute"`
+	ClaimsPerMinute   int  `json:"claims_per_minute"` // badge claim attempts per wallet and per IP address
 }
 
 // Database holds database configuration
@@ -23,34 +26,131 @@ type Database struct {
 	SSLMode  string `json:"sslmode"`
 }
 
+// SMTP holds the mail server used to send claim links. Links are only
+// logged when Host is empty.
+type SMTP struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	From     string `json:"from"`
+}
+
+// Providers holds the API credentials, default webhook secrets and wallet
+// questions of the ticketing providers besides Luma. Webhooks of any
+// provider, Luma included, are rejected when it has no secret unless
+// AllowUnsigned is set, which is only meant for development.
+type Providers struct {
+	EventbriteToken          string `json:"eventbrite_token"`
+	EventbriteWebhookSecret  string `json:"eventbrite_webhook_secret"`
+	EventbriteWalletQuestion string `json:"eventbrite_wallet_question"`
+	PretixURL                string `json:"pretix_url"`
+	PretixToken              string `json:"pretix_token"`
+	PretixWebhookSecret      string `json:"pretix_webhook_secret"`
+	PretixWalletQuestion     string `json:"pretix_wallet_question"`
+	TitoWebhookSecret        string `json:"tito_webhook_secret"`
+	TitoWalletQuestion       string `json:"tito_wallet_question"`
+	GenericWebhookSecret     string `json:"generic_webhook_secret"`
+	GenericWalletQuestion    string `json:"generic_wallet_question"`
+	AllowUnsigned            bool   `json:"allow_unsigned"`
+}
+
+// Chain selects where attendance NFTs are minted
+type Chain struct {
+	Backend       string            `json:"backend"` // "contract" or "pallet-nfts"
+	AssetHubRPC   string            `json:"asset_hub_rpc"`
+	SignerURI     string            `json:"signer_uri"`
+	EventBackends map[string]string `json:"event_backends"` // per-event overrides keyed by event ID
+}
+
 // Config holds all configuration for the application
 type Config struct {
-	ServerAddress   string    `json:"server_address"`
-	PolkadotRPC     string    `json:"polkadot_rpc"`
-	ContractAddress string    `json:"contract_address"`
-	LumaAPIKey      string    `json:"luma_api_key"`
-	LumaWebhookKey  string    `json:"luma_webhook_key"`
-	JWTSecret       string    `json:"jwt_secret"`
-	AdminUsername   string    `json:"admin_username"`
-	AdminPassword   string    `json:"admin_password"`
-	RateLimit       RateLimit `json:"rate_limit"`
-	Database        Database  `json:"database"`
+	ServerAddress      string    `json:"server_address"`
+	PolkadotRPC        string    `json:"polkadot_rpc"`
+	ContractAddress    string    `json:"contract_address"`
+	LumaAPIKey         string    `json:"luma_api_key"`
+	LumaWebhookKey     string    `json:"luma_webhook_key"`
+	LumaAPIURL         string    `json:"luma_api_url"`         // base URL of the Luma API or a local stand-in
+	LumaSyncMinutes    int       `json:"luma_sync_minutes"`    // interval of the imported event sync, 0 to disable
+	LumaWalletQuestion string    `json:"luma_wallet_question"` // label or ID of the registration question asking for a wallet
+	ClaimBaseURL       string    `json:"claim_base_url"`       // page attendees open to claim a parked NFT; the token is appended
+	ClaimSecret        string    `json:"claim_secret"`         // signs claim links; defaults to the JWT secret
+	ClaimLinkTTLHours  int       `json:"claim_link_ttl_hours"` // how long claim links work
+	CheckInBaseURL     string    `json:"checkin_base_url"`     // page QR check-in codes link to; the event ID and code are appended
+	MetadataBaseURL    string    `json:"metadata_base_url"`    // public URL of the metadata endpoint; NFT IDs are appended
+	IPFSAPIURL         string    `json:"ipfs_api_url"`         // IPFS node API that pins NFT metadata, empty to serve it from the backend
+	NFTImageBaseURL    string    `json:"nft_image_base_url"`   // public URL of /api/nfts that NFT metadata takes badge images from
+	BadgeTemplate      string    `json:"badge_template"`       // JSON file with the badge template, empty for the default
+	JWTSecret          string    `json:"jwt_secret"`
+	AdminUsername      string    `json:"admin_username"`
+	AdminPassword      string    `json:"admin_password"`
+	SS58Prefix         uint16    `json:"ss58_prefix"`
+	Chain              Chain     `json:"chain"`
+	SMTP               SMTP      `json:"smtp"`
+	Providers          Providers `json:"providers"`
+	RateLimit          RateLimit `json:"rate_limit"`
+	Database           Database  `json:"database"`
 }
 
 // Load loads configuration from environment variables or a config file
 func Load() *Config {
+	// A prefix SS58 can't encode would garble every address the API returns
+	ss58Prefix := getEnvAsInt("SS58_PREFIX", 42)
+	if ss58Prefix < 0 || ss58Prefix > address.MaxPrefix {
+		log.Fatalf("SS58_PREFIX must be between 0 and %d, got %d", address.MaxPrefix, ss58Prefix)
+	}
+
 	cfg := &Config{
-		ServerAddress:   getEnv("SERVER_ADDRESS", ":8080"),
-		PolkadotRPC:     getEnv("POLKADOT_RPC", "wss://westend-rpc.polkadot.io"),
-		ContractAddress: getEnv("CONTRACT_ADDRESS", ""),
-		LumaAPIKey:      getEnv("LUMA_API_KEY", ""),
-		LumaWebhookKey:  getEnv("LUMA_WEBHOOK_KEY", ""),
-		JWTSecret:       getEnv("JWT_SECRET", "polkadot-attendance-secret-key"),
-		AdminUsername:   getEnv("ADMIN_USERNAME", "admin"),
-		AdminPassword:   getEnv("ADMIN_PASSWORD", "password"),
+		ServerAddress:      getEnv("SERVER_ADDRESS", ":8080"),
+		PolkadotRPC:        getEnv("POLKADOT_RPC", "wss://westend-rpc.polkadot.io"),
+		ContractAddress:    getEnv("CONTRACT_ADDRESS", ""),
+		LumaAPIKey:         getEnv("LUMA_API_KEY", ""),
+		LumaWebhookKey:     getEnv("LUMA_WEBHOOK_KEY", ""),
+		LumaAPIURL:         getEnv("LUMA_API_URL", "https://public-api.lu.ma"),
+		LumaSyncMinutes:    getEnvAsInt("LUMA_SYNC_MINUTES", 15),
+		LumaWalletQuestion: getEnv("LUMA_WALLET_QUESTION", "Polkadot wallet address"),
+		ClaimBaseURL:       getEnv("CLAIM_BASE_URL", "http://localhost:3000/claim"),
+		ClaimSecret:        getEnv("CLAIM_SECRET", ""),
+		ClaimLinkTTLHours:  getEnvAsInt("CLAIM_LINK_TTL_HOURS", 720),
+		CheckInBaseURL:     getEnv("CHECKIN_BASE_URL", "http://localhost:3000/checkin"),
+		MetadataBaseURL:    getEnv("METADATA_BASE_URL", "http://localhost:8080/api/metadata"),
+		IPFSAPIURL:         getEnv("IPFS_API_URL", ""),
+		NFTImageBaseURL:    getEnv("NFT_IMAGE_BASE_URL", "http://localhost:8080/api/nfts"),
+		BadgeTemplate:      getEnv("BADGE_TEMPLATE", ""),
+		JWTSecret:          getEnv("JWT_SECRET", "polkadot-attendance-secret-key"),
+		AdminUsername:      getEnv("ADMIN_USERNAME", "admin"),
+		AdminPassword:      getEnv("ADMIN_PASSWORD", "password"),
+		SS58Prefix:         uint16(ss58Prefix),
+		Chain: Chain{
+			Backend:     getEnv("CHAIN_BACKEND", "contract"),
+			AssetHubRPC: getEnv("ASSET_HUB_RPC", "wss://westend-asset-hub-rpc.polkadot.io"),
+			SignerURI:   getEnv("CHAIN_SIGNER_URI", "//Alice"),
+		},
+		SMTP: SMTP{
+			Host:     getEnv("SMTP_HOST", ""),
+			Port:     getEnvAsInt("SMTP_PORT", 587),
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("SMTP_FROM", "noreply@localhost"),
+		},
+		Providers: Providers{
+			EventbriteToken:          getEnv("EVENTBRITE_TOKEN", ""),
+			EventbriteWebhookSecret:  getEnv("EVENTBRITE_WEBHOOK_SECRET", ""),
+			EventbriteWalletQuestion: getEnv("EVENTBRITE_WALLET_QUESTION", "Polkadot wallet address"),
+			PretixURL:                getEnv("PRETIX_URL", "https://pretix.eu"),
+			PretixToken:              getEnv("PRETIX_TOKEN", ""),
+			PretixWebhookSecret:      getEnv("PRETIX_WEBHOOK_SECRET", ""),
+			PretixWalletQuestion:     getEnv("PRETIX_WALLET_QUESTION", "Polkadot wallet address"),
+			TitoWebhookSecret:        getEnv("TITO_WEBHOOK_SECRET", ""),
+			TitoWalletQuestion:       getEnv("TITO_WALLET_QUESTION", "Polkadot wallet address"),
+			GenericWebhookSecret:     getEnv("GENERIC_WEBHOOK_SECRET", ""),
+			GenericWalletQuestion:    getEnv("GENERIC_WALLET_QUESTION", "Polkadot wallet address"),
+			AllowUnsigned:            getEnv("ALLOW_UNSIGNED_WEBHOOKS", "") == "true",
+		},
 		RateLimit: RateLimit{
 			Enabled:           true,
 			RequestsPerMinute: 60,
+			ClaimsPerMinute:   getEnvAsInt("CLAIM_RATE_LIMIT", 5),
 		},
 		Database: Database{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -76,6 +176,10 @@ func Load() *Config {
 		}
 	}
 
+	if cfg.SS58Prefix > address.MaxPrefix {
+		log.Fatalf("ss58_prefix must be between 0 and %d, got %d", address.MaxPrefix, cfg.SS58Prefix)
+	}
+
 	return cfg
 }
 
@@ -99,4 +203,4 @@ func getEnvAsInt(key string, defaultValue int) int {
 		return defaultValue
 	}
 	return value
-}
\ No newline at end of file
+}
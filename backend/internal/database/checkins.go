@@ -0,0 +1,152 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// CheckInRepository handles database operations for QR check-ins
+type CheckInRepository struct {
+	db *DB
+}
+
+// NewCheckInRepository creates a new check-in repository
+func NewCheckInRepository(db *DB) *CheckInRepository {
+	return &CheckInRepository{db: db}
+}
+
+// GetSettings gets the check-in settings of an event, or nil if check-in
+// was never set up for it
+func (r *CheckInRepository) GetSettings(ctx context.Context, eventID uint64) (*models.CheckInSettings, error) {
+	query := `
+		SELECT event_id, secret, enabled, period_seconds, latitude, longitude, radius_meters, updated_at
+		FROM checkin_settings
+		WHERE event_id = $1
+	`
+
+	var settings models.CheckInSettings
+	var latitude, longitude, radius sql.NullFloat64
+
+	err := r.db.QueryRowContext(ctx, query, eventID).Scan(
+		&settings.EventID,
+		&settings.Secret,
+		&settings.Enabled,
+		&settings.PeriodSeconds,
+		&latitude,
+		&longitude,
+		&radius,
+		&settings.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get check-in settings: %w", err)
+	}
+
+	settings.Latitude = nullableFloat(latitude)
+	settings.Longitude = nullableFloat(longitude)
+	settings.RadiusMeters = nullableFloat(radius)
+
+	return &settings, nil
+}
+
+// SaveSettings creates or replaces the check-in settings of an event
+func (r *CheckInRepository) SaveSettings(ctx context.Context, settings *models.CheckInSettings) error {
+	query := `
+		INSERT INTO checkin_settings (event_id, secret, enabled, period_seconds, latitude, longitude, radius_meters)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (event_id) DO UPDATE
+		SET secret = EXCLUDED.secret, enabled = EXCLUDED.enabled, period_seconds = EXCLUDED.period_seconds,
+			latitude = EXCLUDED.latitude, longitude = EXCLUDED.longitude, radius_meters = EXCLUDED.radius_meters,
+			updated_at = NOW()
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx,
+		query,
+		settings.EventID,
+		settings.Secret,
+		settings.Enabled,
+		settings.PeriodSeconds,
+		settings.Latitude,
+		settings.Longitude,
+		settings.RadiusMeters,
+	).Scan(&settings.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save check-in settings: %w", err)
+	}
+
+	return nil
+}
+
+// Record stores a wallet's check-in to an event. It returns false if the
+// wallet has already checked in.
+func (r *CheckInRepository) Record(ctx context.Context, checkIn *models.CheckIn) (bool, error) {
+	query := `
+		INSERT INTO checkins (event_id, wallet_address)
+		VALUES ($1, $2)
+		ON CONFLICT (event_id, wallet_address) DO NOTHING
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query, checkIn.EventID, checkIn.WalletAddress).Scan(&checkIn.ID, &checkIn.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to record check-in: %w", err)
+	}
+
+	return true, nil
+}
+
+// SetNFT links a check-in to the NFT minted for it
+func (r *CheckInRepository) SetNFT(ctx context.Context, id, nftID uint64) error {
+	query := `UPDATE checkins SET nft_id = $1 WHERE id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, nftID, id); err != nil {
+		return fmt.Errorf("failed to link check-in to NFT: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a check-in whose NFT couldn't be created, so the wallet can try again
+func (r *CheckInRepository) Delete(ctx context.Context, id uint64) error {
+	query := `DELETE FROM checkins WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete check-in: %w", err)
+	}
+
+	return nil
+}
+
+// CountByEvent counts the QR check-ins of an event
+func (r *CheckInRepository) CountByEvent(ctx context.Context, eventID uint64) (int, error) {
+	query := `SELECT COUNT(*) FROM checkins WHERE event_id = $1`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, eventID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count check-ins: %w", err)
+	}
+
+	return count, nil
+}
+
+// nullableFloat converts a nullable column value to an optional float
+func nullableFloat(value sql.NullFloat64) *float64 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Float64
+}
@@ -0,0 +1,208 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// Errors returned when reserving a direct claim
+var (
+	ErrClaimLimitReached = errors.New("all badges of this event have been claimed")
+	ErrWalletClaimed     = errors.New("wallet has already claimed this event's badge")
+)
+
+// ClaimPolicyRepository handles database operations for event allowlists
+// and the badges claimed under events' claim policies
+type ClaimPolicyRepository struct {
+	db *DB
+}
+
+// NewClaimPolicyRepository creates a new claim policy repository
+func NewClaimPolicyRepository(db *DB) *ClaimPolicyRepository {
+	return &ClaimPolicyRepository{db: db}
+}
+
+// AddToAllowlist adds canonical wallet addresses to an event's allowlist and
+// returns how many weren't on it yet
+func (r *ClaimPolicyRepository) AddToAllowlist(ctx context.Context, eventID uint64, wallets []string) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	added := 0
+	for _, wallet := range wallets {
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO claim_allowlist (event_id, wallet_address)
+			VALUES ($1, $2)
+			ON CONFLICT (event_id, wallet_address) DO NOTHING
+		`, eventID, wallet)
+		if err != nil {
+			return 0, fmt.Errorf("failed to add wallet to allowlist: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		added += int(rowsAffected)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit allowlist: %w", err)
+	}
+
+	return added, nil
+}
+
+// RemoveFromAllowlist removes a wallet from an event's allowlist. It returns
+// false if the wallet wasn't on it.
+func (r *ClaimPolicyRepository) RemoveFromAllowlist(ctx context.Context, eventID uint64, wallet string) (bool, error) {
+	query := `DELETE FROM claim_allowlist WHERE event_id = $1 AND wallet_address = $2`
+
+	result, err := r.db.ExecContext(ctx, query, eventID, wallet)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove wallet from allowlist: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetAllowlist lists the wallets on an event's allowlist
+func (r *ClaimPolicyRepository) GetAllowlist(ctx context.Context, eventID uint64) ([]string, error) {
+	query := `
+		SELECT wallet_address
+		FROM claim_allowlist
+		WHERE event_id = $1
+		ORDER BY created_at, wallet_address
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allowlist: %w", err)
+	}
+	defer rows.Close()
+
+	wallets := []string{}
+	for rows.Next() {
+		var wallet string
+		if err := rows.Scan(&wallet); err != nil {
+			return nil, fmt.Errorf("failed to scan allowlist: %w", err)
+		}
+		wallets = append(wallets, wallet)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get allowlist: %w", err)
+	}
+
+	return wallets, nil
+}
+
+// IsAllowlisted reports whether a wallet is on an event's allowlist
+func (r *ClaimPolicyRepository) IsAllowlisted(ctx context.Context, eventID uint64, wallet string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM claim_allowlist WHERE event_id = $1 AND wallet_address = $2)`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, eventID, wallet).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check allowlist: %w", err)
+	}
+
+	return exists, nil
+}
+
+// Reserve records a wallet's claim before its NFT is minted. It fails with
+// ErrWalletClaimed if the wallet has already claimed the event's badge, or
+// with ErrClaimLimitReached once the event has maxClaims claims.
+func (r *ClaimPolicyRepository) Reserve(ctx context.Context, claim *models.DirectClaim, maxClaims *int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the event so concurrent claims can't exceed the limit
+	var eventID uint64
+	err = tx.QueryRowContext(ctx, `SELECT id FROM events WHERE id = $1 FOR UPDATE`, claim.EventID).Scan(&eventID)
+	if err == sql.ErrNoRows {
+		return ErrEventNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock event: %w", err)
+	}
+
+	if maxClaims != nil {
+		var count int
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM direct_claims WHERE event_id = $1`, claim.EventID).Scan(&count); err != nil {
+			return fmt.Errorf("failed to count claims: %w", err)
+		}
+		if count >= *maxClaims {
+			return ErrClaimLimitReached
+		}
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO direct_claims (event_id, wallet_address, ip_address)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (event_id, wallet_address) DO NOTHING
+		RETURNING id, created_at
+	`, claim.EventID, claim.WalletAddress, claim.IPAddress).Scan(&claim.ID, &claim.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return ErrWalletClaimed
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reserve claim: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit claim: %w", err)
+	}
+
+	return nil
+}
+
+// SetNFT links a direct claim to the NFT minted for it
+func (r *ClaimPolicyRepository) SetNFT(ctx context.Context, id, nftID uint64) error {
+	query := `UPDATE direct_claims SET nft_id = $1 WHERE id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, nftID, id); err != nil {
+		return fmt.Errorf("failed to link claim to NFT: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a direct claim whose NFT couldn't be created, so the wallet
+// can try again and the claim doesn't count towards the limit
+func (r *ClaimPolicyRepository) Delete(ctx context.Context, id uint64) error {
+	query := `DELETE FROM direct_claims WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete claim: %w", err)
+	}
+
+	return nil
+}
+
+// CountByEvent counts the direct claims of an event
+func (r *ClaimPolicyRepository) CountByEvent(ctx context.Context, eventID uint64) (int, error) {
+	query := `SELECT COUNT(*) FROM direct_claims WHERE event_id = $1`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, eventID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count claims: %w", err)
+	}
+
+	return count, nil
+}
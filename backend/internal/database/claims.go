@@ -0,0 +1,285 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// ClaimRepository handles database operations for claims
+type ClaimRepository struct {
+	db *DB
+}
+
+// NewClaimRepository creates a new claim repository
+func NewClaimRepository(db *DB) *ClaimRepository {
+	return &ClaimRepository{db: db}
+}
+
+// claimColumns lists the columns read by scanClaim
+const claimColumns = `id, event_id, attendee_id, name, email, status, wallet_address, nft_id, expires_at, created_at, claimed_at`
+
+// scanClaim scans a row selected with claimColumns
+func scanClaim(row rowScanner) (*models.Claim, error) {
+	var claim models.Claim
+	var wallet sql.NullString
+	var nftID sql.NullInt64
+	var claimedAt sql.NullTime
+
+	err := row.Scan(
+		&claim.ID,
+		&claim.EventID,
+		&claim.AttendeeID,
+		&claim.Name,
+		&claim.Email,
+		&claim.Status,
+		&wallet,
+		&nftID,
+		&claim.ExpiresAt,
+		&claim.CreatedAt,
+		&claimedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	claim.WalletAddress = wallet.String
+	if nftID.Valid {
+		id := uint64(nftID.Int64)
+		claim.NFTID = &id
+	}
+	if claimedAt.Valid {
+		claim.ClaimedAt = &claimedAt.Time
+	}
+
+	return &claim, nil
+}
+
+// Park creates a pending claim for an attendee of an event. If the attendee
+// already has a pending claim, its token and expiry are replaced so only the
+// newest link works. It returns false, with claim filled in from the
+// database, if the attendee has already claimed their NFT or is claiming it.
+func (r *ClaimRepository) Park(ctx context.Context, claim *models.Claim, tokenHash string) (bool, error) {
+	query := `
+		INSERT INTO claims (event_id, attendee_id, name, email, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (event_id, attendee_id) DO UPDATE
+		SET name = EXCLUDED.name, email = EXCLUDED.email,
+			token_hash = EXCLUDED.token_hash, expires_at = EXCLUDED.expires_at
+		WHERE claims.status = 'pending'
+		RETURNING ` + claimColumns
+
+	parked, err := scanClaim(r.db.QueryRowContext(ctx,
+		query,
+		claim.EventID,
+		claim.AttendeeID,
+		claim.Name,
+		claim.Email,
+		tokenHash,
+		claim.ExpiresAt,
+	))
+
+	if err == sql.ErrNoRows {
+		existing, err := r.GetByAttendee(ctx, claim.EventID, claim.AttendeeID)
+		if err != nil {
+			return false, err
+		}
+		if existing == nil {
+			return false, fmt.Errorf("claim for attendee %s vanished", claim.AttendeeID)
+		}
+		*claim = *existing
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to park claim: %w", err)
+	}
+
+	*claim = *parked
+	return true, nil
+}
+
+// GetByAttendee gets the claim of an attendee of an event
+func (r *ClaimRepository) GetByAttendee(ctx context.Context, eventID uint64, attendeeID string) (*models.Claim, error) {
+	query := `
+		SELECT ` + claimColumns + `
+		FROM claims
+		WHERE event_id = $1 AND attendee_id = $2
+	`
+
+	claim, err := scanClaim(r.db.QueryRowContext(ctx, query, eventID, attendeeID))
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get claim: %w", err)
+	}
+
+	return claim, nil
+}
+
+// GetByTokenHash gets the claim a link's token belongs to
+func (r *ClaimRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.Claim, error) {
+	query := `
+		SELECT ` + claimColumns + `
+		FROM claims
+		WHERE token_hash = $1
+	`
+
+	claim, err := scanClaim(r.db.QueryRowContext(ctx, query, tokenHash))
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get claim: %w", err)
+	}
+
+	return claim, nil
+}
+
+// claimReservationTimeout is how long a reservation blocks other attempts;
+// after it, a claim left reserved by a failed mint can be reserved again
+const claimReservationTimeout = "10 minutes"
+
+// Reserve marks a pending, unexpired claim as being claimed. It returns
+// false if the claim isn't available, for example because another request
+// reserved it first.
+func (r *ClaimRepository) Reserve(ctx context.Context, id uint64) (bool, error) {
+	query := `
+		UPDATE claims
+		SET status = 'claiming', reserved_at = NOW()
+		WHERE id = $1 AND expires_at > NOW()
+			AND (status = 'pending'
+				OR (status = 'claiming' AND reserved_at < NOW() - INTERVAL '` + claimReservationTimeout + `'))
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve claim: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// Release returns a reserved claim to pending after its mint failed
+func (r *ClaimRepository) Release(ctx context.Context, id uint64) error {
+	query := `UPDATE claims SET status = 'pending', reserved_at = NULL WHERE id = $1 AND status = 'claiming'`
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to release claim: %w", err)
+	}
+
+	return nil
+}
+
+// MarkClaimed records that a reserved claim was minted to a wallet
+func (r *ClaimRepository) MarkClaimed(ctx context.Context, id uint64, wallet string, nftID uint64) error {
+	query := `
+		UPDATE claims
+		SET status = 'claimed', wallet_address = $1, nft_id = $2, claimed_at = NOW()
+		WHERE id = $3 AND status = 'claiming'
+	`
+
+	result, err := r.db.ExecContext(ctx, query, wallet, nftID, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark claim as claimed: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("claim %d is not reserved", id)
+	}
+
+	return nil
+}
+
+// ClaimCounts summarizes the claims of an event
+type ClaimCounts struct {
+	EventID uint64 `json:"event_id"`
+	// Unclaimed counts pending and reserved claims whose link still works
+	Unclaimed int `json:"unclaimed"`
+	// Expired counts unclaimed claims whose link has expired
+	Expired int `json:"expired"`
+	Claimed int `json:"claimed"`
+}
+
+// CountByEvent counts the claims of each event with any, or only of eventID if it's not 0
+func (r *ClaimRepository) CountByEvent(ctx context.Context, eventID uint64) ([]ClaimCounts, error) {
+	query := `
+		SELECT event_id,
+			COUNT(*) FILTER (WHERE status <> 'claimed' AND expires_at > NOW()),
+			COUNT(*) FILTER (WHERE status <> 'claimed' AND expires_at <= NOW()),
+			COUNT(*) FILTER (WHERE status = 'claimed')
+		FROM claims
+		WHERE $1 = 0 OR event_id = $1
+		GROUP BY event_id
+		ORDER BY event_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count claims: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []ClaimCounts{}
+	for rows.Next() {
+		var c ClaimCounts
+		if err := rows.Scan(&c.EventID, &c.Unclaimed, &c.Expired, &c.Claimed); err != nil {
+			return nil, fmt.Errorf("failed to scan claim counts: %w", err)
+		}
+		counts = append(counts, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating claim counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// GetUnclaimedByEvent gets the claims of an event that haven't been claimed, oldest first
+func (r *ClaimRepository) GetUnclaimedByEvent(ctx context.Context, eventID uint64) ([]models.Claim, error) {
+	query := `
+		SELECT ` + claimColumns + `
+		FROM claims
+		WHERE event_id = $1 AND status <> 'claimed'
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query claims: %w", err)
+	}
+	defer rows.Close()
+
+	claims := []models.Claim{}
+	for rows.Next() {
+		claim, err := scanClaim(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan claim: %w", err)
+		}
+		claims = append(claims, *claim)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating claims: %w", err)
+	}
+
+	return claims, nil
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
 )
 
 // Config represents database configuration
@@ -53,64 +54,112 @@ func New(config Config) (*DB, error) {
 	return &DB{db}, nil
 }
 
-// MigrateUp performs database migrations
-func (db *DB) MigrateUp() error {
-	// Create events table if it doesn't exist
-	if _, err := db.Exec(`
-		CREATE TABLE IF NOT EXISTS events (
-			id SERIAL PRIMARY KEY,
-			name VARCHAR(100) NOT NULL,
-			date DATE NOT NULL,
-			location VARCHAR(100) NOT NULL,
-			organizer VARCHAR(100) NOT NULL,
-			created_at TIMESTAMP NOT NULL DEFAULT NOW()
-		)
-	`); err != nil {
-		return fmt.Errorf("failed to create events table: %w", err)
+// canonicalizeAddresses rewrites SS58 addresses to their canonical hex form
+func canonicalizeAddresses(tx *sql.Tx) error {
+	columns := []struct {
+		table  string
+		column string
+		// merge folds a row into the row already holding its canonical
+		// address, for columns that must be unique
+		merge func(tx *sql.Tx, from, into uint64) error
+	}{
+		{"events", "organizer", nil},
+		{"nfts", "owner", nil},
+		{"users", "wallet_address", mergeUsers},
 	}
 
-	// Create NFTs table if it doesn't exist
-	if _, err := db.Exec(`
-		CREATE TABLE IF NOT EXISTS nfts (
-			id SERIAL PRIMARY KEY,
-			event_id INTEGER NOT NULL REFERENCES events(id),
-			owner VARCHAR(100) NOT NULL,
-			metadata JSONB NOT NULL,
-			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-			tx_hash VARCHAR(100),
-			confirmed BOOLEAN NOT NULL DEFAULT FALSE
-		)
-	`); err != nil {
-		return fmt.Errorf("failed to create nfts table: %w", err)
+	for _, col := range columns {
+		rows, err := tx.Query(fmt.Sprintf(
+			`SELECT id, %s FROM %s WHERE %s NOT LIKE '0x%%'`,
+			col.column, col.table, col.column,
+		))
+		if err != nil {
+			return fmt.Errorf("failed to query %s.%s: %w", col.table, col.column, err)
+		}
+
+		updates := make(map[uint64]string)
+		for rows.Next() {
+			var id uint64
+			var value string
+			if err := rows.Scan(&id, &value); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan %s.%s: %w", col.table, col.column, err)
+			}
+
+			canonical, err := address.Canonical(value)
+			if err != nil {
+				log.Printf("Skipping invalid address in %s.%s (id %d): %v", col.table, col.column, id, err)
+				continue
+			}
+			updates[id] = canonical
+		}
+		rows.Close()
+
+		for id, canonical := range updates {
+			if col.merge != nil {
+				var existing uint64
+				err := tx.QueryRow(fmt.Sprintf(
+					`SELECT id FROM %s WHERE %s = $1 AND id <> $2`,
+					col.table, col.column,
+				), canonical, id).Scan(&existing)
+				if err != nil && err != sql.ErrNoRows {
+					return fmt.Errorf("failed to query %s.%s: %w", col.table, col.column, err)
+				}
+				if err == nil {
+					if err := col.merge(tx, id, existing); err != nil {
+						return err
+					}
+					log.Printf("Merged %s %d into %d, which has the same address", col.table, id, existing)
+					continue
+				}
+			}
+
+			if _, err := tx.Exec(fmt.Sprintf(
+				`UPDATE %s SET %s = $1 WHERE id = $2`,
+				col.table, col.column,
+			), canonical, id); err != nil {
+				return fmt.Errorf("failed to update %s.%s: %w", col.table, col.column, err)
+			}
+		}
+
+		if len(updates) > 0 {
+			log.Printf("Canonicalized %d addresses in %s.%s", len(updates), col.table, col.column)
+		}
 	}
 
-	// Create users table if it doesn't exist
-	if _, err := db.Exec(`
-		CREATE TABLE IF NOT EXISTS users (
-			id SERIAL PRIMARY KEY,
-			wallet_address VARCHAR(100) NOT NULL UNIQUE,
-			username VARCHAR(100),
-			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-			last_login TIMESTAMP
+	return nil
+} 
+
+// mergeUsers folds a user into another user with the same wallet, moving
+// their event permissions and keeping the other user's username if they
+// have one
+func mergeUsers(tx *sql.Tx, from, into uint64) error {
+	if _, err := tx.Exec(`
+		UPDATE event_permissions AS p
+		SET user_id = $2
+		WHERE user_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM event_permissions WHERE event_id = p.event_id AND user_id = $2
 		)
-	`); err != nil {
-		return fmt.Errorf("failed to create users table: %w", err)
+	`, from, into); err != nil {
+		return fmt.Errorf("failed to move event permissions: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM event_permissions WHERE user_id = $1`, from); err != nil {
+		return fmt.Errorf("failed to delete event permissions: %w", err)
 	}
 
-	// Create event_permissions table if it doesn't exist
-	if _, err := db.Exec(`
-		CREATE TABLE IF NOT EXISTS event_permissions (
-			id SERIAL PRIMARY KEY,
-			event_id INTEGER NOT NULL REFERENCES events(id),
-			user_id INTEGER NOT NULL REFERENCES users(id),
-			role VARCHAR(20) NOT NULL,
-			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-			UNIQUE(event_id, user_id)
-		)
-	`); err != nil {
-		return fmt.Errorf("failed to create event_permissions table: %w", err)
+	if _, err := tx.Exec(`
+		UPDATE users AS u
+		SET username = COALESCE(u.username, f.username),
+			last_login = GREATEST(u.last_login, f.last_login),
+			created_at = LEAST(u.created_at, f.created_at)
+		FROM users AS f
+		WHERE u.id = $2 AND f.id = $1
+	`, from, into); err != nil {
+		return fmt.Errorf("failed to merge users: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM users WHERE id = $1`, from); err != nil {
+		return fmt.Errorf("failed to delete merged user: %w", err)
 	}
 
-	log.Println("Database migrations completed successfully")
 	return nil
-} 
\ No newline at end of file
+}
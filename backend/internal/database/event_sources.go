@@ -0,0 +1,155 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// ErrSourceInUse is returned when a provider's event is already linked to another event
+var ErrSourceInUse = errors.New("provider event is already linked to another event")
+
+// EventSourceRepository handles database operations for the ticketing
+// providers events take check-ins from
+type EventSourceRepository struct {
+	db *DB
+}
+
+// NewEventSourceRepository creates a new event source repository
+func NewEventSourceRepository(db *DB) *EventSourceRepository {
+	return &EventSourceRepository{db: db}
+}
+
+const eventSourceColumns = `event_id, provider, external_event_id, webhook_secret, wallet_question, created_at`
+
+// scanEventSource scans an event source row selected with eventSourceColumns
+func scanEventSource(row rowScanner) (*models.EventSource, error) {
+	var source models.EventSource
+
+	err := row.Scan(
+		&source.EventID,
+		&source.Provider,
+		&source.ExternalEventID,
+		&source.WebhookSecret,
+		&source.WalletQuestion,
+		&source.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &source, nil
+}
+
+// Save links an event to a provider's event, replacing its previous link to
+// that provider
+func (r *EventSourceRepository) Save(ctx context.Context, source *models.EventSource) error {
+	query := `
+		INSERT INTO event_sources (event_id, provider, external_event_id, webhook_secret, wallet_question)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (event_id, provider) DO UPDATE
+		SET external_event_id = EXCLUDED.external_event_id, webhook_secret = EXCLUDED.webhook_secret,
+			wallet_question = EXCLUDED.wallet_question
+		RETURNING created_at
+	`
+
+	err := r.db.QueryRowContext(ctx,
+		query,
+		source.EventID,
+		source.Provider,
+		source.ExternalEventID,
+		source.WebhookSecret,
+		source.WalletQuestion,
+	).Scan(&source.CreatedAt)
+
+	if isUniqueViolation(err) {
+		return ErrSourceInUse
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to save event source: %w", err)
+	}
+
+	return nil
+}
+
+// GetByExternalID gets the source linked to a provider's event, or nil if
+// no event takes its check-ins
+func (r *EventSourceRepository) GetByExternalID(ctx context.Context, provider, externalEventID string) (*models.EventSource, error) {
+	query := `
+		SELECT ` + eventSourceColumns + `
+		FROM event_sources
+		WHERE provider = $1 AND external_event_id = $2
+	`
+
+	source, err := scanEventSource(r.db.QueryRowContext(ctx, query, provider, externalEventID))
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event source: %w", err)
+	}
+
+	return source, nil
+}
+
+// ListByEvent lists the sources of an event
+func (r *EventSourceRepository) ListByEvent(ctx context.Context, eventID uint64) ([]models.EventSource, error) {
+	query := `
+		SELECT ` + eventSourceColumns + `
+		FROM event_sources
+		WHERE event_id = $1
+		ORDER BY provider
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list event sources: %w", err)
+	}
+	defer rows.Close()
+
+	sources := []models.EventSource{}
+	for rows.Next() {
+		source, err := scanEventSource(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event source: %w", err)
+		}
+		sources = append(sources, *source)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list event sources: %w", err)
+	}
+
+	return sources, nil
+}
+
+// Delete unlinks an event from a provider. It returns false if the event
+// had no source for the provider.
+func (r *EventSourceRepository) Delete(ctx context.Context, eventID uint64, provider string) (bool, error) {
+	query := `DELETE FROM event_sources WHERE event_id = $1 AND provider = $2`
+
+	result, err := r.db.ExecContext(ctx, query, eventID, provider)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete event source: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// isUniqueViolation reports whether err is a unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -1,7 +1,9 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -18,51 +20,160 @@ func NewEventRepository(db *DB) *EventRepository {
 	return &EventRepository{db: db}
 }
 
-// Create creates a new event
-func (r *EventRepository) Create(event *models.Event) error {
-	// Parse date string to a proper date
-	date, err := time.Parse("2006-01-02", event.Date)
+// Errors returned by versioned event updates
+var (
+	ErrEventNotFound   = errors.New("event not found")
+	ErrVersionConflict = errors.New("event was modified by another request")
+)
+
+// eventColumns lists the columns read by scanEvent
+const eventColumns = `id, name, to_char(date, 'YYYY-MM-DD'), starts_at, ends_at, time_zone, location, event_type,
+	description, cover_image_url, logo_url, url, capacity, organizer, transferable, status, version, updated_at, luma_event_id,
+	claim_mode, claim_code_hash, max_claims, claims_open_at, claims_close_at`
+
+// scanEvent scans a row selected with eventColumns
+func scanEvent(row rowScanner) (*models.Event, error) {
+	var event models.Event
+	var startsAt, endsAt, updatedAt time.Time
+	var capacity, maxClaims sql.NullInt64
+	var lumaEventID sql.NullString
+	var claimsOpenAt, claimsCloseAt sql.NullTime
+
+	err := row.Scan(
+		&event.ID,
+		&event.Name,
+		&event.Date,
+		&startsAt,
+		&endsAt,
+		&event.TimeZone,
+		&event.Location,
+		&event.Type,
+		&event.Description,
+		&event.CoverImageURL,
+		&event.LogoURL,
+		&event.URL,
+		&capacity,
+		&event.Organizer,
+		&event.Transferable,
+		&event.Status,
+		&event.Version,
+		&updatedAt,
+		&lumaEventID,
+		&event.ClaimPolicy.Mode,
+		&event.ClaimPolicy.CodeHash,
+		&maxClaims,
+		&claimsOpenAt,
+		&claimsCloseAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if capacity.Valid {
+		value := int(capacity.Int64)
+		event.Capacity = &value
+	}
+	event.LumaEventID = lumaEventID.String
+	if maxClaims.Valid {
+		value := int(maxClaims.Int64)
+		event.ClaimPolicy.MaxClaims = &value
+	}
+	if claimsOpenAt.Valid {
+		event.ClaimPolicy.OpensAt = &claimsOpenAt.Time
+	}
+	if claimsCloseAt.Valid {
+		event.ClaimPolicy.ClosesAt = &claimsCloseAt.Time
+	}
+	event.StartsAt = &startsAt
+	event.EndsAt = &endsAt
+	event.UpdatedAt = &updatedAt
+	return &event, nil
+}
+
+// Create creates a new event. Without StartsAt, the event lasts the whole
+// day of Date in its time zone. LumaEventID is only written here; the link
+// to Luma doesn't change afterwards.
+func (r *EventRepository) Create(ctx context.Context, event *models.Event) error {
+	date, err := localDate(event)
 	if err != nil {
-		return fmt.Errorf("invalid date format: %w", err)
+		return err
 	}
 
 	// Insert event into database
 	query := `
-		INSERT INTO events (name, date, location, organizer) 
-		VALUES ($1, $2, $3, $4)
-		RETURNING id
+		INSERT INTO events (name, date, starts_at, ends_at, time_zone, location, event_type,
+			description, cover_image_url, logo_url, url, capacity, organizer, transferable, luma_event_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		RETURNING id, status, version, updated_at, claim_mode
 	`
-	err = r.db.QueryRow(
+	var updatedAt time.Time
+	err = r.db.QueryRowContext(ctx,
 		query,
 		event.Name,
 		date,
+		*event.StartsAt,
+		*event.EndsAt,
+		event.TimeZone,
 		event.Location,
+		event.Type,
+		event.Description,
+		event.CoverImageURL,
+		event.LogoURL,
+		event.URL,
+		nullableInt(event.Capacity),
 		event.Organizer,
-	).Scan(&event.ID)
+		event.Transferable,
+		nullableString(event.LumaEventID),
+	).Scan(&event.ID, &event.Status, &event.Version, &updatedAt, &event.ClaimPolicy.Mode)
 
 	if err != nil {
 		return fmt.Errorf("failed to create event: %w", err)
 	}
 
+	event.UpdatedAt = &updatedAt
 	return nil
 }
 
-// GetByID gets an event by ID
-func (r *EventRepository) GetByID(id uint64) (*models.Event, error) {
+// localDate normalizes an event's schedule and type and returns the local
+// start date to store
+func localDate(event *models.Event) (string, error) {
+	if err := event.NormalizeSchedule(); err != nil {
+		return "", err
+	}
+
+	if event.Type == "" {
+		event.Type = models.EventPhysical
+	}
+
+	return event.Date, nil
+}
+
+// nullableInt converts an optional int to a nullable column value
+func nullableInt(value *int) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+// nullableString converts an optional string to a nullable column value
+func nullableString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// GetByID gets an event by ID. Soft-deleted events are returned too, since
+// their NFTs still refer to them; check Status before exposing them.
+func (r *EventRepository) GetByID(ctx context.Context, id uint64) (*models.Event, error) {
 	query := `
-		SELECT id, name, to_char(date, 'YYYY-MM-DD'), location, organizer
+		SELECT ` + eventColumns + `
 		FROM events
 		WHERE id = $1
 	`
 
-	var event models.Event
-	err := r.db.QueryRow(query, id).Scan(
-		&event.ID,
-		&event.Name,
-		&event.Date,
-		&event.Location,
-		&event.Organizer,
-	)
+	event, err := scanEvent(r.db.QueryRowContext(ctx, query, id))
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -72,18 +183,71 @@ func (r *EventRepository) GetByID(id uint64) (*models.Event, error) {
 		return nil, fmt.Errorf("failed to get event: %w", err)
 	}
 
-	return &event, nil
+	return event, nil
 }
 
-// GetAll gets all events
-func (r *EventRepository) GetAll() ([]models.Event, error) {
+// GetByLumaEventID gets the event imported from a Luma event, ignoring deleted events
+func (r *EventRepository) GetByLumaEventID(ctx context.Context, lumaEventID string) (*models.Event, error) {
 	query := `
-		SELECT id, name, to_char(date, 'YYYY-MM-DD'), location, organizer
+		SELECT ` + eventColumns + `
 		FROM events
+		WHERE luma_event_id = $1 AND status <> 'deleted'
+	`
+
+	event, err := scanEvent(r.db.QueryRowContext(ctx, query, lumaEventID))
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	return event, nil
+}
+
+// GetLinkedToLuma gets the events imported from Luma that haven't been deleted
+func (r *EventRepository) GetLinkedToLuma(ctx context.Context) ([]models.Event, error) {
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events
+		WHERE luma_event_id IS NOT NULL AND status <> 'deleted'
+		ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.Event
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		events = append(events, *event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating events: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetAll gets all events that haven't been deleted
+func (r *EventRepository) GetAll(ctx context.Context) ([]models.Event, error) {
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events
+		WHERE status <> 'deleted'
 		ORDER BY date DESC
 	`
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query events: %w", err)
 	}
@@ -91,18 +255,11 @@ func (r *EventRepository) GetAll() ([]models.Event, error) {
 
 	var events []models.Event
 	for rows.Next() {
-		var event models.Event
-		err := rows.Scan(
-			&event.ID,
-			&event.Name,
-			&event.Date,
-			&event.Location,
-			&event.Organizer,
-		)
+		event, err := scanEvent(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan event: %w", err)
 		}
-		events = append(events, event)
+		events = append(events, *event)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -112,25 +269,311 @@ func (r *EventRepository) GetAll() ([]models.Event, error) {
 	return events, nil
 }
 
-// Update updates an event
-func (r *EventRepository) Update(event *models.Event) error {
-	// Parse date string to a proper date
-	date, err := time.Parse("2006-01-02", event.Date)
+// EventFilter narrows the events returned by List
+type EventFilter struct {
+	From      string // earliest date, YYYY-MM-DD
+	To        string // latest date, YYYY-MM-DD
+	Location  string // matched case-insensitively
+	Organizer string // canonical address
+	Status    string // models.EventActive or models.EventCancelled; deleted events are never listed
+}
+
+// eventSortKeys are the fields events can be sorted by
+var eventSortKeys = map[string]sortKey{
+	"date": {column: "date", cast: "date"},
+	"name": {column: "name"},
+	"id":   {column: "id"},
+}
+
+// List gets a page of events matching the filter, newest first unless
+// page.Sort says otherwise
+func (r *EventRepository) List(ctx context.Context, filter EventFilter, page PageRequest) ([]models.Event, *PageInfo, error) {
+	ks, err := newKeyset(page, eventSortKeys, "-date")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var conds conditions
+	conds.add("status <> ?", models.EventDeleted)
+	if filter.Status != "" {
+		conds.add("status = ?", filter.Status)
+	}
+	if filter.From != "" {
+		from, err := time.Parse("2006-01-02", filter.From)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid from date: %w", err)
+		}
+		conds.add("date >= ?", from)
+	}
+	if filter.To != "" {
+		to, err := time.Parse("2006-01-02", filter.To)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid to date: %w", err)
+		}
+		conds.add("date <= ?", to)
+	}
+	if filter.Location != "" {
+		conds.add("LOWER(location) = LOWER(?)", filter.Location)
+	}
+	if filter.Organizer != "" {
+		conds.add("organizer = ?", filter.Organizer)
+	}
+
+	info := &PageInfo{}
+	countQuery := `SELECT COUNT(*) FROM events ` + conds.where()
+	if err := r.db.QueryRowContext(ctx, countQuery, conds.args...).Scan(&info.Total); err != nil {
+		return nil, nil, fmt.Errorf("failed to count events: %w", err)
+	}
+
+	ks.apply(&conds)
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events
+		` + conds.where() + `
+		` + ks.orderBy(&conds)
+
+	rows, err := r.db.QueryContext(ctx, query, conds.args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.Event
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		events = append(events, *event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating events: %w", err)
+	}
+
+	n, next := ks.next(len(events), func(i int) (string, uint64) {
+		switch ks.field {
+		case "date":
+			return events[i].Date, events[i].ID
+		case "name":
+			return events[i].Name, events[i].ID
+		}
+		return "", events[i].ID
+	})
+
+	info.NextCursor = next
+	return events[:n], info, nil
+}
+
+// Update saves the editable fields and status of an event if its version
+// still matches event.Version, then sets the new version on event.
+// It fails with ErrVersionConflict if the event was changed in the meantime.
+func (r *EventRepository) Update(ctx context.Context, event *models.Event) error {
+	date, err := localDate(event)
 	if err != nil {
-		return fmt.Errorf("invalid date format: %w", err)
+		return err
 	}
 
 	query := `
 		UPDATE events
-		SET name = $1, date = $2, location = $3
-		WHERE id = $4
+		SET name = $1, date = $2, starts_at = $3, ends_at = $4, time_zone = $5, location = $6,
+			event_type = $7, description = $8, cover_image_url = $9, logo_url = $10, url = $11,
+			capacity = $12, organizer = $13, transferable = $14, status = $15,
+			version = version + 1, updated_at = NOW()
+		WHERE id = $16 AND version = $17 AND status <> 'deleted'
+		RETURNING version, updated_at
 	`
 
-	result, err := r.db.Exec(query, event.Name, date, event.Location, event.ID)
+	var updatedAt time.Time
+	err = r.db.QueryRowContext(ctx,
+		query,
+		event.Name,
+		date,
+		*event.StartsAt,
+		*event.EndsAt,
+		event.TimeZone,
+		event.Location,
+		event.Type,
+		event.Description,
+		event.CoverImageURL,
+		event.LogoURL,
+		event.URL,
+		nullableInt(event.Capacity),
+		event.Organizer,
+		event.Transferable,
+		event.Status,
+		event.ID,
+		event.Version,
+	).Scan(&event.Version, &updatedAt)
+
+	if err == sql.ErrNoRows {
+		return r.missOrConflict(ctx, event.ID)
+	}
+
 	if err != nil {
 		return fmt.Errorf("failed to update event: %w", err)
 	}
 
+	event.UpdatedAt = &updatedAt
+	return nil
+}
+
+// SetClaimPolicy replaces the claim policy of an event and bumps its version
+func (r *EventRepository) SetClaimPolicy(ctx context.Context, event *models.Event) error {
+	query := `
+		UPDATE events
+		SET claim_mode = $1, claim_code_hash = $2, max_claims = $3, claims_open_at = $4, claims_close_at = $5,
+			version = version + 1, updated_at = NOW()
+		WHERE id = $6 AND status <> 'deleted'
+		RETURNING version, updated_at
+	`
+
+	policy := event.ClaimPolicy
+	var updatedAt time.Time
+	err := r.db.QueryRowContext(ctx,
+		query,
+		policy.Mode,
+		policy.CodeHash,
+		nullableInt(policy.MaxClaims),
+		policy.OpensAt,
+		policy.ClosesAt,
+		event.ID,
+	).Scan(&event.Version, &updatedAt)
+
+	if err == sql.ErrNoRows {
+		return ErrEventNotFound
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to set claim policy: %w", err)
+	}
+
+	event.UpdatedAt = &updatedAt
+	return nil
+}
+
+// eventDependentTables hold rows of a single event, deleted with it
+var eventDependentTables = []string{
+	"event_permissions",
+	"event_sources",
+	"claims",
+	"direct_claims",
+	"claim_allowlist",
+	"checkins",
+	"checkin_settings",
+}
+
+// Delete removes an event. Events without NFTs are deleted together with
+// their permissions, claims, check-ins and sources; events with NFTs are kept for them and only marked as
+// deleted, which is reported by the first return value. A version of 0
+// skips the version check.
+func (r *EventRepository) Delete(ctx context.Context, id uint64, version int) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the event so no NFT is minted for it while it's being deleted
+	var current int
+	err = tx.QueryRowContext(ctx, `
+		SELECT version
+		FROM events
+		WHERE id = $1 AND status <> 'deleted'
+		FOR UPDATE
+	`, id).Scan(&current)
+
+	if err == sql.ErrNoRows {
+		return false, ErrEventNotFound
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	if version != 0 && version != current {
+		return false, ErrVersionConflict
+	}
+
+	var nftCount int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM nfts WHERE event_id = $1`, id).Scan(&nftCount); err != nil {
+		return false, fmt.Errorf("failed to count NFTs: %w", err)
+	}
+
+	soft := nftCount > 0
+	if soft {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE events
+			SET status = 'deleted', version = version + 1, updated_at = NOW()
+			WHERE id = $1
+		`, id)
+	} else {
+		// None of these reference events(id) with ON DELETE CASCADE
+		for _, table := range eventDependentTables {
+			if _, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE event_id = $1`, id); err != nil {
+				return false, fmt.Errorf("failed to delete %s of event: %w", table, err)
+			}
+		}
+		_, err = tx.ExecContext(ctx, `DELETE FROM events WHERE id = $1`, id)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to delete event: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit event deletion: %w", err)
+	}
+
+	return soft, nil
+}
+
+// missOrConflict tells why a versioned write matched no row
+func (r *EventRepository) missOrConflict(ctx context.Context, id uint64) error {
+	var exists bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM events WHERE id = $1 AND status <> 'deleted')`,
+		id,
+	).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check event: %w", err)
+	}
+
+	if !exists {
+		return ErrEventNotFound
+	}
+	return ErrVersionConflict
+}
+
+// GetCollectionID gets the pallet-nfts collection of an event.
+// The second return value is false if no collection has been created.
+func (r *EventRepository) GetCollectionID(ctx context.Context, eventID uint64) (uint32, bool, error) {
+	query := `SELECT collection_id FROM events WHERE id = $1`
+
+	var collectionID sql.NullInt64
+	err := r.db.QueryRowContext(ctx, query, eventID).Scan(&collectionID)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get collection ID: %w", err)
+	}
+
+	if !collectionID.Valid {
+		return 0, false, nil
+	}
+
+	return uint32(collectionID.Int64), true, nil
+}
+
+// SetCollectionID records the pallet-nfts collection of an event
+func (r *EventRepository) SetCollectionID(ctx context.Context, eventID uint64, collectionID uint32) error {
+	query := `UPDATE events SET collection_id = $1 WHERE id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, collectionID, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to set collection ID: %w", err)
+	}
+
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("failed to get rows affected: %w", err)
@@ -143,13 +586,34 @@ func (r *EventRepository) Update(event *models.Event) error {
 	return nil
 }
 
-// Delete deletes an event
-func (r *EventRepository) Delete(id uint64) error {
-	query := `DELETE FROM events WHERE id = $1`
+// GetChainEventID gets the ID the attendance contract assigned to an event.
+// The second return value is false if the event isn't on the contract.
+func (r *EventRepository) GetChainEventID(ctx context.Context, eventID uint64) (uint64, bool, error) {
+	query := `SELECT chain_event_id FROM events WHERE id = $1`
+
+	var chainEventID sql.NullInt64
+	err := r.db.QueryRowContext(ctx, query, eventID).Scan(&chainEventID)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get chain event ID: %w", err)
+	}
+
+	if !chainEventID.Valid {
+		return 0, false, nil
+	}
+
+	return uint64(chainEventID.Int64), true, nil
+}
+
+// SetChainEventID records the ID the attendance contract assigned to an event
+func (r *EventRepository) SetChainEventID(ctx context.Context, eventID, chainEventID uint64) error {
+	query := `UPDATE events SET chain_event_id = $1 WHERE id = $2`
 
-	result, err := r.db.Exec(query, id)
+	result, err := r.db.ExecContext(ctx, query, int64(chainEventID), eventID)
 	if err != nil {
-		return fmt.Errorf("failed to delete event: %w", err)
+		return fmt.Errorf("failed to set chain event ID: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -162,4 +626,4 @@ func (r *EventRepository) Delete(id uint64) error {
 	}
 
 	return nil
-} 
\ No newline at end of file
+}
@@ -0,0 +1,347 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey identifies the advisory lock held while migrating
+const migrationLockKey = 7384210563
+
+// migration is a numbered schema change. SQL migrations are read from
+// migrations/NNNN_name.up.sql and NNNN_name.down.sql; data migrations that
+// need Go code are registered in goMigrations.
+type migration struct {
+	version int
+	name    string
+	up      func(tx *sql.Tx) error
+	down    func(tx *sql.Tx) error // nil if the migration can't be reverted
+}
+
+// goMigrations are migrations implemented in Go
+var goMigrations = []migration{
+	{
+		version: 6,
+		name:    "canonical_addresses",
+		up:      canonicalizeAddresses,
+		// Canonical hex addresses are valid input everywhere, so there's nothing to undo
+		down: func(tx *sql.Tx) error { return nil },
+	},
+}
+
+// MigrationStatus describes a known migration and whether it has been applied
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// loadMigrations reads the embedded SQL migrations and merges in the Go migrations
+func loadMigrations() ([]migration, error) {
+	byVersion := make(map[int]*migration)
+
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	for _, entry := range entries {
+		fileName := entry.Name()
+
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("unexpected migration file %s", fileName)
+		}
+
+		base := strings.TrimSuffix(fileName, "."+direction+".sql")
+		parts := strings.SplitN(base, "_", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("migration file %s is not named NNNN_name.%s.sql", fileName, direction)
+		}
+
+		version, err := strconv.Atoi(parts[0])
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration file %s has an invalid version", fileName)
+		}
+
+		data, err := migrationFiles.ReadFile(path.Join("migrations", fileName))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", fileName, err)
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &migration{version: version, name: parts[1]}
+			byVersion[version] = m
+		} else if m.name != parts[1] {
+			return nil, fmt.Errorf("migration %04d has files named %s and %s", version, m.name, parts[1])
+		}
+
+		statements := string(data)
+		run := func(tx *sql.Tx) error {
+			_, err := tx.Exec(statements)
+			return err
+		}
+		if direction == "up" {
+			m.up = run
+		} else {
+			m.down = run
+		}
+	}
+
+	for i := range goMigrations {
+		gm := goMigrations[i]
+		if _, exists := byVersion[gm.version]; exists {
+			return nil, fmt.Errorf("migration %04d is defined twice", gm.version)
+		}
+		byVersion[gm.version] = &gm
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == nil {
+			return nil, fmt.Errorf("migration %04d_%s has no up migration", m.version, m.name)
+		}
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	return migrations, nil
+}
+
+// migrator runs migrations on a single connection holding the migration lock
+type migrator struct {
+	conn       *sql.Conn
+	migrations []migration
+}
+
+// withMigrator takes the migration lock, ensures schema_migrations exists and runs fn
+func (db *DB) withMigrator(fn func(m *migrator) error) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	// Advisory locks belong to a session, so everything runs on one connection
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockKey); err != nil {
+			log.Printf("Failed to release migration lock: %v", err)
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name VARCHAR(100) NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	return fn(&migrator{conn: conn, migrations: migrations})
+}
+
+// applied returns when each applied migration ran
+func (m *migrator) applied() (map[int]time.Time, error) {
+	rows, err := m.conn.QueryContext(context.Background(), `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_migrations: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+// run applies or reverts one migration in a transaction together with its schema_migrations row
+func (m *migrator) run(mig migration, up bool) error {
+	tx, err := m.conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if up {
+		if err := mig.up(tx); err != nil {
+			return fmt.Errorf("migration %04d_%s failed: %w", mig.version, mig.name, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, mig.version, mig.name); err != nil {
+			return fmt.Errorf("failed to record migration %04d: %w", mig.version, err)
+		}
+	} else {
+		if mig.down == nil {
+			return fmt.Errorf("migration %04d_%s can't be reverted", mig.version, mig.name)
+		}
+		if err := mig.down(tx); err != nil {
+			return fmt.Errorf("reverting migration %04d_%s failed: %w", mig.version, mig.name, err)
+		}
+		if _, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = $1`, mig.version); err != nil {
+			return fmt.Errorf("failed to record migration %04d: %w", mig.version, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %04d: %w", mig.version, err)
+	}
+
+	if up {
+		log.Printf("Applied migration %04d_%s", mig.version, mig.name)
+	} else {
+		log.Printf("Reverted migration %04d_%s", mig.version, mig.name)
+	}
+	return nil
+}
+
+// up applies every pending migration in order
+func (m *migrator) up() error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+
+	for _, mig := range m.migrations {
+		if _, done := applied[mig.version]; done {
+			continue
+		}
+		if err := m.run(mig, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// down reverts the most recently applied migrations
+func (m *migrator) down(steps int) error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+
+	for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+		mig := m.migrations[i]
+		if _, done := applied[mig.version]; !done {
+			continue
+		}
+		if err := m.run(mig, false); err != nil {
+			return err
+		}
+		steps--
+	}
+
+	return nil
+}
+
+// MigrateUp applies all pending migrations.
+// Migration 0001 creates the original tables with IF NOT EXISTS, so databases
+// created before schema_migrations existed are adopted without changes.
+func (db *DB) MigrateUp() error {
+	if err := db.withMigrator(func(m *migrator) error { return m.up() }); err != nil {
+		return err
+	}
+
+	log.Println("Database migrations completed successfully")
+	return nil
+}
+
+// MigrateDown reverts the last steps applied migrations
+func (db *DB) MigrateDown(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be positive")
+	}
+
+	return db.withMigrator(func(m *migrator) error { return m.down(steps) })
+}
+
+// MigrateRedo reverts the last applied migration and applies it again
+func (db *DB) MigrateRedo() error {
+	return db.withMigrator(func(m *migrator) error {
+		applied, err := m.applied()
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mig := m.migrations[i]
+			if _, done := applied[mig.version]; !done {
+				continue
+			}
+			if err := m.run(mig, false); err != nil {
+				return err
+			}
+			return m.run(mig, true)
+		}
+
+		return fmt.Errorf("no applied migrations to redo")
+	})
+}
+
+// MigrationStatus lists the known migrations and when they were applied
+func (db *DB) MigrationStatus() ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+
+	err := db.withMigrator(func(m *migrator) error {
+		applied, err := m.applied()
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			status := MigrationStatus{Version: mig.version, Name: mig.name}
+			if appliedAt, done := applied[mig.version]; done {
+				status.AppliedAt = &appliedAt
+				delete(applied, mig.version)
+			}
+			statuses = append(statuses, status)
+		}
+
+		// Migrations recorded by a newer build than this one
+		for version, appliedAt := range applied {
+			appliedAt := appliedAt
+			statuses = append(statuses, MigrationStatus{Version: version, Name: "(unknown)", AppliedAt: &appliedAt})
+		}
+
+		sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+		return nil
+	})
+
+	return statuses, err
+}
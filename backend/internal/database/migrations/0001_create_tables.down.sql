@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS event_permissions;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS nfts;
+DROP TABLE IF EXISTS events;
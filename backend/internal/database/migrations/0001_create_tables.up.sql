@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS events (
+	id SERIAL PRIMARY KEY,
+	name VARCHAR(100) NOT NULL,
+	date DATE NOT NULL,
+	location VARCHAR(100) NOT NULL,
+	organizer VARCHAR(100) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS nfts (
+	id SERIAL PRIMARY KEY,
+	event_id INTEGER NOT NULL REFERENCES events(id),
+	owner VARCHAR(100) NOT NULL,
+	metadata JSONB NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	tx_hash VARCHAR(100),
+	confirmed BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE IF NOT EXISTS users (
+	id SERIAL PRIMARY KEY,
+	wallet_address VARCHAR(100) NOT NULL UNIQUE,
+	username VARCHAR(100),
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	last_login TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS event_permissions (
+	id SERIAL PRIMARY KEY,
+	event_id INTEGER NOT NULL REFERENCES events(id),
+	user_id INTEGER NOT NULL REFERENCES users(id),
+	role VARCHAR(20) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE(event_id, user_id)
+);
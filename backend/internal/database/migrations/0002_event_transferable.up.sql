@@ -0,0 +1,2 @@
+-- Per-event transfer policy; FALSE makes the event's badges soulbound
+ALTER TABLE events ADD COLUMN IF NOT EXISTS transferable BOOLEAN NOT NULL DEFAULT TRUE;
@@ -0,0 +1,2 @@
+-- pallet-nfts collection holding the event's badges, if minted with pallet-nfts
+ALTER TABLE events ADD COLUMN IF NOT EXISTS collection_id BIGINT;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS nft_ownership_history;
+
+ALTER TABLE nfts DROP COLUMN IF EXISTS burn_reason;
+ALTER TABLE nfts DROP COLUMN IF EXISTS burned_at;
+ALTER TABLE nfts DROP COLUMN IF EXISTS burned;
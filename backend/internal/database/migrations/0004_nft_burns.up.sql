@@ -0,0 +1,15 @@
+-- Track burned (revoked) NFTs
+ALTER TABLE nfts ADD COLUMN IF NOT EXISTS burned BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE nfts ADD COLUMN IF NOT EXISTS burned_at TIMESTAMP;
+ALTER TABLE nfts ADD COLUMN IF NOT EXISTS burn_reason TEXT;
+
+CREATE TABLE IF NOT EXISTS nft_ownership_history (
+	id SERIAL PRIMARY KEY,
+	nft_id INTEGER NOT NULL REFERENCES nfts(id),
+	action VARCHAR(20) NOT NULL,
+	from_owner VARCHAR(100),
+	to_owner VARCHAR(100),
+	reason TEXT,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_nft_ownership_history_nft ON nft_ownership_history (nft_id);
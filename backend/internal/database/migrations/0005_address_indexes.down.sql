@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_events_organizer;
+DROP INDEX IF EXISTS idx_nfts_event_owner;
+DROP INDEX IF EXISTS idx_nfts_owner;
@@ -0,0 +1,4 @@
+-- Index address columns used for ownership lookups
+CREATE INDEX IF NOT EXISTS idx_nfts_owner ON nfts (owner);
+CREATE INDEX IF NOT EXISTS idx_nfts_event_owner ON nfts (event_id, owner);
+CREATE INDEX IF NOT EXISTS idx_events_organizer ON events (organizer);
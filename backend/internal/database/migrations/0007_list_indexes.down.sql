@@ -0,0 +1,7 @@
+CREATE INDEX IF NOT EXISTS idx_nfts_owner ON nfts (owner);
+DROP INDEX IF EXISTS idx_nfts_owner_id;
+
+DROP INDEX IF EXISTS idx_nfts_event_id;
+DROP INDEX IF EXISTS idx_events_location;
+DROP INDEX IF EXISTS idx_events_name_id;
+DROP INDEX IF EXISTS idx_events_date_id;
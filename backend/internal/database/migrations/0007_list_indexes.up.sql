@@ -0,0 +1,10 @@
+-- Index the sort keys and filters of the paginated list endpoints.
+-- Each sort index ends in id, the keyset tie-breaker.
+CREATE INDEX IF NOT EXISTS idx_events_date_id ON events (date, id);
+CREATE INDEX IF NOT EXISTS idx_events_name_id ON events (name, id);
+CREATE INDEX IF NOT EXISTS idx_events_location ON events (LOWER(location));
+CREATE INDEX IF NOT EXISTS idx_nfts_event_id ON nfts (event_id, id);
+
+-- Listing an owner's NFTs pages by id, which supersedes the plain owner index
+CREATE INDEX IF NOT EXISTS idx_nfts_owner_id ON nfts (owner, id);
+DROP INDEX IF EXISTS idx_nfts_owner;
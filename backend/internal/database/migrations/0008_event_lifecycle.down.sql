@@ -0,0 +1,3 @@
+ALTER TABLE events DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE events DROP COLUMN IF EXISTS version;
+ALTER TABLE events DROP COLUMN IF EXISTS status;
@@ -0,0 +1,5 @@
+-- Optimistic concurrency and lifecycle state for events.
+-- Events that already have NFTs are soft-deleted with status 'deleted'.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT NOW();
@@ -0,0 +1,8 @@
+ALTER TABLE events DROP COLUMN IF EXISTS url;
+ALTER TABLE events DROP COLUMN IF EXISTS event_type;
+ALTER TABLE events DROP COLUMN IF EXISTS capacity;
+ALTER TABLE events DROP COLUMN IF EXISTS cover_image_url;
+ALTER TABLE events DROP COLUMN IF EXISTS description;
+ALTER TABLE events DROP COLUMN IF EXISTS time_zone;
+ALTER TABLE events DROP COLUMN IF EXISTS ends_at;
+ALTER TABLE events DROP COLUMN IF EXISTS starts_at;
@@ -0,0 +1,19 @@
+-- Start/end instants with the IANA time zone the event is held in, plus
+-- descriptive fields. date stays as the local start date.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS starts_at TIMESTAMPTZ;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS ends_at TIMESTAMPTZ;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS cover_image_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS capacity INTEGER;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS event_type VARCHAR(20) NOT NULL DEFAULT 'physical';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS url TEXT NOT NULL DEFAULT '';
+
+-- Existing events last their whole (UTC) day
+UPDATE events
+SET starts_at = date::timestamp AT TIME ZONE 'UTC',
+	ends_at = (date + 1)::timestamp AT TIME ZONE 'UTC'
+WHERE starts_at IS NULL;
+
+ALTER TABLE events ALTER COLUMN starts_at SET NOT NULL;
+ALTER TABLE events ALTER COLUMN ends_at SET NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_events_luma_event_id;
+ALTER TABLE events DROP COLUMN IF EXISTS luma_event_id;
@@ -0,0 +1,7 @@
+-- Links events imported from Luma to their Luma event. A Luma event can be
+-- imported again once its local event is deleted.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS luma_event_id VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_events_luma_event_id
+	ON events (luma_event_id)
+	WHERE luma_event_id IS NOT NULL AND status <> 'deleted';
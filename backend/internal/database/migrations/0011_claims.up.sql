@@ -0,0 +1,18 @@
+-- NFTs parked for attendees who checked in without a usable wallet. The
+-- attendee claims one through the link sent to them; only the SHA-256 of
+-- the link's token is stored.
+CREATE TABLE IF NOT EXISTS claims (
+	id SERIAL PRIMARY KEY,
+	event_id INTEGER NOT NULL REFERENCES events(id),
+	attendee_id VARCHAR(100) NOT NULL,
+	name VARCHAR(255) NOT NULL DEFAULT '',
+	email VARCHAR(255) NOT NULL DEFAULT '',
+	token_hash CHAR(64) NOT NULL UNIQUE,
+	status VARCHAR(20) NOT NULL DEFAULT 'pending',
+	wallet_address VARCHAR(100),
+	nft_id INTEGER REFERENCES nfts(id),
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	claimed_at TIMESTAMP,
+	UNIQUE(event_id, attendee_id)
+);
+CREATE INDEX IF NOT EXISTS idx_claims_event_status ON claims (event_id, status);
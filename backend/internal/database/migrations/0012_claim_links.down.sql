@@ -0,0 +1,3 @@
+UPDATE claims SET status = 'pending' WHERE status = 'claiming';
+ALTER TABLE claims DROP COLUMN IF EXISTS reserved_at;
+ALTER TABLE claims DROP COLUMN IF EXISTS expires_at;
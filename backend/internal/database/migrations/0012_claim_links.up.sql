@@ -0,0 +1,9 @@
+-- Claim links expire, and a claim is reserved while its NFT is minted so
+-- the same link can't be redeemed twice.
+ALTER TABLE claims ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP;
+ALTER TABLE claims ADD COLUMN IF NOT EXISTS reserved_at TIMESTAMP;
+
+-- Links sent before tokens were signed no longer verify; checking in again
+-- sends a new one
+UPDATE claims SET expires_at = created_at + INTERVAL '30 days' WHERE expires_at IS NULL;
+ALTER TABLE claims ALTER COLUMN expires_at SET NOT NULL;
@@ -0,0 +1,22 @@
+-- QR check-in: each event with check-in enabled has a TOTP secret whose
+-- codes are shown by the organizer, and an optional geofence.
+CREATE TABLE IF NOT EXISTS checkin_settings (
+	event_id INTEGER PRIMARY KEY REFERENCES events(id),
+	secret BYTEA NOT NULL,
+	enabled BOOLEAN NOT NULL DEFAULT TRUE,
+	period_seconds INTEGER NOT NULL DEFAULT 30,
+	latitude DOUBLE PRECISION,
+	longitude DOUBLE PRECISION,
+	radius_meters DOUBLE PRECISION,
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- One check-in per wallet and event
+CREATE TABLE IF NOT EXISTS checkins (
+	id SERIAL PRIMARY KEY,
+	event_id INTEGER NOT NULL REFERENCES events(id),
+	wallet_address VARCHAR(100) NOT NULL,
+	nft_id INTEGER REFERENCES nfts(id),
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE(event_id, wallet_address)
+);
@@ -0,0 +1,12 @@
+-- Ticketing providers an event takes check-in webhooks from, besides the
+-- Luma event it may have been imported from
+CREATE TABLE IF NOT EXISTS event_sources (
+	event_id INTEGER NOT NULL REFERENCES events(id),
+	provider VARCHAR(32) NOT NULL,
+	external_event_id VARCHAR(255) NOT NULL,
+	webhook_secret VARCHAR(255) NOT NULL DEFAULT '',
+	wallet_question VARCHAR(255) NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (event_id, provider),
+	UNIQUE (provider, external_event_id)
+);
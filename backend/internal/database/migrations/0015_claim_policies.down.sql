@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS direct_claims;
+DROP TABLE IF EXISTS claim_allowlist;
+ALTER TABLE events DROP COLUMN IF EXISTS claims_close_at;
+ALTER TABLE events DROP COLUMN IF EXISTS claims_open_at;
+ALTER TABLE events DROP COLUMN IF EXISTS max_claims;
+ALTER TABLE events DROP COLUMN IF EXISTS claim_code_hash;
+ALTER TABLE events DROP COLUMN IF EXISTS claim_mode;
@@ -0,0 +1,26 @@
+-- How attendees can claim an event's badge besides check-in webhooks:
+-- with a secret code, from an allowlist or by anyone while claims are open
+ALTER TABLE events ADD COLUMN IF NOT EXISTS claim_mode VARCHAR(16) NOT NULL DEFAULT 'webhook';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS claim_code_hash VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS max_claims INTEGER;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS claims_open_at TIMESTAMPTZ;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS claims_close_at TIMESTAMPTZ;
+
+-- Wallets allowed to claim events in allowlist mode
+CREATE TABLE IF NOT EXISTS claim_allowlist (
+	event_id INTEGER NOT NULL REFERENCES events(id),
+	wallet_address VARCHAR(100) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (event_id, wallet_address)
+);
+
+-- Badges claimed under an event's claim policy, one per wallet
+CREATE TABLE IF NOT EXISTS direct_claims (
+	id SERIAL PRIMARY KEY,
+	event_id INTEGER NOT NULL REFERENCES events(id),
+	wallet_address VARCHAR(100) NOT NULL,
+	nft_id INTEGER REFERENCES nfts(id),
+	ip_address VARCHAR(64) NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE(event_id, wallet_address)
+);
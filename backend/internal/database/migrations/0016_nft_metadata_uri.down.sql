@@ -0,0 +1 @@
+ALTER TABLE nfts DROP COLUMN IF EXISTS metadata_uri;
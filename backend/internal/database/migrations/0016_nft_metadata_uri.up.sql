@@ -0,0 +1,3 @@
+-- URI of the NFT's metadata as stored on chain: the backend's metadata
+-- endpoint or an IPFS CID
+ALTER TABLE nfts ADD COLUMN IF NOT EXISTS metadata_uri TEXT NOT NULL DEFAULT '';
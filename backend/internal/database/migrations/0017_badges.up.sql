@@ -0,0 +1,2 @@
+-- Organizer logo shown on an event's badge artwork
+ALTER TABLE events ADD COLUMN IF NOT EXISTS logo_url TEXT NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE nfts DROP COLUMN IF EXISTS chain_token_id;
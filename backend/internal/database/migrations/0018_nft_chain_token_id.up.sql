@@ -0,0 +1,4 @@
+-- ID the chain assigned to the NFT's token, recorded once the mint is
+-- confirmed. Transfers and burns address the token by this ID; it's NULL
+-- for NFTs whose mint wasn't confirmed, which can't be moved or revoked.
+ALTER TABLE nfts ADD COLUMN IF NOT EXISTS chain_token_id BIGINT;
@@ -0,0 +1 @@
+ALTER TABLE events DROP COLUMN IF EXISTS chain_event_id;
@@ -0,0 +1,4 @@
+-- ID the attendance contract assigned to the event, which needn't match the
+-- database ID. Events registered earlier were assumed to share their ID.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS chain_event_id BIGINT;
+UPDATE events SET chain_event_id = id WHERE chain_event_id IS NULL AND collection_id IS NULL;
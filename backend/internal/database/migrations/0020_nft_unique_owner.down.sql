@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_nfts_event_owner_live;
+
+-- Without mint_failed, failed mints would look like pending ones. They never
+-- reached the chain, so revoke them; migrating up again leaves them revoked.
+UPDATE nfts
+SET burned = TRUE, burned_at = NOW(), burn_reason = 'Mint failed'
+WHERE mint_failed AND NOT burned;
+
+ALTER TABLE nfts DROP COLUMN IF EXISTS mint_failed;
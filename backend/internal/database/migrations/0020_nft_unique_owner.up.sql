@@ -0,0 +1,28 @@
+-- Mints that failed before reaching the chain can be retried for the wallet
+ALTER TABLE nfts ADD COLUMN IF NOT EXISTS mint_failed BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Duplicates from before a wallet was limited to one NFT per event hold real
+-- tokens, so they're reported instead of being revoked here. Revoke the
+-- extra NFTs on the previous release with POST /api/admin/nfts/:id/revoke,
+-- which burns them on chain, then migrate again.
+DO $$
+DECLARE
+	duplicates TEXT;
+BEGIN
+	SELECT string_agg(format('event %s, owner %s: NFTs %s', event_id, owner, ids), '; ')
+	INTO duplicates
+	FROM (
+		SELECT event_id, owner, string_agg(id::text, ', ' ORDER BY id) AS ids
+		FROM nfts
+		WHERE NOT burned
+		GROUP BY event_id, owner
+		HAVING COUNT(*) > 1
+	) AS duplicate;
+
+	IF duplicates IS NOT NULL THEN
+		RAISE EXCEPTION 'wallets hold more than one NFT of an event; revoke the duplicates on chain and migrate again: %', duplicates;
+	END IF;
+END $$;
+
+-- A wallet holds at most one live NFT per event
+CREATE UNIQUE INDEX IF NOT EXISTS idx_nfts_event_owner_live ON nfts (event_id, owner) WHERE NOT burned;
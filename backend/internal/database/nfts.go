@@ -1,13 +1,43 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
 )
 
+// Ownership change actions
+const (
+	OwnershipMint     = "mint"
+	OwnershipTransfer = "transfer"
+	OwnershipBurn     = "burn"
+)
+
+// OwnershipChange records a change of an NFT's owner
+type OwnershipChange struct {
+	ID        uint64    `json:"id"`
+	NFTID     uint64    `json:"nft_id"`
+	Action    string    `json:"action"`
+	FromOwner string    `json:"from_owner,omitempty"`
+	ToOwner   string    `json:"to_owner,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ErrEventNotMintable is returned when creating an NFT for an event that is
+// missing, cancelled or deleted
+var ErrEventNotMintable = errors.New("event is not open for minting")
+
+// ErrAlreadyMinted is returned when creating or transferring an NFT for a
+// wallet that already holds one for the event
+var ErrAlreadyMinted = errors.New("wallet already holds an NFT for this event")
+
 // NFTRepository handles database operations for NFTs
 type NFTRepository struct {
 	db *DB
@@ -18,55 +48,134 @@ func NewNFTRepository(db *DB) *NFTRepository {
 	return &NFTRepository{db: db}
 }
 
+// nftColumns lists the columns read by scanNFT
+const nftColumns = `id, event_id, owner, metadata, metadata_uri, chain_token_id, tx_hash, confirmed, burned, burned_at, burn_reason`
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanNFT scans a row selected with nftColumns
+func scanNFT(row rowScanner) (*models.NFT, error) {
+	var nft models.NFT
+	var metadataJSON []byte
+	var chainTokenID sql.NullInt64
+	var txHash sql.NullString
+	var confirmed bool
+	var burnedAt sql.NullTime
+	var burnReason sql.NullString
+
+	err := row.Scan(
+		&nft.ID,
+		&nft.EventID,
+		&nft.Owner,
+		&metadataJSON,
+		&nft.MetadataURI,
+		&chainTokenID,
+		&txHash,
+		&confirmed,
+		&nft.Burned,
+		&burnedAt,
+		&burnReason,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	nft.ChainTokenID = uint64(chainTokenID.Int64)
+	if burnedAt.Valid {
+		nft.BurnedAt = &burnedAt.Time
+	}
+	nft.BurnReason = burnReason.String
+
+	// Parse metadata JSON
+	if err := json.Unmarshal(metadataJSON, &nft.Metadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+
+	return &nft, nil
+}
+
 // Create creates a new NFT
-func (r *NFTRepository) Create(nft *models.NFT) error {
+func (r *NFTRepository) Create(ctx context.Context, nft *models.NFT) error {
 	// Convert metadata to JSON
 	metadataJSON, err := json.Marshal(nft.Metadata)
 	if err != nil {
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 
-	// Insert NFT into database
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Insert NFT into database, provided its event is still active. The
+	// unique index on live NFTs lets a wallet hold one per event; a row
+	// whose mint failed is taken over so the mint can be retried.
 	query := `
-		INSERT INTO nfts (event_id, owner, metadata) 
-		VALUES ($1, $2, $3)
-		RETURNING id
+		INSERT INTO nfts (event_id, owner, metadata)
+		SELECT $1, $2, $3
+		FROM events
+		WHERE id = $1 AND status = 'active'
+		ON CONFLICT (event_id, owner) WHERE NOT burned DO UPDATE
+		SET metadata = EXCLUDED.metadata, metadata_uri = '', chain_token_id = NULL,
+			tx_hash = NULL, confirmed = FALSE, mint_failed = FALSE
+		WHERE nfts.mint_failed
+		RETURNING id, (xmax = 0)
 	`
-	err = r.db.QueryRow(
+	var inserted bool
+	err = tx.QueryRowContext(ctx,
 		query,
 		nft.EventID,
 		nft.Owner,
 		metadataJSON,
-	).Scan(&nft.ID)
+	).Scan(&nft.ID, &inserted)
+
+	if err == sql.ErrNoRows {
+		// Either the event isn't active or the wallet already holds an NFT
+		var active bool
+		err = tx.QueryRowContext(ctx,
+			`SELECT EXISTS (SELECT 1 FROM events WHERE id = $1 AND status = 'active')`,
+			nft.EventID,
+		).Scan(&active)
+		if err != nil {
+			return fmt.Errorf("failed to check event status: %w", err)
+		}
+		if !active {
+			return ErrEventNotMintable
+		}
+		return ErrAlreadyMinted
+	}
 
 	if err != nil {
 		return fmt.Errorf("failed to create NFT: %w", err)
 	}
 
+	// Record the initial owner; a retried mint already has its record
+	if inserted {
+		if err := insertOwnershipChange(ctx, tx, nft.ID, OwnershipMint, "", nft.Owner, ""); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit NFT: %w", err)
+	}
+
 	return nil
 }
 
 // GetByID gets an NFT by ID
-func (r *NFTRepository) GetByID(id uint64) (*models.NFT, error) {
+func (r *NFTRepository) GetByID(ctx context.Context, id uint64) (*models.NFT, error) {
 	query := `
-		SELECT id, event_id, owner, metadata, tx_hash, confirmed
+		SELECT ` + nftColumns + `
 		FROM nfts
 		WHERE id = $1
 	`
 
-	var nft models.NFT
-	var metadataJSON []byte
-	var txHash sql.NullString
-	var confirmed bool
-
-	err := r.db.QueryRow(query, id).Scan(
-		&nft.ID,
-		&nft.EventID,
-		&nft.Owner,
-		&metadataJSON,
-		&txHash,
-		&confirmed,
-	)
+	nft, err := scanNFT(r.db.QueryRowContext(ctx, query, id))
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -76,24 +185,19 @@ func (r *NFTRepository) GetByID(id uint64) (*models.NFT, error) {
 		return nil, fmt.Errorf("failed to get NFT: %w", err)
 	}
 
-	// Parse metadata JSON
-	if err := json.Unmarshal(metadataJSON, &nft.Metadata); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
-	}
-
-	return &nft, nil
+	return nft, nil
 }
 
 // GetAllByEventID gets all NFTs for an event
-func (r *NFTRepository) GetAllByEventID(eventID uint64) ([]models.NFT, error) {
+func (r *NFTRepository) GetAllByEventID(ctx context.Context, eventID uint64) ([]models.NFT, error) {
 	query := `
-		SELECT id, event_id, owner, metadata, tx_hash, confirmed
+		SELECT ` + nftColumns + `
 		FROM nfts
 		WHERE event_id = $1
 		ORDER BY id
 	`
 
-	rows, err := r.db.Query(query, eventID)
+	rows, err := r.db.QueryContext(ctx, query, eventID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query NFTs: %w", err)
 	}
@@ -101,29 +205,11 @@ func (r *NFTRepository) GetAllByEventID(eventID uint64) ([]models.NFT, error) {
 
 	var nfts []models.NFT
 	for rows.Next() {
-		var nft models.NFT
-		var metadataJSON []byte
-		var txHash sql.NullString
-		var confirmed bool
-
-		err := rows.Scan(
-			&nft.ID,
-			&nft.EventID,
-			&nft.Owner,
-			&metadataJSON,
-			&txHash,
-			&confirmed,
-		)
+		nft, err := scanNFT(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan NFT: %w", err)
 		}
-
-		// Parse metadata JSON
-		if err := json.Unmarshal(metadataJSON, &nft.Metadata); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
-		}
-
-		nfts = append(nfts, nft)
+		nfts = append(nfts, *nft)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -133,112 +219,150 @@ func (r *NFTRepository) GetAllByEventID(eventID uint64) ([]models.NFT, error) {
 	return nfts, nil
 }
 
-// GetAll gets all NFTs
-func (r *NFTRepository) GetAll() ([]models.NFT, error) {
+// NFTFilter narrows the NFTs returned by List
+type NFTFilter struct {
+	EventID   uint64 // 0 for every event
+	Owner     string // canonical address
+	Confirmed *bool
+	Burned    *bool
+}
+
+// nftSortKeys are the fields NFTs can be sorted by
+var nftSortKeys = map[string]sortKey{
+	"id":       {column: "id"},
+	"event_id": {column: "event_id", cast: "integer"},
+}
+
+// List gets a page of NFTs matching the filter, oldest first unless
+// page.Sort says otherwise
+func (r *NFTRepository) List(ctx context.Context, filter NFTFilter, page PageRequest) ([]models.NFT, *PageInfo, error) {
+	ks, err := newKeyset(page, nftSortKeys, "id")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var conds conditions
+	if filter.EventID != 0 {
+		conds.add("event_id = ?", filter.EventID)
+	}
+	if filter.Owner != "" {
+		conds.add("owner = ?", filter.Owner)
+	}
+	if filter.Confirmed != nil {
+		conds.add("confirmed = ?", *filter.Confirmed)
+	}
+	if filter.Burned != nil {
+		conds.add("burned = ?", *filter.Burned)
+	}
+
+	info := &PageInfo{}
+	countQuery := `SELECT COUNT(*) FROM nfts ` + conds.where()
+	if err := r.db.QueryRowContext(ctx, countQuery, conds.args...).Scan(&info.Total); err != nil {
+		return nil, nil, fmt.Errorf("failed to count NFTs: %w", err)
+	}
+
+	ks.apply(&conds)
 	query := `
-		SELECT id, event_id, owner, metadata, tx_hash, confirmed
+		SELECT ` + nftColumns + `
 		FROM nfts
-		ORDER BY id
-	`
+		` + conds.where() + `
+		` + ks.orderBy(&conds)
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query, conds.args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query NFTs: %w", err)
+		return nil, nil, fmt.Errorf("failed to query NFTs: %w", err)
 	}
 	defer rows.Close()
 
 	var nfts []models.NFT
 	for rows.Next() {
-		var nft models.NFT
-		var metadataJSON []byte
-		var txHash sql.NullString
-		var confirmed bool
-
-		err := rows.Scan(
-			&nft.ID,
-			&nft.EventID,
-			&nft.Owner,
-			&metadataJSON,
-			&txHash,
-			&confirmed,
-		)
+		nft, err := scanNFT(rows)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan NFT: %w", err)
-		}
-
-		// Parse metadata JSON
-		if err := json.Unmarshal(metadataJSON, &nft.Metadata); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+			return nil, nil, fmt.Errorf("failed to scan NFT: %w", err)
 		}
-
-		nfts = append(nfts, nft)
+		nfts = append(nfts, *nft)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating NFTs: %w", err)
+		return nil, nil, fmt.Errorf("error iterating NFTs: %w", err)
 	}
 
-	return nfts, nil
+	n, next := ks.next(len(nfts), func(i int) (string, uint64) {
+		return strconv.FormatUint(nfts[i].EventID, 10), nfts[i].ID
+	})
+
+	info.NextCursor = next
+	return nfts[:n], info, nil
 }
 
-// GetAllByOwner gets all NFTs for an owner
-func (r *NFTRepository) GetAllByOwner(owner string) ([]models.NFT, error) {
+// CountByEventID counts the NFTs minted for an event, burned ones included
+func (r *NFTRepository) CountByEventID(ctx context.Context, eventID uint64) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM nfts WHERE event_id = $1`, eventID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count NFTs: %w", err)
+	}
+
+	return count, nil
+}
+
+// HasOwner reports whether a wallet holds an NFT for an event. Burned NFTs
+// and mints that failed don't count.
+func (r *NFTRepository) HasOwner(ctx context.Context, eventID uint64, owner string) (bool, error) {
 	query := `
-		SELECT id, event_id, owner, metadata, tx_hash, confirmed
-		FROM nfts
-		WHERE owner = $1
-		ORDER BY id
+		SELECT EXISTS (
+			SELECT 1 FROM nfts
+			WHERE event_id = $1 AND owner = $2 AND NOT burned AND NOT mint_failed
+		)
 	`
 
-	rows, err := r.db.Query(query, owner)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query NFTs: %w", err)
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, eventID, owner).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check NFT owner: %w", err)
 	}
-	defer rows.Close()
 
-	var nfts []models.NFT
-	for rows.Next() {
-		var nft models.NFT
-		var metadataJSON []byte
-		var txHash sql.NullString
-		var confirmed bool
+	return exists, nil
+}
 
-		err := rows.Scan(
-			&nft.ID,
-			&nft.EventID,
-			&nft.Owner,
-			&metadataJSON,
-			&txHash,
-			&confirmed,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan NFT: %w", err)
-		}
+// SetMetadata replaces an NFT's metadata and records the metadata URI
+// stored on chain for it
+func (r *NFTRepository) SetMetadata(ctx context.Context, id uint64, metadata map[string]interface{}, uri string) error {
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
 
-		// Parse metadata JSON
-		if err := json.Unmarshal(metadataJSON, &nft.Metadata); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
-		}
+	query := `UPDATE nfts SET metadata = $1, metadata_uri = $2 WHERE id = $3`
 
-		nfts = append(nfts, nft)
+	if _, err := r.db.ExecContext(ctx, query, metadataJSON, uri, id); err != nil {
+		return fmt.Errorf("failed to set NFT metadata: %w", err)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating NFTs: %w", err)
+	return nil
+}
+
+// AttendeeNumber returns the position of an NFT among its event's NFTs,
+// counting from 1 in minting order
+func (r *NFTRepository) AttendeeNumber(ctx context.Context, nft *models.NFT) (int, error) {
+	query := `SELECT COUNT(*) FROM nfts WHERE event_id = $1 AND id <= $2`
+
+	var number int
+	if err := r.db.QueryRowContext(ctx, query, nft.EventID, nft.ID).Scan(&number); err != nil {
+		return 0, fmt.Errorf("failed to get attendee number: %w", err)
 	}
 
-	return nfts, nil
+	return number, nil
 }
 
 // UpdateTxHash updates the transaction hash for an NFT
-func (r *NFTRepository) UpdateTxHash(id uint64, txHash string) error {
+func (r *NFTRepository) UpdateTxHash(ctx context.Context, id uint64, txHash string) error {
 	query := `
 		UPDATE nfts
 		SET tx_hash = $1
 		WHERE id = $2
 	`
 
-	result, err := r.db.Exec(query, txHash, id)
+	result, err := r.db.ExecContext(ctx, query, txHash, id)
 	if err != nil {
 		return fmt.Errorf("failed to update NFT transaction hash: %w", err)
 	}
@@ -255,15 +379,67 @@ func (r *NFTRepository) UpdateTxHash(id uint64, txHash string) error {
 	return nil
 }
 
+// ConfirmMint marks an NFT as minted on chain under the given token ID.
+// A zero token ID means the chain didn't report one and is stored as NULL.
+func (r *NFTRepository) ConfirmMint(ctx context.Context, id, chainTokenID uint64) error {
+	query := `
+		UPDATE nfts
+		SET confirmed = TRUE, chain_token_id = NULLIF($1::BIGINT, 0)
+		WHERE id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, int64(chainTokenID), id)
+	if err != nil {
+		return fmt.Errorf("failed to confirm NFT mint: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("NFT not found")
+	}
+
+	return nil
+}
+
+// MarkMintFailed records that an NFT's mint failed, so another mint for its
+// wallet and event takes the row over
+func (r *NFTRepository) MarkMintFailed(ctx context.Context, id uint64) error {
+	query := `
+		UPDATE nfts
+		SET mint_failed = TRUE
+		WHERE id = $1 AND NOT confirmed
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark NFT mint as failed: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("NFT not found")
+	}
+
+	return nil
+}
+
 // UpdateConfirmation updates the confirmation status for an NFT
-func (r *NFTRepository) UpdateConfirmation(id uint64, confirmed bool) error {
+func (r *NFTRepository) UpdateConfirmation(ctx context.Context, id uint64, confirmed bool) error {
 	query := `
 		UPDATE nfts
 		SET confirmed = $1
 		WHERE id = $2
 	`
 
-	result, err := r.db.Exec(query, confirmed, id)
+	result, err := r.db.ExecContext(ctx, query, confirmed, id)
 	if err != nil {
 		return fmt.Errorf("failed to update NFT confirmation status: %w", err)
 	}
@@ -278,4 +454,154 @@ func (r *NFTRepository) UpdateConfirmation(id uint64, confirmed bool) error {
 	}
 
 	return nil
-} 
\ No newline at end of file
+}
+
+// Transfer moves an NFT to a new owner and records the change
+func (r *NFTRepository) Transfer(ctx context.Context, id uint64, to, reason string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the NFT and read its current owner
+	var from string
+	err = tx.QueryRowContext(ctx, `
+		SELECT owner
+		FROM nfts
+		WHERE id = $1 AND NOT burned
+		FOR UPDATE
+	`, id).Scan(&from)
+
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("NFT not found or burned")
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to get NFT owner: %w", err)
+	}
+
+	// A failed mint for the new owner would otherwise hold the event's slot
+	_, err = tx.ExecContext(ctx, `
+		UPDATE nfts
+		SET burned = TRUE, burned_at = NOW(), burn_reason = 'Mint failed'
+		WHERE event_id = (SELECT event_id FROM nfts WHERE id = $2)
+			AND owner = $1 AND mint_failed AND NOT burned
+	`, to, id)
+	if err != nil {
+		return fmt.Errorf("failed to release failed mint: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE nfts SET owner = $1 WHERE id = $2`, to, id); err != nil {
+		if isUniqueViolation(err) {
+			return ErrAlreadyMinted
+		}
+		return fmt.Errorf("failed to transfer NFT: %w", err)
+	}
+
+	if err := insertOwnershipChange(ctx, tx, id, OwnershipTransfer, from, to, reason); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transfer: %w", err)
+	}
+
+	return nil
+}
+
+// Burn marks an NFT as burned and records the revocation
+func (r *NFTRepository) Burn(ctx context.Context, id uint64, reason string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Mark the NFT as burned, keeping the last owner for the history
+	var owner string
+	err = tx.QueryRowContext(ctx, `
+		UPDATE nfts
+		SET burned = TRUE, burned_at = NOW(), burn_reason = $1
+		WHERE id = $2 AND NOT burned
+		RETURNING owner
+	`, reason, id).Scan(&owner)
+
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("NFT not found or already burned")
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to burn NFT: %w", err)
+	}
+
+	if err := insertOwnershipChange(ctx, tx, id, OwnershipBurn, owner, "", reason); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit burn: %w", err)
+	}
+
+	return nil
+}
+
+// GetOwnershipHistory gets the ownership changes of an NFT, oldest first
+func (r *NFTRepository) GetOwnershipHistory(ctx context.Context, nftID uint64) ([]OwnershipChange, error) {
+	query := `
+		SELECT id, nft_id, action, from_owner, to_owner, reason, created_at
+		FROM nft_ownership_history
+		WHERE nft_id = $1
+		ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, nftID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ownership history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []OwnershipChange
+	for rows.Next() {
+		var change OwnershipChange
+		var from, to, reason sql.NullString
+
+		err := rows.Scan(
+			&change.ID,
+			&change.NFTID,
+			&change.Action,
+			&from,
+			&to,
+			&reason,
+			&change.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ownership change: %w", err)
+		}
+
+		change.FromOwner = from.String
+		change.ToOwner = to.String
+		change.Reason = reason.String
+		history = append(history, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating ownership history: %w", err)
+	}
+
+	return history, nil
+}
+
+// insertOwnershipChange records an ownership change within a transaction
+func insertOwnershipChange(ctx context.Context, tx *sql.Tx, nftID uint64, action, from, to, reason string) error {
+	query := `
+		INSERT INTO nft_ownership_history (nft_id, action, from_owner, to_owner, reason)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''))
+	`
+
+	if _, err := tx.ExecContext(ctx, query, nftID, action, from, to, reason); err != nil {
+		return fmt.Errorf("failed to record ownership change: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package database
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Page sizes of the list methods
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// ErrInvalidPage is returned for unknown sort fields and malformed cursors
+var ErrInvalidPage = errors.New("invalid page request")
+
+// PageRequest selects one page of a list
+type PageRequest struct {
+	Limit  int    // rows per page; DefaultPageLimit if zero, capped at MaxPageLimit
+	Cursor string // NextCursor of the previous page, empty for the first page
+	Sort   string // sort field, prefixed with "-" for descending order
+}
+
+// PageInfo describes where a page sits in the full list
+type PageInfo struct {
+	Total      int    // rows matching the filter across all pages
+	NextCursor string // empty on the last page
+}
+
+// sortKey is a column a list can be ordered by
+type sortKey struct {
+	column string
+	cast   string // SQL type the cursor value is cast to
+}
+
+// pageCursor is the position after the last row of a page. It records the
+// sort it was issued for, so it can't be replayed against another order.
+type pageCursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k,omitempty"`
+	ID   uint64 `json:"id"`
+}
+
+// keyset pages through a list ordered by a sort key, with id breaking ties
+type keyset struct {
+	sort  string
+	field string
+	key   sortKey
+	desc  bool
+	limit int
+	after *pageCursor
+}
+
+// newKeyset resolves a page request against the sort keys a list supports
+func newKeyset(page PageRequest, keys map[string]sortKey, defaultSort string) (*keyset, error) {
+	sort := page.Sort
+	if sort == "" {
+		sort = defaultSort
+	}
+
+	field := strings.TrimPrefix(sort, "-")
+	key, exists := keys[field]
+	if !exists {
+		return nil, fmt.Errorf("%w: unknown sort field %q", ErrInvalidPage, field)
+	}
+
+	ks := &keyset{
+		sort:  sort,
+		field: field,
+		key:   key,
+		desc:  strings.HasPrefix(sort, "-"),
+		limit: page.Limit,
+	}
+	if ks.limit <= 0 {
+		ks.limit = DefaultPageLimit
+	}
+	if ks.limit > MaxPageLimit {
+		ks.limit = MaxPageLimit
+	}
+
+	if page.Cursor != "" {
+		data, err := base64.RawURLEncoding.DecodeString(page.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidPage)
+		}
+
+		var after pageCursor
+		if err := json.Unmarshal(data, &after); err != nil {
+			return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidPage)
+		}
+		if after.Sort != sort {
+			return nil, fmt.Errorf("%w: cursor was issued for sort %q", ErrInvalidPage, after.Sort)
+		}
+		ks.after = &after
+	}
+
+	return ks, nil
+}
+
+// apply adds the position of the cursor to the conditions
+func (k *keyset) apply(conds *conditions) {
+	if k.after == nil {
+		return
+	}
+
+	op := ">"
+	if k.desc {
+		op = "<"
+	}
+
+	if k.key.column == "id" {
+		conds.add("id "+op+" ?", k.after.ID)
+		return
+	}
+
+	value := "?"
+	if k.key.cast != "" {
+		value += "::" + k.key.cast
+	}
+	conds.add(fmt.Sprintf("(%s, id) %s (%s, ?)", k.key.column, op, value), k.after.Key, k.after.ID)
+}
+
+// orderBy returns the ORDER BY and LIMIT clauses of the page. One row more
+// than the limit is fetched to tell whether another page follows.
+func (k *keyset) orderBy(conds *conditions) string {
+	direction := "ASC"
+	if k.desc {
+		direction = "DESC"
+	}
+
+	order := "id " + direction
+	if k.key.column != "id" {
+		order = k.key.column + " " + direction + ", " + order
+	}
+
+	conds.args = append(conds.args, k.limit+1)
+	return fmt.Sprintf("ORDER BY %s LIMIT $%d", order, len(conds.args))
+}
+
+// next trims the extra row fetched by orderBy and returns the cursor of the
+// following page, or "" if this is the last one. key returns the sort value
+// and id of row i.
+func (k *keyset) next(rows int, key func(i int) (string, uint64)) (int, string) {
+	if rows <= k.limit {
+		return rows, ""
+	}
+
+	value, id := key(k.limit - 1)
+	cursor := pageCursor{Sort: k.sort, ID: id}
+	if k.key.column != "id" {
+		cursor.Key = value
+	}
+
+	data, _ := json.Marshal(cursor)
+	return k.limit, base64.RawURLEncoding.EncodeToString(data)
+}
+
+// conditions builds a WHERE clause with numbered placeholders
+type conditions struct {
+	clauses []string
+	args    []interface{}
+}
+
+// add appends a condition; each ? in clause is bound to the next arg
+func (c *conditions) add(clause string, args ...interface{}) {
+	for _, arg := range args {
+		c.args = append(c.args, arg)
+		clause = strings.Replace(clause, "?", "$"+strconv.Itoa(len(c.args)), 1)
+	}
+	c.clauses = append(c.clauses, clause)
+}
+
+// where returns the WHERE clause, or "" without conditions
+func (c *conditions) where() string {
+	if len(c.clauses) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(c.clauses, " AND ")
+}
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var testSortKeys = map[string]sortKey{
+	"id":         {column: "id"},
+	"created_at": {column: "created_at", cast: "TIMESTAMP"},
+}
+
+// nextCursor returns the cursor a page of limit rows issues when another
+// page follows, ending at the given sort value and ID
+func nextCursor(t *testing.T, ks *keyset, value string, id uint64) string {
+	t.Helper()
+
+	rows, cursor := ks.next(ks.limit+1, func(i int) (string, uint64) {
+		if i != ks.limit-1 {
+			t.Fatalf("next read row %d, want the last row of the page %d", i, ks.limit-1)
+		}
+		return value, id
+	})
+	if rows != ks.limit {
+		t.Fatalf("next kept %d rows, want %d", rows, ks.limit)
+	}
+	if cursor == "" {
+		t.Fatal("next returned no cursor for a full page")
+	}
+	return cursor
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		sort  string
+		value string
+		id    uint64
+		want  pageCursor
+	}{
+		{"id", "", 41, pageCursor{Sort: "id", ID: 41}},
+		{"-id", "", 7, pageCursor{Sort: "-id", ID: 7}},
+		{"created_at", "2024-05-01T10:00:00Z", 12, pageCursor{Sort: "created_at", Key: "2024-05-01T10:00:00Z", ID: 12}},
+		{"-created_at", "2024-05-01T10:00:00Z", 3, pageCursor{Sort: "-created_at", Key: "2024-05-01T10:00:00Z", ID: 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			first, err := newKeyset(PageRequest{Limit: 2, Sort: tt.sort}, testSortKeys, "id")
+			if err != nil {
+				t.Fatalf("newKeyset failed: %v", err)
+			}
+			cursor := nextCursor(t, first, tt.value, tt.id)
+
+			second, err := newKeyset(PageRequest{Limit: 2, Sort: tt.sort, Cursor: cursor}, testSortKeys, "id")
+			if err != nil {
+				t.Fatalf("newKeyset with cursor %q failed: %v", cursor, err)
+			}
+			if second.after == nil || !reflect.DeepEqual(*second.after, tt.want) {
+				t.Errorf("cursor decoded to %+v, want %+v", second.after, tt.want)
+			}
+		})
+	}
+}
+
+func TestLastPageHasNoCursor(t *testing.T) {
+	ks, err := newKeyset(PageRequest{Limit: 2}, testSortKeys, "id")
+	if err != nil {
+		t.Fatalf("newKeyset failed: %v", err)
+	}
+
+	rows, cursor := ks.next(2, func(int) (string, uint64) {
+		t.Fatal("next read a row of the last page")
+		return "", 0
+	})
+	if rows != 2 || cursor != "" {
+		t.Errorf("next = %d, %q; want 2 rows and no cursor", rows, cursor)
+	}
+}
+
+func TestInvalidCursors(t *testing.T) {
+	first, err := newKeyset(PageRequest{Limit: 1, Sort: "id"}, testSortKeys, "id")
+	if err != nil {
+		t.Fatalf("newKeyset failed: %v", err)
+	}
+	idCursor := nextCursor(t, first, "", 5)
+
+	tests := []struct {
+		name string
+		page PageRequest
+	}{
+		{"not base64", PageRequest{Cursor: "!!!"}},
+		{"not JSON", PageRequest{Cursor: "bm90IGpzb24"}},
+		{"other sort", PageRequest{Sort: "-id", Cursor: idCursor}},
+		{"unknown sort", PageRequest{Sort: "owner"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newKeyset(tt.page, testSortKeys, "id"); !errors.Is(err, ErrInvalidPage) {
+				t.Errorf("newKeyset = %v, want %v", err, ErrInvalidPage)
+			}
+		})
+	}
+}
+
+func TestPageLimits(t *testing.T) {
+	tests := []struct {
+		limit, want int
+	}{
+		{0, DefaultPageLimit},
+		{-1, DefaultPageLimit},
+		{10, 10},
+		{MaxPageLimit + 1, MaxPageLimit},
+	}
+
+	for _, tt := range tests {
+		ks, err := newKeyset(PageRequest{Limit: tt.limit}, testSortKeys, "id")
+		if err != nil {
+			t.Fatalf("newKeyset failed: %v", err)
+		}
+		if ks.limit != tt.want {
+			t.Errorf("limit %d became %d, want %d", tt.limit, ks.limit, tt.want)
+		}
+	}
+}
+
+func TestKeysetConditions(t *testing.T) {
+	first, _ := newKeyset(PageRequest{Limit: 1, Sort: "-created_at"}, testSortKeys, "id")
+	cursor := nextCursor(t, first, "2024-05-01T10:00:00Z", 9)
+	ks, err := newKeyset(PageRequest{Limit: 1, Sort: "-created_at", Cursor: cursor}, testSortKeys, "id")
+	if err != nil {
+		t.Fatalf("newKeyset failed: %v", err)
+	}
+
+	conds := &conditions{}
+	conds.add("event_id = ?", uint64(3))
+	ks.apply(conds)
+	order := ks.orderBy(conds)
+
+	if want := "WHERE event_id = $1 AND (created_at, id) < ($2::TIMESTAMP, $3)"; conds.where() != want {
+		t.Errorf("where = %q, want %q", conds.where(), want)
+	}
+	if want := "ORDER BY created_at DESC, id DESC LIMIT $4"; order != want {
+		t.Errorf("orderBy = %q, want %q", order, want)
+	}
+	if want := []interface{}{uint64(3), "2024-05-01T10:00:00Z", uint64(9), 2}; !reflect.DeepEqual(conds.args, want) {
+		t.Errorf("args = %v, want %v", conds.args, want)
+	}
+}
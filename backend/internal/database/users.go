@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
@@ -44,13 +45,13 @@ func NewUserRepository(db *DB) *UserRepository {
 }
 
 // Create creates a new user
-func (r *UserRepository) Create(user *User) error {
+func (r *UserRepository) Create(ctx context.Context, user *User) error {
 	query := `
 		INSERT INTO users (wallet_address, username) 
 		VALUES ($1, $2)
 		RETURNING id, created_at
 	`
-	err := r.db.QueryRow(
+	err := r.db.QueryRowContext(ctx,
 		query,
 		user.WalletAddress,
 		user.Username,
@@ -64,7 +65,7 @@ func (r *UserRepository) Create(user *User) error {
 }
 
 // GetByWalletAddress gets a user by wallet address
-func (r *UserRepository) GetByWalletAddress(walletAddress string) (*User, error) {
+func (r *UserRepository) GetByWalletAddress(ctx context.Context, walletAddress string) (*User, error) {
 	query := `
 		SELECT id, wallet_address, username, created_at, last_login
 		FROM users
@@ -74,7 +75,7 @@ func (r *UserRepository) GetByWalletAddress(walletAddress string) (*User, error)
 	var user User
 	var lastLogin sql.NullTime
 
-	err := r.db.QueryRow(query, walletAddress).Scan(
+	err := r.db.QueryRowContext(ctx, query, walletAddress).Scan(
 		&user.ID,
 		&user.WalletAddress,
 		&user.Username,
@@ -98,9 +99,9 @@ func (r *UserRepository) GetByWalletAddress(walletAddress string) (*User, error)
 }
 
 // GetOrCreate gets a user by wallet address or creates a new one
-func (r *UserRepository) GetOrCreate(walletAddress string) (*User, error) {
+func (r *UserRepository) GetOrCreate(ctx context.Context, walletAddress string) (*User, error) {
 	// Try to get existing user
-	user, err := r.GetByWalletAddress(walletAddress)
+	user, err := r.GetByWalletAddress(ctx, walletAddress)
 	if err != nil {
 		return nil, err
 	}
@@ -114,7 +115,7 @@ func (r *UserRepository) GetOrCreate(walletAddress string) (*User, error) {
 	newUser := &User{
 		WalletAddress: walletAddress,
 	}
-	if err := r.Create(newUser); err != nil {
+	if err := r.Create(ctx, newUser); err != nil {
 		return nil, err
 	}
 
@@ -122,14 +123,14 @@ func (r *UserRepository) GetOrCreate(walletAddress string) (*User, error) {
 }
 
 // UpdateLastLogin updates the last login time for a user
-func (r *UserRepository) UpdateLastLogin(userID uint64) error {
+func (r *UserRepository) UpdateLastLogin(ctx context.Context, userID uint64) error {
 	query := `
 		UPDATE users
 		SET last_login = NOW()
 		WHERE id = $1
 	`
 
-	result, err := r.db.Exec(query, userID)
+	result, err := r.db.ExecContext(ctx, query, userID)
 	if err != nil {
 		return fmt.Errorf("failed to update last login: %w", err)
 	}
@@ -157,13 +158,13 @@ func NewPermissionRepository(db *DB) *PermissionRepository {
 }
 
 // Create creates a new event permission
-func (r *PermissionRepository) Create(perm *EventPermission) error {
+func (r *PermissionRepository) Create(ctx context.Context, perm *EventPermission) error {
 	query := `
 		INSERT INTO event_permissions (event_id, user_id, role) 
 		VALUES ($1, $2, $3)
 		RETURNING id, created_at
 	`
-	err := r.db.QueryRow(
+	err := r.db.QueryRowContext(ctx,
 		query,
 		perm.EventID,
 		perm.UserID,
@@ -178,7 +179,7 @@ func (r *PermissionRepository) Create(perm *EventPermission) error {
 }
 
 // GetUserRoleForEvent gets a user's role for an event
-func (r *PermissionRepository) GetUserRoleForEvent(userID, eventID uint64) (Role, error) {
+func (r *PermissionRepository) GetUserRoleForEvent(ctx context.Context, userID, eventID uint64) (Role, error) {
 	query := `
 		SELECT role
 		FROM event_permissions
@@ -186,7 +187,7 @@ func (r *PermissionRepository) GetUserRoleForEvent(userID, eventID uint64) (Role
 	`
 
 	var role Role
-	err := r.db.QueryRow(query, userID, eventID).Scan(&role)
+	err := r.db.QueryRowContext(ctx, query, userID, eventID).Scan(&role)
 
 	if err == sql.ErrNoRows {
 		return "", nil
@@ -200,9 +201,9 @@ func (r *PermissionRepository) GetUserRoleForEvent(userID, eventID uint64) (Role
 }
 
 // UpdateRole updates a user's role for an event
-func (r *PermissionRepository) UpdateRole(userID, eventID uint64, role Role) error {
+func (r *PermissionRepository) UpdateRole(ctx context.Context, userID, eventID uint64, role Role) error {
 	// Check if permission exists
-	_, err := r.GetUserRoleForEvent(userID, eventID)
+	_, err := r.GetUserRoleForEvent(ctx, userID, eventID)
 	if err != nil {
 		return err
 	}
@@ -213,7 +214,7 @@ func (r *PermissionRepository) UpdateRole(userID, eventID uint64, role Role) err
 		WHERE user_id = $2 AND event_id = $3
 	`
 
-	result, err := r.db.Exec(query, role, userID, eventID)
+	result, err := r.db.ExecContext(ctx, query, role, userID, eventID)
 	if err != nil {
 		return fmt.Errorf("failed to update role: %w", err)
 	}
@@ -231,7 +232,7 @@ func (r *PermissionRepository) UpdateRole(userID, eventID uint64, role Role) err
 }
 
 // GetUsersForEvent gets all users with permissions for an event
-func (r *PermissionRepository) GetUsersForEvent(eventID uint64) ([]EventPermission, error) {
+func (r *PermissionRepository) GetUsersForEvent(ctx context.Context, eventID uint64) ([]EventPermission, error) {
 	query := `
 		SELECT p.id, p.event_id, p.user_id, p.role, p.created_at
 		FROM event_permissions p
@@ -239,7 +240,7 @@ func (r *PermissionRepository) GetUsersForEvent(eventID uint64) ([]EventPermissi
 		ORDER BY p.user_id
 	`
 
-	rows, err := r.db.Query(query, eventID)
+	rows, err := r.db.QueryContext(ctx, query, eventID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query permissions: %w", err)
 	}
@@ -269,7 +270,7 @@ func (r *PermissionRepository) GetUsersForEvent(eventID uint64) ([]EventPermissi
 }
 
 // GetEventsForUser gets all events a user has permissions for
-func (r *PermissionRepository) GetEventsForUser(userID uint64) ([]EventPermission, error) {
+func (r *PermissionRepository) GetEventsForUser(ctx context.Context, userID uint64) ([]EventPermission, error) {
 	query := `
 		SELECT p.id, p.event_id, p.user_id, p.role, p.created_at
 		FROM event_permissions p
@@ -277,7 +278,7 @@ func (r *PermissionRepository) GetEventsForUser(userID uint64) ([]EventPermissio
 		ORDER BY p.event_id
 	`
 
-	rows, err := r.db.Query(query, userID)
+	rows, err := r.db.QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query permissions: %w", err)
 	}
@@ -307,13 +308,13 @@ func (r *PermissionRepository) GetEventsForUser(userID uint64) ([]EventPermissio
 }
 
 // Delete deletes a permission
-func (r *PermissionRepository) Delete(userID, eventID uint64) error {
+func (r *PermissionRepository) Delete(ctx context.Context, userID, eventID uint64) error {
 	query := `
 		DELETE FROM event_permissions
 		WHERE user_id = $1 AND event_id = $2
 	`
 
-	result, err := r.db.Exec(query, userID, eventID)
+	result, err := r.db.ExecContext(ctx, query, userID, eventID)
 	if err != nil {
 		return fmt.Errorf("failed to delete permission: %w", err)
 	}
@@ -1,139 +1,280 @@
 package luma
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
 )
 
+// DefaultBaseURL is Luma's public API
+const DefaultBaseURL = "https://public-api.lu.ma"
+
+const (
+	// maxRetries is how many times a rate-limited request is retried
+	maxRetries = 3
+	// maxRetryWait caps the wait asked for by a Retry-After header
+	maxRetryWait = time.Minute
+	// pageLimit is the page size used when listing events and guests
+	pageLimit = 100
+)
+
 // Client handles interactions with the Luma API
 type Client struct {
-	apiKey     string
-	httpClient *http.Client
-	baseURL    string
+	apiKey         string
+	httpClient     *http.Client
+	baseURL        string
+	walletQuestion string
 }
 
-// NewClient creates a new Luma API client
-func NewClient(apiKey string) *Client {
+// NewClient creates a new Luma API client. baseURL defaults to
+// DefaultBaseURL and can point at a local stand-in for development.
+// walletQuestion is the label or ID of the registration question that asks
+// guests for their wallet address.
+func NewClient(apiKey, baseURL, walletQuestion string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
 	return &Client{
-		apiKey:     apiKey,
-		httpClient: &http.Client{},
-		baseURL:    "https://api.lu.ma/v1",
+		apiKey:         apiKey,
+		httpClient:     &http.Client{Timeout: 30 * time.Second},
+		baseURL:        strings.TrimRight(baseURL, "/"),
+		walletQuestion: walletQuestion,
 	}
 }
 
-// GetAttendee gets an attendee by ID
-func (c *Client) GetAttendee(attendeeID string) (*models.Attendee, error) {
-	// If in development mode without API key, use mock data
-	if c.apiKey == "" {
-		return &models.Attendee{
-			ID:            attendeeID,
-			Name:          "John Doe",
-			Email:         "john.doe@example.com",
-			WalletAddress: "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY",
-		}, nil
-	}
-
-	// In a real implementation, this would make an HTTP request to the Luma API
-	// Example:
-	// url := fmt.Sprintf("%s/attendees/%s", c.baseURL, attendeeID)
-	// req, err := http.NewRequest("GET", url, nil)
-	// if err != nil {
-	//     return nil, err
-	// }
-	//
-	// req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	// req.Header.Set("Content-Type", "application/json")
-	//
-	// resp, err := c.httpClient.Do(req)
-	// if err != nil {
-	//     return nil, err
-	// }
-	// defer resp.Body.Close()
-	//
-	// if resp.StatusCode != http.StatusOK {
-	//     return nil, fmt.Errorf("API returned status: %d", resp.StatusCode)
-	// }
-	//
-	// var attendee models.Attendee
-	// if err := json.NewDecoder(resp.Body).Decode(&attendee); err != nil {
-	//     return nil, err
-	// }
-
-	// For now, return mock data
-	return &models.Attendee{
-		ID:            attendeeID,
-		Name:          "John Doe",
-		Email:         "john.doe@example.com",
-		WalletAddress: "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY",
-	}, nil
+// Configured reports whether the client has an API key. Without one, every
+// request fails with ErrNotConfigured.
+func (c *Client) Configured() bool {
+	return c.apiKey != ""
 }
 
-// GetEvent gets an event by ID
-func (c *Client) GetEvent(eventID string) (*models.Event, error) {
-	// If in development mode without API key, use mock data
-	if c.apiKey == "" {
-		return &models.Event{
-			ID:       1,
-			Name:     "Polkadot Meetup",
-			Date:     "2023-06-01",
-			Location: "Berlin",
-		}, nil
-	}
-
-	// In a real implementation, this would make an HTTP request to the Luma API
-	// Similar to GetAttendee but with the events endpoint
-
-	// For now, return mock data
-	return &models.Event{
-		ID:       1,
-		Name:     "Polkadot Meetup",
-		Date:     "2023-06-01",
-		Location: "Berlin",
-	}, nil
+// GetLumaEvent gets an event by its Luma ID
+func (c *Client) GetLumaEvent(ctx context.Context, eventID string) (*Event, error) {
+	var resp struct {
+		Event Event `json:"event"`
+	}
+
+	query := url.Values{"api_id": {eventID}}
+	if err := c.get(ctx, "/public/v1/event/get", query, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Event, nil
+}
+
+// ListCalendarEvents gets one page of the events of the calendar the API key
+// belongs to. An empty cursor starts from the first page.
+func (c *Client) ListCalendarEvents(ctx context.Context, cursor string) (*EventPage, error) {
+	var resp struct {
+		Entries []struct {
+			Event Event `json:"event"`
+		} `json:"entries"`
+		HasMore    bool   `json:"has_more"`
+		NextCursor string `json:"next_cursor"`
+	}
+
+	query := url.Values{"pagination_limit": {strconv.Itoa(pageLimit)}}
+	if cursor != "" {
+		query.Set("pagination_cursor", cursor)
+	}
+	if err := c.get(ctx, "/public/v1/calendar/list-events", query, &resp); err != nil {
+		return nil, err
+	}
+
+	page := &EventPage{Events: make([]Event, 0, len(resp.Entries))}
+	for _, entry := range resp.Entries {
+		page.Events = append(page.Events, entry.Event)
+	}
+	if resp.HasMore {
+		page.NextCursor = resp.NextCursor
+	}
+
+	return page, nil
+}
+
+// AllCalendarEvents gets every event of the calendar, following the pagination cursor
+func (c *Client) AllCalendarEvents(ctx context.Context) ([]Event, error) {
+	var events []Event
+
+	cursor := ""
+	for {
+		page, err := c.ListCalendarEvents(ctx, cursor)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, page.Events...)
+
+		if page.NextCursor == "" || page.NextCursor == cursor {
+			return events, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// ListGuests gets one page of an event's guests. An empty cursor starts
+// from the first page.
+func (c *Client) ListGuests(ctx context.Context, eventID, cursor string) (*GuestPage, error) {
+	var resp struct {
+		Entries []struct {
+			Guest Guest `json:"guest"`
+		} `json:"entries"`
+		HasMore    bool   `json:"has_more"`
+		NextCursor string `json:"next_cursor"`
+	}
+
+	query := url.Values{
+		"event_api_id":     {eventID},
+		"pagination_limit": {strconv.Itoa(pageLimit)},
+	}
+	if cursor != "" {
+		query.Set("pagination_cursor", cursor)
+	}
+	if err := c.get(ctx, "/public/v1/event/get-guests", query, &resp); err != nil {
+		return nil, err
+	}
+
+	page := &GuestPage{Guests: make([]Guest, 0, len(resp.Entries))}
+	for _, entry := range resp.Entries {
+		page.Guests = append(page.Guests, entry.Guest)
+	}
+	if resp.HasMore {
+		page.NextCursor = resp.NextCursor
+	}
+
+	return page, nil
 }
 
-// Actual Luma API integration would look something like this:
+// AllGuests gets every guest of an event, following the pagination cursor
+func (c *Client) AllGuests(ctx context.Context, eventID string) ([]Guest, error) {
+	var guests []Guest
 
-// LumaAPIResponse represents the response structure from Luma API
-type LumaAPIResponse struct {
-	Success bool            `json:"success"`
-	Data    json.RawMessage `json:"data"`
-	Error   string          `json:"error,omitempty"`
+	cursor := ""
+	for {
+		page, err := c.ListGuests(ctx, eventID, cursor)
+		if err != nil {
+			return nil, err
+		}
+		guests = append(guests, page.Guests...)
+
+		if page.NextCursor == "" || page.NextCursor == cursor {
+			return guests, nil
+		}
+		cursor = page.NextCursor
+	}
 }
 
-// fetchFromAPI makes a request to the Luma API
-func (c *Client) fetchFromAPI(method, endpoint string, body []byte) (json.RawMessage, error) {
-	url := c.baseURL + endpoint
+// GetGuest looks up a guest of an event by Luma guest ID or, if idOrEmail
+// contains an @, by email
+func (c *Client) GetGuest(ctx context.Context, eventID, idOrEmail string) (*Guest, error) {
+	var resp struct {
+		Guest Guest `json:"guest"`
+	}
+
+	query := url.Values{"event_api_id": {eventID}}
+	if strings.Contains(idOrEmail, "@") {
+		query.Set("email", idOrEmail)
+	} else {
+		query.Set("api_id", idOrEmail)
+	}
+	if err := c.get(ctx, "/public/v1/event/get-guest", query, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Guest, nil
+}
 
-	req, err := http.NewRequest(method, url, nil)
+// GetAttendee gets the attendee a check-in refers to. The wallet address is
+// the guest's answer to the wallet question, as given; it's empty if they
+// didn't answer.
+func (c *Client) GetAttendee(ctx context.Context, eventID, attendeeID string) (*models.Attendee, error) {
+	guest, err := c.GetGuest(ctx, eventID, attendeeID)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	req.Header.Set("Content-Type", "application/json")
+	return &models.Attendee{
+		ID:            guest.ID,
+		Name:          guest.Name,
+		Email:         guest.Email,
+		WalletAddress: guest.AnswerTo(c.walletQuestion),
+	}, nil
+}
+
+// get sends a GET request and decodes the JSON response into out, retrying
+// while the API is rate limiting
+func (c *Client) get(ctx context.Context, endpoint string, query url.Values, out interface{}) error {
+	if !c.Configured() {
+		return ErrNotConfigured
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := c.do(ctx, endpoint, query, out)
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests || attempt == maxRetries {
+			return err
+		}
+
+		wait := apiErr.RetryAfter
+		if wait <= 0 {
+			wait = time.Duration(attempt+1) * time.Second
+		}
+		if wait > maxRetryWait {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// do sends a single GET request
+func (c *Client) do(ctx context.Context, endpoint string, query url.Values, out interface{}) error {
+	reqURL := c.baseURL + endpoint
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create Luma request: %w", err)
+	}
+	req.Header.Set("x-luma-api-key", c.apiKey)
+	req.Header.Set("Accept", "application/json")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to call Luma API: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status: %d", resp.StatusCode)
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read Luma response: %w", err)
 	}
 
-	var apiResp LumaAPIResponse
-	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-		return nil, err
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp, body)
 	}
 
-	if !apiResp.Success {
-		return nil, fmt.Errorf("API error: %s", apiResp.Error)
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode Luma response: %w", err)
 	}
 
-	return apiResp.Data, nil
-}
\ No newline at end of file
+	return nil
+}
@@ -0,0 +1,95 @@
+package luma
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotConfigured is returned by API calls when the client has no API key
+	ErrNotConfigured = errors.New("luma API key is not configured")
+	// ErrUnauthorized is matched by API errors for a rejected API key
+	ErrUnauthorized = errors.New("luma API key was rejected")
+	// ErrNotFound is matched by API errors for a missing event or guest
+	ErrNotFound = errors.New("not found in Luma")
+	// ErrRateLimited is matched by API errors for requests still rate limited after retrying
+	ErrRateLimited = errors.New("luma API rate limit exceeded")
+)
+
+// APIError is a non-200 response from the Luma API
+type APIError struct {
+	StatusCode int
+	Message    string
+	// RetryAfter is the wait asked for by a 429 response, if any
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("luma API returned status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("luma API returned status %d: %s", e.StatusCode, e.Message)
+}
+
+// Unwrap maps the status code to ErrUnauthorized, ErrNotFound or ErrRateLimited
+func (e *APIError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// newAPIError builds an APIError from a failed response
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+
+	var payload struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if json.Unmarshal(body, &payload) == nil {
+		apiErr.Message = payload.Message
+		if apiErr.Message == "" {
+			apiErr.Message = payload.Error
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+	}
+
+	return apiErr
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,183 @@
+package luma
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// ErrAlreadyImported is returned when importing a Luma event that's already
+// linked to a local event
+var ErrAlreadyImported = errors.New("luma event is already imported")
+
+// SyncResult summarizes a sync of imported events
+type SyncResult struct {
+	Checked int `json:"checked"`
+	Updated int `json:"updated"`
+	Failed  int `json:"failed"`
+}
+
+// Syncer imports Luma events as local events and keeps them up to date
+type Syncer struct {
+	client    *Client
+	eventRepo *database.EventRepository
+}
+
+// NewSyncer creates a new syncer
+func NewSyncer(client *Client, eventRepo *database.EventRepository) *Syncer {
+	return &Syncer{
+		client:    client,
+		eventRepo: eventRepo,
+	}
+}
+
+// Client returns the Luma API client
+func (s *Syncer) Client() *Client {
+	return s.client
+}
+
+// Import creates a local event linked to a Luma event. The caller registers
+// it on chain and grants the organizer's permissions, as for created events.
+func (s *Syncer) Import(ctx context.Context, lumaEvent *Event, organizer string, transferable bool) (*models.Event, error) {
+	existing, err := s.eventRepo.GetByLumaEventID(ctx, lumaEvent.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, ErrAlreadyImported
+	}
+
+	if lumaEvent.StartAt == nil {
+		return nil, fmt.Errorf("luma event %s has no start time", lumaEvent.ID)
+	}
+
+	event := lumaEvent.Model()
+	event.Organizer = organizer
+	event.Transferable = transferable
+	event.LumaEventID = lumaEvent.ID
+
+	if err := s.eventRepo.Create(ctx, event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// Sync copies the name, schedule and location of every imported event from
+// Luma. Events edited concurrently are left for the next sync.
+func (s *Syncer) Sync(ctx context.Context) (*SyncResult, error) {
+	events, err := s.eventRepo.GetLinkedToLuma(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SyncResult{}
+	for i := range events {
+		event := &events[i]
+		result.Checked++
+
+		updated, err := s.syncEvent(ctx, event)
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		if err != nil {
+			log.Printf("Failed to sync event %d from Luma event %s: %v", event.ID, event.LumaEventID, err)
+			result.Failed++
+			continue
+		}
+		if updated {
+			result.Updated++
+		}
+	}
+
+	return result, nil
+}
+
+// syncEvent updates one event from Luma, reporting whether it changed
+func (s *Syncer) syncEvent(ctx context.Context, event *models.Event) (bool, error) {
+	lumaEvent, err := s.client.GetLumaEvent(ctx, event.LumaEventID)
+	if err != nil {
+		return false, err
+	}
+
+	changed, err := applyLumaEvent(event, lumaEvent)
+	if err != nil || !changed {
+		return false, err
+	}
+
+	if err := s.eventRepo.Update(ctx, event); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// applyLumaEvent copies the name, schedule and location of a Luma event to
+// the event imported from it, reporting whether any of them changed
+func applyLumaEvent(event *models.Event, lumaEvent *Event) (bool, error) {
+	if lumaEvent.StartAt == nil {
+		return false, fmt.Errorf("luma event has no start time")
+	}
+
+	// Normalizing fills in the defaults the stored event got when it was saved
+	latest := lumaEvent.Model()
+	if err := latest.NormalizeSchedule(); err != nil {
+		return false, err
+	}
+
+	if event.Name == latest.Name &&
+		sameTime(event.StartsAt, latest.StartsAt) &&
+		sameTime(event.EndsAt, latest.EndsAt) &&
+		event.TimeZone == latest.TimeZone &&
+		event.Location == latest.Location &&
+		event.Type == latest.Type {
+		return false, nil
+	}
+
+	event.Name = latest.Name
+	event.Date = latest.Date
+	event.StartsAt = latest.StartsAt
+	event.EndsAt = latest.EndsAt
+	event.TimeZone = latest.TimeZone
+	event.Location = latest.Location
+	event.Type = latest.Type
+
+	return true, nil
+}
+
+// Run syncs imported events every interval until ctx is done
+func (s *Syncer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := s.Sync(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("Luma sync failed: %v", err)
+				}
+				continue
+			}
+			if result.Updated > 0 || result.Failed > 0 {
+				log.Printf("Luma sync: %d checked, %d updated, %d failed", result.Checked, result.Updated, result.Failed)
+			}
+		}
+	}
+}
+
+// sameTime reports whether two optional times are the same instant
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
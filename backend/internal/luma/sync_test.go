@@ -0,0 +1,176 @@
+package luma
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+func timeAt(t *testing.T, value string) *time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &parsed
+}
+
+// lumaEvent is an in-person Luma event starting late on 1 June in New York,
+// which is already 2 June in UTC
+func lumaEvent(t *testing.T) *Event {
+	return &Event{
+		ID:       "evt-sub0",
+		Name:     "Sub0",
+		StartAt:  timeAt(t, "2025-06-02T01:00:00Z"),
+		EndAt:    timeAt(t, "2025-06-02T04:00:00Z"),
+		TimeZone: "America/New_York",
+		Address:  &Address{Address: "1 Main St", City: "New York"},
+	}
+}
+
+// imported is the event Import stores for a Luma event
+func imported(t *testing.T, lumaEvent *Event) *models.Event {
+	t.Helper()
+	event := lumaEvent.Model()
+	if err := event.NormalizeSchedule(); err != nil {
+		t.Fatal(err)
+	}
+	event.ID = 4
+	event.LumaEventID = lumaEvent.ID
+	return event
+}
+
+func TestEventModel(t *testing.T) {
+	event := lumaEvent(t)
+	model := event.Model()
+	if model.Date != "2025-06-01" {
+		t.Errorf("date = %s, want the local start date 2025-06-01", model.Date)
+	}
+	if model.Location != "New York" || model.Type != models.EventPhysical || !model.Transferable {
+		t.Errorf("model = %+v", model)
+	}
+
+	event.MeetingURL = "https://meet.example.com/sub0"
+	if model := event.Model(); model.Type != models.EventHybrid {
+		t.Errorf("type with a meeting URL = %s, want %s", model.Type, models.EventHybrid)
+	}
+
+	event.Address = nil
+	if model := event.Model(); model.Type != models.EventOnline || model.Location != "Online" {
+		t.Errorf("type and location without an address = %s, %s", model.Type, model.Location)
+	}
+}
+
+func TestApplyLumaEvent(t *testing.T) {
+	tests := []struct {
+		name        string
+		change      func(e *Event)
+		wantChanged bool
+		check       func(t *testing.T, event *models.Event)
+	}{
+		{
+			name:   "unchanged",
+			change: func(e *Event) {},
+		},
+		{
+			name:   "description only",
+			change: func(e *Event) { e.Description = "Not synced" },
+		},
+		{
+			name:        "renamed",
+			change:      func(e *Event) { e.Name = "Sub0 Reset" },
+			wantChanged: true,
+			check: func(t *testing.T, event *models.Event) {
+				if event.Name != "Sub0 Reset" {
+					t.Errorf("name = %s", event.Name)
+				}
+			},
+		},
+		{
+			name: "moved a day later",
+			change: func(e *Event) {
+				e.StartAt = timeAt(t, "2025-06-03T01:00:00Z")
+				e.EndAt = timeAt(t, "2025-06-03T04:00:00Z")
+			},
+			wantChanged: true,
+			check: func(t *testing.T, event *models.Event) {
+				if event.Date != "2025-06-02" || !event.StartsAt.Equal(*timeAt(t, "2025-06-03T01:00:00Z")) {
+					t.Errorf("date = %s, starts at %v", event.Date, event.StartsAt)
+				}
+			},
+		},
+		{
+			name:        "went online",
+			change:      func(e *Event) { e.Address = nil },
+			wantChanged: true,
+			check: func(t *testing.T, event *models.Event) {
+				if event.Location != "Online" || event.Type != models.EventOnline {
+					t.Errorf("location = %s, type = %s", event.Location, event.Type)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := imported(t, lumaEvent(t))
+			latest := lumaEvent(t)
+			tt.change(latest)
+
+			changed, err := applyLumaEvent(event, latest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if tt.check != nil {
+				tt.check(t, event)
+			}
+		})
+	}
+
+	event := imported(t, lumaEvent(t))
+	if _, err := applyLumaEvent(event, &Event{ID: "evt-sub0", Name: "Sub0"}); err == nil {
+		t.Error("event without a start time was applied")
+	}
+}
+
+// TestSyncEventUnchanged syncs an event that matches Luma, which needs no
+// database write
+func TestSyncEventUnchanged(t *testing.T) {
+	latest := lumaEvent(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/public/v1/event/get" || r.URL.Query().Get("api_id") != latest.ID {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("x-luma-api-key") != "test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"event": latest})
+	}))
+	defer server.Close()
+
+	syncer := NewSyncer(NewClient("test-key", server.URL, ""), nil)
+	changed, err := syncer.syncEvent(context.Background(), imported(t, latest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed {
+		t.Error("unchanged event was updated")
+	}
+
+	missing := imported(t, latest)
+	missing.LumaEventID = "evt-deleted"
+	if _, err := syncer.syncEvent(context.Background(), missing); !errors.Is(err, ErrNotFound) {
+		t.Errorf("error for a missing Luma event = %v, want %v", err, ErrNotFound)
+	}
+}
@@ -0,0 +1,135 @@
+package luma
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// Event is an event as returned by the Luma API
+type Event struct {
+	ID          string     `json:"api_id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	StartAt     *time.Time `json:"start_at"`
+	EndAt       *time.Time `json:"end_at"`
+	TimeZone    string     `json:"timezone"`
+	CoverURL    string     `json:"cover_url"`
+	URL         string     `json:"url"`
+	MeetingURL  string     `json:"meeting_url"`
+	Address     *Address   `json:"geo_address_json"`
+}
+
+// Address is the venue of an in-person Luma event
+type Address struct {
+	Address     string `json:"address"`
+	FullAddress string `json:"full_address"`
+	City        string `json:"city"`
+}
+
+// Guest is a registered guest of a Luma event
+type Guest struct {
+	ID             string     `json:"api_id"`
+	Name           string     `json:"user_name"`
+	Email          string     `json:"user_email"`
+	ApprovalStatus string     `json:"approval_status"`
+	CheckedInAt    *time.Time `json:"checked_in_at"`
+	Answers        []Answer   `json:"registration_answers"`
+}
+
+// Answer is a guest's answer to a registration question
+type Answer struct {
+	QuestionID string      `json:"question_id"`
+	Label      string      `json:"label"`
+	Answer     interface{} `json:"answer"`
+}
+
+// AnswerTo returns the guest's answer to a registration question, matched by
+// question ID or case-insensitively by label, or "" if they didn't answer it
+func (g *Guest) AnswerTo(question string) string {
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return ""
+	}
+
+	for _, answer := range g.Answers {
+		if answer.QuestionID != question && !strings.EqualFold(strings.TrimSpace(answer.Label), question) {
+			continue
+		}
+		switch value := answer.Answer.(type) {
+		case nil:
+			return ""
+		case string:
+			return strings.TrimSpace(value)
+		default:
+			return strings.TrimSpace(fmt.Sprint(value))
+		}
+	}
+
+	return ""
+}
+
+// EventPage is one page of a calendar's events
+type EventPage struct {
+	Events []Event
+	// NextCursor fetches the next page; it's empty on the last page
+	NextCursor string
+}
+
+// GuestPage is one page of an event's guests
+type GuestPage struct {
+	Guests []Guest
+	// NextCursor fetches the next page; it's empty on the last page
+	NextCursor string
+}
+
+// Location is a display location for the event: the venue's city or
+// address, or "Online" for events without one
+func (e *Event) Location() string {
+	if e.Address != nil {
+		for _, location := range []string{e.Address.City, e.Address.Address, e.Address.FullAddress} {
+			if location != "" {
+				return location
+			}
+		}
+	}
+	return "Online"
+}
+
+// Model converts the event to the system's event model. The model has no
+// ID and is transferable, matching events created through the API.
+func (e *Event) Model() *models.Event {
+	event := &models.Event{
+		Name:          e.Name,
+		StartsAt:      e.StartAt,
+		EndsAt:        e.EndAt,
+		TimeZone:      e.TimeZone,
+		Location:      e.Location(),
+		Description:   e.Description,
+		CoverImageURL: e.CoverURL,
+		URL:           e.URL,
+		Transferable:  true,
+	}
+
+	switch {
+	case e.Address == nil:
+		event.Type = models.EventOnline
+	case e.MeetingURL != "":
+		event.Type = models.EventHybrid
+	default:
+		event.Type = models.EventPhysical
+	}
+
+	if e.StartAt != nil {
+		// Date is the local start date, falling back to UTC for unknown zones
+		loc, err := time.LoadLocation(e.TimeZone)
+		if err != nil {
+			loc = time.UTC
+		}
+		event.Date = e.StartAt.In(loc).Format("2006-01-02")
+	}
+
+	return event
+}
@@ -0,0 +1,117 @@
+package minting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// MetadataStore publishes NFT metadata and returns the URI stored on chain
+type MetadataStore interface {
+	URI(ctx context.Context, nftID uint64, metadata models.TokenMetadata) (string, error)
+}
+
+// NewMetadataStore returns the metadata store for the configuration. NFTs
+// point at the backend's metadata endpoint under baseURL, unless an IPFS
+// API is configured to pin their metadata.
+func NewMetadataStore(baseURL, ipfsAPIURL string) MetadataStore {
+	store := &HTTPMetadataStore{baseURL: strings.TrimRight(baseURL, "/")}
+	if ipfsAPIURL == "" {
+		return store
+	}
+	return &IPFSMetadataStore{
+		apiURL:     strings.TrimRight(ipfsAPIURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		fallback:   store,
+	}
+}
+
+// HTTPMetadataStore points NFTs at the backend's metadata endpoint, which
+// serves the metadata stored in the database
+type HTTPMetadataStore struct {
+	baseURL string
+}
+
+// URI returns the URL of the NFT's metadata endpoint
+func (s *HTTPMetadataStore) URI(ctx context.Context, nftID uint64, metadata models.TokenMetadata) (string, error) {
+	return s.baseURL + "/" + strconv.FormatUint(nftID, 10), nil
+}
+
+// IPFSMetadataStore pins NFT metadata with an IPFS node's HTTP API. If the
+// node can't be reached, NFTs fall back to the backend's metadata endpoint
+// so minting doesn't depend on it.
+type IPFSMetadataStore struct {
+	apiURL     string
+	httpClient *http.Client
+	fallback   MetadataStore
+}
+
+// URI pins the metadata and returns its ipfs:// URI
+func (s *IPFSMetadataStore) URI(ctx context.Context, nftID uint64, metadata models.TokenMetadata) (string, error) {
+	cid, err := s.add(ctx, metadata)
+	if err != nil {
+		log.Printf("Failed to pin metadata of NFT %d to IPFS, using the metadata endpoint: %v", nftID, err)
+		return s.fallback.URI(ctx, nftID, metadata)
+	}
+	return "ipfs://" + cid, nil
+}
+
+// add uploads the metadata to the IPFS node and returns its CID
+func (s *IPFSMetadataStore) add(ctx context.Context, metadata models.TokenMetadata) (string, error) {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "metadata.json")
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", err
+	}
+	if err := form.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.apiURL+"/api/v0/add?pin=true&cid-version=1", &body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to add metadata: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("IPFS API returned %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var result struct {
+		Hash string `json:"Hash"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode IPFS response: %w", err)
+	}
+	if result.Hash == "" {
+		return "", fmt.Errorf("IPFS response has no CID")
+	}
+
+	return result.Hash, nil
+}
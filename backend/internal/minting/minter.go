@@ -0,0 +1,173 @@
+package minting
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/polkadot"
+)
+
+// Minter creates attendance NFTs in the database and on chain
+type Minter struct {
+	chain        polkadot.AttendanceChain
+	nftRepo      *database.NFTRepository
+	userRepo     *database.UserRepository
+	metadata     MetadataStore
+	imageBaseURL string
+}
+
+// NewMinter creates a new minter
+func NewMinter(
+	chain polkadot.AttendanceChain,
+	nftRepo *database.NFTRepository,
+	userRepo *database.UserRepository,
+	metadata MetadataStore,
+	imageBaseURL string,
+) *Minter {
+	return &Minter{
+		chain:        chain,
+		nftRepo:      nftRepo,
+		userRepo:     userRepo,
+		metadata:     metadata,
+		imageBaseURL: strings.TrimRight(imageBaseURL, "/"),
+	}
+}
+
+// Metadata builds the NFT metadata for an attendee of an event
+func Metadata(event *models.Event, attendeeName string) models.TokenMetadata {
+	description := fmt.Sprintf("Proof of attendance for %s", event.Name)
+	if event.Description != "" {
+		description += "\n\n" + event.Description
+	}
+
+	metadata := models.TokenMetadata{
+		Name:        fmt.Sprintf("Attendance: %s", event.Name),
+		Description: description,
+		Image:       event.CoverImageURL,
+		ExternalURL: event.URL,
+		Attributes: []models.Attribute{
+			{TraitType: "Event", Value: event.Name},
+			{TraitType: "Date", Value: event.Date},
+			{TraitType: "Location", Value: event.Location},
+		},
+	}
+	add := func(traitType string, value interface{}, displayType string) {
+		metadata.Attributes = append(metadata.Attributes, models.Attribute{
+			TraitType:   traitType,
+			Value:       value,
+			DisplayType: displayType,
+		})
+	}
+
+	if attendeeName != "" {
+		add("Attendee", attendeeName, "")
+	}
+
+	// Schedule and descriptive details are only set for events from the database
+	if event.Type != "" {
+		add("Event Type", event.Type, "")
+	}
+	if event.StartsAt != nil {
+		add("Start", event.StartsAt.Unix(), models.DisplayDate)
+	}
+	if event.EndsAt != nil {
+		add("End", event.EndsAt.Unix(), models.DisplayDate)
+	}
+	if event.TimeZone != "" {
+		add("Time Zone", event.TimeZone, "")
+	}
+
+	// Lets wallets flag soulbound badges
+	add("Transferable", event.Transferable, "")
+	add("Minted", time.Now().Unix(), models.DisplayDate)
+
+	return metadata
+}
+
+// Mint stores a new NFT for the recipient and mints it on the blockchain.
+// It fails with database.ErrEventNotMintable once the event is cancelled or
+// deleted, and with database.ErrAlreadyMinted if the recipient already holds
+// an NFT for the event.
+func (m *Minter) Mint(ctx context.Context, event *models.Event, recipient, attendeeName string) (*models.NFT, error) {
+	if !event.Mintable() {
+		return nil, database.ErrEventNotMintable
+	}
+
+	metadata := Metadata(event, attendeeName)
+
+	// First, store in database
+	nft := &models.NFT{
+		EventID:  event.ID,
+		Owner:    recipient,
+		Metadata: metadata.Map(),
+	}
+
+	if err := m.nftRepo.Create(ctx, nft); err != nil {
+		return nil, fmt.Errorf("failed to create NFT in database: %w", err)
+	}
+
+	// Get or create the user; a failure here shouldn't block the mint
+	if _, err := m.userRepo.GetOrCreate(ctx, recipient); err != nil {
+		log.Printf("Failed to get or create user %s: %v", recipient, err)
+	}
+
+	// The badge image and attendee number need the NFT's ID
+	if m.imageBaseURL != "" {
+		metadata.Image = fmt.Sprintf("%s/%d/image", m.imageBaseURL, nft.ID)
+	}
+	if number, err := m.nftRepo.AttendeeNumber(ctx, nft); err != nil {
+		log.Printf("Failed to number NFT %d: %v", nft.ID, err)
+	} else {
+		metadata.Attributes = append(metadata.Attributes, models.Attribute{
+			TraitType:   "Attendee Number",
+			Value:       number,
+			DisplayType: models.DisplayNumber,
+		})
+	}
+
+	// The chain stores a URI pointing at the metadata rather than the metadata itself
+	uri, err := m.metadata.URI(ctx, nft.ID, metadata)
+	if err != nil {
+		return nft, m.failed(ctx, nft, fmt.Errorf("failed to publish NFT metadata: %w", err))
+	}
+	if err := m.nftRepo.SetMetadata(ctx, nft.ID, metadata.Map(), uri); err != nil {
+		return nft, m.failed(ctx, nft, err)
+	}
+	nft.Metadata = metadata.Map()
+	nft.MetadataURI = uri
+
+	// Mint NFT on blockchain
+	tokenID, success, err := m.chain.MintNFT(ctx, event.ID, nft.ID, recipient, uri)
+	if err != nil {
+		return nft, m.failed(ctx, nft, fmt.Errorf("failed to mint NFT: %w", err))
+	}
+
+	if !success {
+		return nft, m.failed(ctx, nft, fmt.Errorf("failed to mint NFT on blockchain"))
+	}
+
+	// The NFT exists on chain now, so failing to record that mustn't fail the
+	// mint, and it's recorded even if the request has gone away
+	if err := m.nftRepo.ConfirmMint(context.WithoutCancel(ctx), nft.ID, tokenID); err != nil {
+		log.Printf("Failed to record the chain token %d of NFT %d: %v", tokenID, nft.ID, err)
+	}
+	nft.ChainTokenID = tokenID
+
+	return nft, nil
+}
+
+// failed marks the NFT of a failed mint so the recipient can be minted for
+// again, and returns the mint's error. The row is marked even if the mint's
+// context was cancelled; otherwise it would hold the wallet's slot for the
+// event forever.
+func (m *Minter) failed(ctx context.Context, nft *models.NFT, err error) error {
+	if markErr := m.nftRepo.MarkMintFailed(context.WithoutCancel(ctx), nft.ID); markErr != nil {
+		log.Printf("Failed to mark the mint of NFT %d as failed: %v", nft.ID, markErr)
+	}
+	return err
+}
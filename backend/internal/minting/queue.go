@@ -0,0 +1,226 @@
+package minting
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// JobStatus is the processing state of a mint job
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobCompleted JobStatus = "completed"
+)
+
+// RowStatus is the outcome of a single row in a mint job
+type RowStatus string
+
+const (
+	RowPending RowStatus = "pending"
+	RowMinted  RowStatus = "minted"
+	RowSkipped RowStatus = "skipped"
+	RowInvalid RowStatus = "invalid"
+	RowFailed  RowStatus = "failed"
+)
+
+// Recipient is a single attendee to mint for
+type Recipient struct {
+	WalletAddress string `json:"wallet_address"`
+	Name          string `json:"name,omitempty"`
+}
+
+// Row tracks the progress of one recipient within a job
+type Row struct {
+	Row           int       `json:"row"`
+	WalletAddress string    `json:"wallet_address"`
+	Name          string    `json:"name,omitempty"`
+	Status        RowStatus `json:"status"`
+	NFTID         uint64    `json:"nft_id,omitempty"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// Job is a batch of mints for an event
+type Job struct {
+	ID        string    `json:"id"`
+	EventID   uint64    `json:"event_id"`
+	Status    JobStatus `json:"status"`
+	Total     int       `json:"total"`
+	Processed int       `json:"processed"`
+	Minted    int       `json:"minted"`
+	Skipped   int       `json:"skipped"`
+	Failed    int       `json:"failed"`
+	Rows      []Row     `json:"rows"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	event *models.Event
+}
+
+// Queue runs mint jobs in the background and keeps their progress
+type Queue struct {
+	ctx    context.Context
+	minter *Minter
+	jobs   *cache.Cache
+	work   chan *Job
+	mutex  sync.Mutex // Guards job fields while a job is being processed
+}
+
+// NewQueue creates a mint queue and starts its worker, which stops when ctx
+// is cancelled. Jobs are processed one row at a time because every mint is
+// signed by the same account and concurrent extrinsics would race on its nonce.
+func NewQueue(ctx context.Context, minter *Minter) *Queue {
+	q := &Queue{
+		ctx:    ctx,
+		minter: minter,
+		jobs:   cache.New(24*time.Hour, time.Hour),
+		work:   make(chan *Job, 100),
+	}
+	go q.run()
+	return q
+}
+
+// ErrQueueStopped is returned when enqueueing a job after the queue's context
+// was cancelled
+var ErrQueueStopped = errors.New("mint queue is stopped")
+
+// Enqueue schedules a job for the given event. Rows that are already
+// resolved (invalid or duplicate) are counted as processed immediately.
+// It fails with ErrQueueStopped once the queue has stopped.
+func (q *Queue) Enqueue(event *models.Event, rows []Row) (*Job, error) {
+	if q.ctx.Err() != nil {
+		return nil, ErrQueueStopped
+	}
+
+	now := time.Now()
+	job := &Job{
+		ID:        newJobID(),
+		EventID:   event.ID,
+		Status:    JobQueued,
+		Total:     len(rows),
+		Rows:      rows,
+		CreatedAt: now,
+		UpdatedAt: now,
+		event:     event,
+	}
+
+	for _, row := range rows {
+		switch row.Status {
+		case RowSkipped:
+			job.Processed++
+			job.Skipped++
+		case RowInvalid:
+			job.Processed++
+			job.Failed++
+		}
+	}
+
+	q.jobs.Set(job.ID, job, cache.DefaultExpiration)
+	// A stopped queue never drains, so don't block on it
+	select {
+	case q.work <- job:
+	case <-q.ctx.Done():
+		q.jobs.Delete(job.ID)
+		return nil, ErrQueueStopped
+	}
+
+	return q.snapshot(job), nil
+}
+
+// Get returns a copy of a job's current progress
+func (q *Queue) Get(id string) (*Job, bool) {
+	value, found := q.jobs.Get(id)
+	if !found {
+		return nil, false
+	}
+	return q.snapshot(value.(*Job)), true
+}
+
+// run processes queued jobs until the queue's context is cancelled
+func (q *Queue) run() {
+	for {
+		select {
+		case job := <-q.work:
+			q.process(job)
+		case <-q.ctx.Done():
+			log.Printf("Mint queue stopped: %v", q.ctx.Err())
+			return
+		}
+	}
+}
+
+// process mints every pending row of a job
+func (q *Queue) process(job *Job) {
+	q.update(job, func() { job.Status = JobRunning })
+	log.Printf("Processing mint job %s for event %d (%d rows)", job.ID, job.EventID, job.Total)
+
+	for i := range job.Rows {
+		if job.Rows[i].Status != RowPending {
+			continue
+		}
+
+		nft, err := q.minter.Mint(q.ctx, job.event, job.Rows[i].WalletAddress, job.Rows[i].Name)
+
+		q.update(job, func() {
+			row := &job.Rows[i]
+			if nft != nil {
+				row.NFTID = nft.ID
+			}
+			switch {
+			case errors.Is(err, database.ErrAlreadyMinted):
+				row.Status = RowSkipped
+				row.Error = database.ErrAlreadyMinted.Error()
+				job.Skipped++
+			case err != nil:
+				row.Status = RowFailed
+				row.Error = err.Error()
+				job.Failed++
+			default:
+				row.Status = RowMinted
+				job.Minted++
+			}
+			job.Processed++
+		})
+	}
+
+	q.update(job, func() { job.Status = JobCompleted })
+	log.Printf("Mint job %s completed: %d minted, %d skipped, %d failed", job.ID, job.Minted, job.Skipped, job.Failed)
+}
+
+// update applies a change to a job under the queue lock
+func (q *Queue) update(job *Job, change func()) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	change()
+	job.UpdatedAt = time.Now()
+}
+
+// snapshot copies a job so callers can read it without holding the lock
+func (q *Queue) snapshot(job *Job) *Job {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	copied := *job
+	copied.Rows = append([]Row(nil), job.Rows...)
+	return &copied
+}
+
+// newJobID generates a random job identifier
+func newJobID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,89 @@
+package minting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/database"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// waitForJob polls a job until it completes
+func waitForJob(t *testing.T, q *Queue, id string) *Job {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, found := q.Get(id)
+		if !found {
+			t.Fatalf("job %s not found", id)
+		}
+		if job.Status == JobCompleted {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %s didn't complete", id)
+	return nil
+}
+
+// TestQueueCountsRows runs a job for a cancelled event, which the minter
+// refuses before touching the database, so every pending row fails
+func TestQueueCountsRows(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	q := NewQueue(ctx, NewMinter(nil, nil, nil, nil, ""))
+
+	event := &models.Event{ID: 4, Status: models.EventCancelled}
+	rows := []Row{
+		{Row: 1, WalletAddress: "alice", Status: RowPending},
+		{Row: 2, WalletAddress: "alice", Status: RowSkipped, Error: "wallet is listed more than once"},
+		{Row: 3, WalletAddress: "nobody", Status: RowInvalid, Error: "invalid address"},
+		{Row: 4, WalletAddress: "bob", Status: RowPending},
+	}
+
+	queued, err := q.Enqueue(event, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queued.EventID != event.ID || queued.Total != 4 {
+		t.Errorf("queued job = %+v", queued)
+	}
+
+	job := waitForJob(t, q, queued.ID)
+	if job.Processed != 4 || job.Minted != 0 || job.Skipped != 1 || job.Failed != 3 {
+		t.Errorf("processed %d, minted %d, skipped %d, failed %d; want 4, 0, 1, 3",
+			job.Processed, job.Minted, job.Skipped, job.Failed)
+	}
+
+	want := []RowStatus{RowFailed, RowSkipped, RowInvalid, RowFailed}
+	for i, status := range want {
+		if job.Rows[i].Status != status {
+			t.Errorf("row %d status = %s, want %s", i+1, job.Rows[i].Status, status)
+		}
+	}
+	if job.Rows[0].Error != database.ErrEventNotMintable.Error() {
+		t.Errorf("row 1 error = %q, want %q", job.Rows[0].Error, database.ErrEventNotMintable)
+	}
+
+	// Get returns a copy, so changing it leaves the queue's job alone
+	job.Rows[0].Status = RowMinted
+	if again, _ := q.Get(job.ID); again.Rows[0].Status != RowFailed {
+		t.Error("Get returned the queue's rows")
+	}
+}
+
+func TestQueueStopped(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	q := NewQueue(ctx, NewMinter(nil, nil, nil, nil, ""))
+	cancel()
+
+	job, err := q.Enqueue(&models.Event{ID: 4}, []Row{{Row: 1, Status: RowPending}})
+	if err != ErrQueueStopped {
+		t.Fatalf("error = %v, want %v", err, ErrQueueStopped)
+	}
+	if job != nil {
+		t.Errorf("stopped queue returned job %+v", job)
+	}
+}
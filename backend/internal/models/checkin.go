@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// CheckInSettings configures QR check-in for an event
+type CheckInSettings struct {
+	EventID uint64 `json:"event_id"`
+	// Secret derives the rotating codes; it never leaves the server
+	Secret        []byte `json:"-"`
+	Enabled       bool   `json:"enabled"`
+	PeriodSeconds int    `json:"period_seconds"` // how often the code changes
+	// The geofence applies when all three are set
+	Latitude     *float64  `json:"latitude,omitempty"`
+	Longitude    *float64  `json:"longitude,omitempty"`
+	RadiusMeters *float64  `json:"radius_meters,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Period is how often the code changes
+func (s *CheckInSettings) Period() time.Duration {
+	return time.Duration(s.PeriodSeconds) * time.Second
+}
+
+// HasGeofence reports whether check-ins must come from near the venue
+func (s *CheckInSettings) HasGeofence() bool {
+	return s.Latitude != nil && s.Longitude != nil && s.RadiusMeters != nil
+}
+
+// CheckIn records a wallet checking in to an event with a QR code
+type CheckIn struct {
+	ID            uint64    `json:"id"`
+	EventID       uint64    `json:"event_id"`
+	WalletAddress string    `json:"wallet_address"`
+	NFTID         *uint64   `json:"nft_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// Claim states
+const (
+	ClaimPending  = "pending"  // waiting for the attendee to give a wallet
+	ClaimClaiming = "claiming" // reserved while the NFT is minted
+	ClaimClaimed  = "claimed"  // minted to the attendee's wallet
+)
+
+// Claim is an NFT parked for an attendee who checked in without a usable
+// wallet address
+type Claim struct {
+	ID            uint64     `json:"id"`
+	EventID       uint64     `json:"event_id"`
+	AttendeeID    string     `json:"attendee_id"`
+	Name          string     `json:"name,omitempty"`
+	Email         string     `json:"email,omitempty"`
+	Status        string     `json:"status"`
+	WalletAddress string     `json:"wallet_address,omitempty"`
+	NFTID         *uint64    `json:"nft_id,omitempty"`
+	ExpiresAt     time.Time  `json:"expires_at"` // when the claim link stops working
+	CreatedAt     time.Time  `json:"created_at"`
+	ClaimedAt     *time.Time `json:"claimed_at,omitempty"`
+}
+
+// Claim modes of an event's claim policy
+const (
+	ClaimModeWebhook   = "webhook"   // badges are only minted on check-in
+	ClaimModeCode      = "code"      // anyone with the secret code can claim
+	ClaimModeAllowlist = "allowlist" // wallets on the event's allowlist can claim
+	ClaimModeOpen      = "open"      // anyone can claim while claims are open
+)
+
+// ClaimPolicy sets how attendees can claim an event's badge directly,
+// without checking in
+type ClaimPolicy struct {
+	Mode string `json:"mode"` // one of the ClaimMode constants
+	// CodeHash is the hash of the secret code in code mode
+	CodeHash  string     `json:"-"`
+	MaxClaims *int       `json:"max_claims,omitempty"` // nil for unlimited
+	OpensAt   *time.Time `json:"opens_at,omitempty"`
+	ClosesAt  *time.Time `json:"closes_at,omitempty"`
+}
+
+// Open reports whether claims are accepted at t
+func (p *ClaimPolicy) Open(t time.Time) bool {
+	if p.Mode == "" || p.Mode == ClaimModeWebhook {
+		return false
+	}
+	if p.OpensAt != nil && t.Before(*p.OpensAt) {
+		return false
+	}
+	return p.ClosesAt == nil || t.Before(*p.ClosesAt)
+}
+
+// DirectClaim is a badge claimed under an event's claim policy
+type DirectClaim struct {
+	ID            uint64    `json:"id"`
+	EventID       uint64    `json:"event_id"`
+	WalletAddress string    `json:"wallet_address"`
+	NFTID         *uint64   `json:"nft_id,omitempty"`
+	IPAddress     string    `json:"-"`
+	CreatedAt     time.Time `json:"created_at"`
+}
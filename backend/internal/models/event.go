@@ -1,10 +1,87 @@
 package models
 
+import (
+	"fmt"
+	"time"
+)
+
+// Event lifecycle states
+const (
+	EventActive    = "active"
+	EventCancelled = "cancelled" // kept and listed, but no more NFTs can be minted
+	EventDeleted   = "deleted"   // soft-deleted because NFTs reference it
+)
+
+// Event types
+const (
+	EventPhysical = "physical"
+	EventOnline   = "online"
+	EventHybrid   = "hybrid"
+)
+
 // Event represents an event in the system
 type Event struct {
-	ID        uint64 `json:"id"`
-	Name      string `json:"name"`
-	Date      string `json:"date"`
-	Location  string `json:"location"`
-	Organizer string `json:"organizer,omitempty"`
-}
\ No newline at end of file
+	ID   uint64 `json:"id"`
+	Name string `json:"name"`
+	// Date is the local start date (YYYY-MM-DD), which is what the chain stores
+	Date          string     `json:"date"`
+	StartsAt      *time.Time `json:"starts_at,omitempty"`
+	EndsAt        *time.Time `json:"ends_at,omitempty"`
+	TimeZone      string     `json:"time_zone,omitempty"` // IANA name, e.g. Europe/Berlin
+	Location      string     `json:"location"`
+	Type          string     `json:"type,omitempty"` // EventPhysical, EventOnline or EventHybrid
+	Description   string     `json:"description,omitempty"`
+	CoverImageURL string     `json:"cover_image_url,omitempty"`
+	LogoURL       string     `json:"logo_url,omitempty"` // organizer logo shown on badges
+	URL           string     `json:"url,omitempty"`
+	Capacity      *int       `json:"capacity,omitempty"` // nil for unlimited
+	Organizer     string     `json:"organizer,omitempty"`
+	Transferable  bool       `json:"transferable"` // false for soulbound events
+	Status        string     `json:"status,omitempty"`
+	Version       int        `json:"version,omitempty"` // incremented on every update
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+	LumaEventID   string     `json:"luma_event_id,omitempty"` // set for events imported from Luma
+	// ClaimPolicy sets how badges can be claimed without checking in
+	ClaimPolicy ClaimPolicy `json:"claim_policy"`
+}
+
+// Mintable reports whether NFTs can still be minted for the event.
+// Events that don't come from the database have no status and are mintable.
+func (e *Event) Mintable() bool {
+	return e.Status == "" || e.Status == EventActive
+}
+
+// NormalizeSchedule fills in the time zone, start and end of the event and
+// sets Date to the start date in the event's time zone. An event with only
+// a date lasts that whole local day, and a missing end defaults to the
+// midnight after the start.
+func (e *Event) NormalizeSchedule() error {
+	if e.TimeZone == "" {
+		e.TimeZone = "UTC"
+	}
+	loc, err := time.LoadLocation(e.TimeZone)
+	if err != nil {
+		return fmt.Errorf("invalid time zone %q", e.TimeZone)
+	}
+
+	if e.StartsAt == nil {
+		day, err := time.ParseInLocation("2006-01-02", e.Date, loc)
+		if err != nil {
+			return fmt.Errorf("invalid date format, use YYYY-MM-DD")
+		}
+		e.StartsAt = &day
+	}
+
+	start := e.StartsAt.In(loc)
+	if e.EndsAt == nil {
+		end := time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, loc)
+		e.EndsAt = &end
+	}
+
+	if e.EndsAt.Before(*e.StartsAt) {
+		return fmt.Errorf("event can't end before it starts")
+	}
+
+	e.Date = start.Format("2006-01-02")
+	return nil
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// EventSource links an event to the event of a ticketing provider whose
+// check-in webhooks mint its NFTs
+type EventSource struct {
+	EventID         uint64 `json:"event_id"`
+	Provider        string `json:"provider"`
+	ExternalEventID string `json:"external_event_id"`
+	// WebhookSecret overrides the provider's configured webhook secret for this event
+	WebhookSecret string `json:"-"`
+	// WalletQuestion overrides the registration question asking for a wallet
+	WalletQuestion string    `json:"wallet_question,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Attribute display types understood by wallets and marketplaces
+const (
+	DisplayDate   = "date"   // value is a Unix timestamp
+	DisplayNumber = "number" // value is shown as a plain number
+)
+
+// TokenMetadata is NFT metadata in the common NFT JSON format
+type TokenMetadata struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Image       string      `json:"image,omitempty"`
+	ExternalURL string      `json:"external_url,omitempty"`
+	Attributes  []Attribute `json:"attributes"`
+}
+
+// Attribute is a trait of an NFT
+type Attribute struct {
+	TraitType   string      `json:"trait_type"`
+	Value       interface{} `json:"value"`
+	DisplayType string      `json:"display_type,omitempty"`
+}
+
+// Attribute returns the value of a trait, or nil if the metadata doesn't have it
+func (m *TokenMetadata) Attribute(traitType string) interface{} {
+	for _, attribute := range m.Attributes {
+		if attribute.TraitType == traitType {
+			return attribute.Value
+		}
+	}
+	return nil
+}
+
+// Map converts the metadata to the map stored with an NFT
+func (m TokenMetadata) Map() map[string]interface{} {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil
+	}
+	return result
+}
+
+// TokenMetadataFromMap reads the metadata stored with an NFT. NFTs minted
+// before metadata followed the standard have their extra fields turned
+// into attributes.
+func TokenMetadataFromMap(stored map[string]interface{}) TokenMetadata {
+	if _, ok := stored["attributes"]; ok {
+		var metadata TokenMetadata
+		if data, err := json.Marshal(stored); err == nil && json.Unmarshal(data, &metadata) == nil {
+			if metadata.Attributes == nil {
+				metadata.Attributes = []Attribute{}
+			}
+			return metadata
+		}
+	}
+
+	metadata := TokenMetadata{Attributes: []Attribute{}}
+	keys := make([]string, 0, len(stored))
+	for key := range stored {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := stored[key]
+		switch key {
+		case "name":
+			metadata.Name = fmt.Sprint(value)
+		case "description":
+			metadata.Description = fmt.Sprint(value)
+		case "image":
+			metadata.Image = fmt.Sprint(value)
+		case "event_url":
+			metadata.ExternalURL = fmt.Sprint(value)
+		default:
+			metadata.Attributes = append(metadata.Attributes, Attribute{TraitType: key, Value: value})
+		}
+	}
+
+	return metadata
+}
@@ -1,24 +1,46 @@
 package models
 
+import "time"
+
 // NFT represents an attendance NFT
 type NFT struct {
 	ID       uint64                 `json:"id"`
 	EventID  uint64                 `json:"event_id"`
 	Owner    string                 `json:"owner"`
 	Metadata map[string]interface{} `json:"metadata"`
+	// MetadataURI is what the chain stores instead of the metadata itself
+	MetadataURI string `json:"metadata_uri,omitempty"`
+	// ChainTokenID is the ID of the NFT's token on chain, or 0 until its
+	// mint is confirmed
+	ChainTokenID uint64     `json:"chain_token_id,omitempty"`
+	Burned       bool       `json:"burned"`
+	BurnedAt     *time.Time `json:"burned_at,omitempty"`
+	BurnReason   string     `json:"burn_reason,omitempty"`
+}
+
+// MintResult is the result of the mint_nft message. NFTID is the token ID
+// the chain assigned; it's 0 when the mint failed or the ID wasn't reported.
+type MintResult struct {
+	Minted bool   `json:"minted"`
+	NFTID  uint64 `json:"nft_id,omitempty"`
 }
 
-// CheckInEvent represents a Luma check-in event webhook payload
+// CheckInEvent is a check-in webhook payload, normalized from the format of
+// the ticketing provider it came from. Luma sends it as is.
 type CheckInEvent struct {
 	EventID    string `json:"event_id"`
 	AttendeeID string `json:"attendee_id"`
 	Timestamp  string `json:"timestamp"`
+	// Attendee and Answers are set by providers whose payload carries the
+	// attendee's details and their answers to registration questions
+	Attendee *Attendee         `json:"-"`
+	Answers  map[string]string `json:"-"`
 }
 
-// Attendee represents a Luma event attendee
+// Attendee represents an attendee of a ticketing provider's event
 type Attendee struct {
 	ID            string `json:"id"`
 	Name          string `json:"name"`
 	Email         string `json:"email"`
 	WalletAddress string `json:"wallet_address"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,359 @@
+// Code generated by contract-bindgen from attendance_nft.json. DO NOT EDIT.
+
+package bindings
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/scale"
+)
+
+// Contract identity from the metadata
+const (
+	ContractName    = "attendance_nft"
+	ContractVersion = "0.1.0"
+)
+
+// Constructor selectors
+var (
+	ConstructorNew = [4]byte{0x9b, 0xae, 0x9d, 0x5e}
+)
+
+// Message selectors
+var (
+	SelectorCreateEvent   = [4]byte{0x80, 0x67, 0xc4, 0x9f}
+	SelectorMintNFT       = [4]byte{0x21, 0x9a, 0x11, 0x3e}
+	SelectorTransfer      = [4]byte{0x84, 0xa1, 0x5d, 0xa1}
+	SelectorBurn          = [4]byte{0xb1, 0xef, 0xc1, 0x7b}
+	SelectorGetNFT        = [4]byte{0x26, 0x44, 0x36, 0x95}
+	SelectorGetEvent      = [4]byte{0x1c, 0x7f, 0x7f, 0xa8}
+	SelectorGetOwnedNFTs  = [4]byte{0x68, 0x3b, 0xdd, 0x8f}
+	SelectorGetEventCount = [4]byte{0xfc, 0x3c, 0x8a, 0xca}
+	SelectorGetNFTCount   = [4]byte{0x33, 0xde, 0x47, 0x30}
+)
+
+// Contract calls the attendance_nft messages through an Executor
+type Contract struct {
+	exec Executor
+}
+
+// NewContract creates bindings that run messages with exec
+func NewContract(exec Executor) *Contract {
+	return &Contract{exec: exec}
+}
+
+// CreateEvent calls the create_event message.
+// Create a new event
+func (c *Contract) CreateEvent(ctx context.Context, name string, date string, location string, transferable bool) (result uint64, err error) {
+	output, err := execute(ctx, c.exec, "create_event", SelectorCreateEvent, true, name, date, location, transferable)
+	if err != nil {
+		return result, err
+	}
+	err = decodeResult(output, &result)
+	return result, err
+}
+
+// MintNFT calls the mint_nft message.
+// Mint a new NFT for an event attendee
+func (c *Contract) MintNFT(ctx context.Context, eventID uint64, recipient AccountID, metadata string) (result bool, err error) {
+	output, err := execute(ctx, c.exec, "mint_nft", SelectorMintNFT, true, eventID, recipient, metadata)
+	if err != nil {
+		return result, err
+	}
+	err = decodeResult(output, &result)
+	return result, err
+}
+
+// Transfer calls the transfer message.
+// Transfer an NFT to a new owner
+func (c *Contract) Transfer(ctx context.Context, nftID uint64, to AccountID) (result bool, err error) {
+	output, err := execute(ctx, c.exec, "transfer", SelectorTransfer, true, nftID, to)
+	if err != nil {
+		return result, err
+	}
+	err = decodeResult(output, &result)
+	return result, err
+}
+
+// Burn calls the burn message.
+// Burn an NFT, e.g. to revoke a fraudulent check-in
+func (c *Contract) Burn(ctx context.Context, nftID uint64) (result bool, err error) {
+	output, err := execute(ctx, c.exec, "burn", SelectorBurn, true, nftID)
+	if err != nil {
+		return result, err
+	}
+	err = decodeResult(output, &result)
+	return result, err
+}
+
+// GetNFT calls the get_nft message.
+// Get NFT by ID
+func (c *Contract) GetNFT(ctx context.Context, nftID uint64) (*NFT, error) {
+	output, err := execute(ctx, c.exec, "get_nft", SelectorGetNFT, false, nftID)
+	if err != nil {
+		return nil, err
+	}
+	var result NFT
+	found, err := decodeOptionResult(output, &result)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetEvent calls the get_event message.
+// Get event by ID
+func (c *Contract) GetEvent(ctx context.Context, eventID uint64) (*EventInfo, error) {
+	output, err := execute(ctx, c.exec, "get_event", SelectorGetEvent, false, eventID)
+	if err != nil {
+		return nil, err
+	}
+	var result EventInfo
+	found, err := decodeOptionResult(output, &result)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetOwnedNFTs calls the get_owned_nfts message.
+// Get all NFTs owned by an account
+func (c *Contract) GetOwnedNFTs(ctx context.Context, owner AccountID) (result []uint64, err error) {
+	output, err := execute(ctx, c.exec, "get_owned_nfts", SelectorGetOwnedNFTs, false, owner)
+	if err != nil {
+		return result, err
+	}
+	err = decodeResult(output, &result)
+	return result, err
+}
+
+// GetEventCount calls the get_event_count message.
+// Get total number of events
+func (c *Contract) GetEventCount(ctx context.Context) (result uint64, err error) {
+	output, err := execute(ctx, c.exec, "get_event_count", SelectorGetEventCount, false)
+	if err != nil {
+		return result, err
+	}
+	err = decodeResult(output, &result)
+	return result, err
+}
+
+// GetNFTCount calls the get_nft_count message.
+// Get total number of NFTs
+func (c *Contract) GetNFTCount(ctx context.Context) (result uint64, err error) {
+	output, err := execute(ctx, c.exec, "get_nft_count", SelectorGetNFTCount, false)
+	if err != nil {
+		return result, err
+	}
+	err = decodeResult(output, &result)
+	return result, err
+}
+
+// EventCreated is emitted by the contract.
+type EventCreated struct {
+	EventID   uint64
+	Organizer AccountID
+}
+
+// Decode implements scale.Decodeable
+func (v *EventCreated) Decode(decoder scale.Decoder) error {
+	if err := decoder.Decode(&v.EventID); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&v.Organizer); err != nil {
+		return err
+	}
+	return nil
+}
+
+// NFTMinted is emitted by the contract.
+type NFTMinted struct {
+	NFTID     uint64
+	Recipient AccountID
+	EventID   uint64
+}
+
+// Decode implements scale.Decodeable
+func (v *NFTMinted) Decode(decoder scale.Decoder) error {
+	if err := decoder.Decode(&v.NFTID); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&v.Recipient); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&v.EventID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// NFTTransferred is emitted by the contract.
+type NFTTransferred struct {
+	NFTID uint64
+	From  AccountID
+	To    AccountID
+}
+
+// Decode implements scale.Decodeable
+func (v *NFTTransferred) Decode(decoder scale.Decoder) error {
+	if err := decoder.Decode(&v.NFTID); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&v.From); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&v.To); err != nil {
+		return err
+	}
+	return nil
+}
+
+// NFTBurned is emitted by the contract.
+type NFTBurned struct {
+	NFTID uint64
+	Owner AccountID
+}
+
+// Decode implements scale.Decodeable
+func (v *NFTBurned) Decode(decoder scale.Decoder) error {
+	if err := decoder.Decode(&v.NFTID); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&v.Owner); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Event signature topics
+var (
+	TopicEventCreated   = [32]byte{0xa8, 0x07, 0xbc, 0xa1, 0x1d, 0x59, 0x84, 0xb9, 0xb3, 0xa8, 0x5b, 0xaa, 0xa4, 0x3a, 0xc4, 0x20, 0xe1, 0x88, 0x5b, 0xbb, 0x90, 0x76, 0x69, 0x94, 0x0c, 0xcb, 0x81, 0x18, 0x09, 0xbb, 0x53, 0x03}
+	TopicNFTMinted      = [32]byte{0xc4, 0xa5, 0xb1, 0xe1, 0x3a, 0x76, 0x3b, 0xb8, 0x27, 0xa4, 0xe3, 0xcc, 0xc2, 0xbc, 0x80, 0x43, 0xff, 0x87, 0x6b, 0xb8, 0x71, 0x12, 0xf2, 0x71, 0x5f, 0xb8, 0x0a, 0xee, 0x69, 0x52, 0x6d, 0xc2}
+	TopicNFTTransferred = [32]byte{0x34, 0xab, 0xdb, 0x87, 0xe4, 0x8f, 0x7d, 0x36, 0x22, 0xc8, 0x6b, 0xcc, 0xdf, 0x90, 0xc0, 0x7b, 0xf3, 0xf1, 0x82, 0xa9, 0x94, 0x4d, 0x5b, 0xea, 0xc1, 0x3c, 0x88, 0xfd, 0xd6, 0xcf, 0xef, 0xe5}
+	TopicNFTBurned      = [32]byte{0xf5, 0xbd, 0x99, 0x31, 0x3e, 0xb1, 0xfc, 0x7a, 0xa9, 0xe7, 0x67, 0xd3, 0xd0, 0xff, 0x44, 0x4a, 0x9a, 0xe7, 0x7a, 0xbe, 0x66, 0xd8, 0xd0, 0xc5, 0x02, 0x94, 0xf4, 0xd5, 0xed, 0x15, 0xc6, 0x0d}
+)
+
+// DecodeEvent decodes a contract event from its signature topic and data
+func DecodeEvent(topic [32]byte, data []byte) (interface{}, error) {
+	decoder := scale.NewDecoder(bytes.NewReader(data))
+	switch topic {
+	case TopicEventCreated:
+		var event EventCreated
+		if err := decoder.Decode(&event); err != nil {
+			return nil, fmt.Errorf("failed to decode EventCreated: %v", err)
+		}
+		return &event, nil
+	case TopicNFTMinted:
+		var event NFTMinted
+		if err := decoder.Decode(&event); err != nil {
+			return nil, fmt.Errorf("failed to decode NFTMinted: %v", err)
+		}
+		return &event, nil
+	case TopicNFTTransferred:
+		var event NFTTransferred
+		if err := decoder.Decode(&event); err != nil {
+			return nil, fmt.Errorf("failed to decode NFTTransferred: %v", err)
+		}
+		return &event, nil
+	case TopicNFTBurned:
+		var event NFTBurned
+		if err := decoder.Decode(&event); err != nil {
+			return nil, fmt.Errorf("failed to decode NFTBurned: %v", err)
+		}
+		return &event, nil
+	}
+	return nil, fmt.Errorf("unknown event topic %#x", topic)
+}
+
+// EventInfo mirrors attendance_nft::attendance_nft::EventInfo
+type EventInfo struct {
+	Name         string
+	Date         string
+	Location     string
+	Organizer    AccountID
+	Transferable bool
+}
+
+// Encode implements scale.Encodeable
+func (v EventInfo) Encode(encoder scale.Encoder) error {
+	if err := encoder.Encode(v.Name); err != nil {
+		return err
+	}
+	if err := encoder.Encode(v.Date); err != nil {
+		return err
+	}
+	if err := encoder.Encode(v.Location); err != nil {
+		return err
+	}
+	if err := encoder.Encode(v.Organizer); err != nil {
+		return err
+	}
+	if err := encoder.Encode(v.Transferable); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Decode implements scale.Decodeable
+func (v *EventInfo) Decode(decoder scale.Decoder) error {
+	if err := decoder.Decode(&v.Name); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&v.Date); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&v.Location); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&v.Organizer); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&v.Transferable); err != nil {
+		return err
+	}
+	return nil
+}
+
+// NFT mirrors attendance_nft::attendance_nft::Nft
+type NFT struct {
+	ID       uint64
+	EventID  uint64
+	Owner    AccountID
+	Metadata string
+}
+
+// Encode implements scale.Encodeable
+func (v NFT) Encode(encoder scale.Encoder) error {
+	if err := encoder.Encode(v.ID); err != nil {
+		return err
+	}
+	if err := encoder.Encode(v.EventID); err != nil {
+		return err
+	}
+	if err := encoder.Encode(v.Owner); err != nil {
+		return err
+	}
+	if err := encoder.Encode(v.Metadata); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Decode implements scale.Decodeable
+func (v *NFT) Decode(decoder scale.Decoder) error {
+	if err := decoder.Decode(&v.ID); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&v.EventID); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&v.Owner); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&v.Metadata); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Package bindings holds typed Go bindings for the attendance NFT contract.
+// attendance_nft.go is generated from the contract metadata in
+// attendance_nft.json; run `go generate` after changing the contract.
+package bindings
+
+//go:generate go run ../../../cmd/contract-bindgen -metadata attendance_nft.json -out attendance_nft.go -package bindings
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/scale"
+)
+
+// AccountID is an ink! AccountId
+type AccountID [32]byte
+
+// Message is an encoded contract message
+type Message struct {
+	Label    string
+	Selector [4]byte
+	Input    []byte // selector followed by the SCALE-encoded arguments
+	Mutates  bool
+}
+
+// Executor runs contract messages and returns their SCALE-encoded output.
+// Mutating messages must be submitted as extrinsics; a cancelled context
+// stops waiting for them.
+type Executor interface {
+	Exec(ctx context.Context, msg Message) ([]byte, error)
+}
+
+// ErrCouldNotReadInput is returned when the contract can't decode a message's input
+var ErrCouldNotReadInput = errors.New("contract could not read the message input")
+
+// execute encodes a message with its arguments and runs it
+func execute(ctx context.Context, exec Executor, label string, selector [4]byte, mutates bool, args ...interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(selector[:])
+
+	encoder := scale.NewEncoder(&buf)
+	for i, arg := range args {
+		if err := encoder.Encode(arg); err != nil {
+			return nil, fmt.Errorf("failed to encode argument %d of %s: %v", i, label, err)
+		}
+	}
+
+	return exec.Exec(ctx, Message{Label: label, Selector: selector, Input: buf.Bytes(), Mutates: mutates})
+}
+
+// decodeResult decodes a MessageResult, i.e. Result<T, LangError>.
+// target may be nil for messages returning ().
+func decodeResult(output []byte, target interface{}) error {
+	decoder, err := unwrapResult(output)
+	if err != nil {
+		return err
+	}
+
+	if target == nil {
+		return nil
+	}
+
+	if err := decoder.Decode(target); err != nil {
+		return fmt.Errorf("failed to decode message output: %v", err)
+	}
+	return nil
+}
+
+// decodeOptionResult decodes a MessageResult holding an Option<T>.
+// It reports false for None.
+func decodeOptionResult(output []byte, target interface{}) (bool, error) {
+	decoder, err := unwrapResult(output)
+	if err != nil {
+		return false, err
+	}
+
+	var found bool
+	if err := decoder.DecodeOption(&found, target); err != nil {
+		return false, fmt.Errorf("failed to decode message output: %v", err)
+	}
+	return found, nil
+}
+
+// unwrapResult reads the Ok/Err tag of a MessageResult
+func unwrapResult(output []byte) (*scale.Decoder, error) {
+	if len(output) == 0 {
+		return nil, fmt.Errorf("empty message output")
+	}
+
+	switch output[0] {
+	case 0:
+		return scale.NewDecoder(bytes.NewReader(output[1:])), nil
+	case 1:
+		return nil, ErrCouldNotReadInput
+	default:
+		return nil, fmt.Errorf("invalid message result tag %d", output[0])
+	}
+}
@@ -0,0 +1,176 @@
+package polkadot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types/codec"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// AttendanceChain is the chain backend used by the API and minting code.
+// Client implements it; NewSimulatedClient gives an isolated in-memory instance.
+// Cancelling ctx abandons a call; an extrinsic that was already submitted
+// may still be included.
+type AttendanceChain interface {
+	// CreateEvent registers an event on chain and returns its chain ID
+	CreateEvent(ctx context.Context, eventID uint64, name, date, location string, transferable bool) (uint64, error)
+	// GetEvent reads an event, returning nil if it doesn't exist
+	GetEvent(ctx context.Context, id uint64) (*models.Event, error)
+	// MintNFT mints an attendance NFT for a recipient and returns the ID of
+	// its token on chain, or 0 if the chain didn't report one. The chain
+	// stores metadataURI, which points at the NFT's metadata.
+	MintNFT(ctx context.Context, eventID, nftID uint64, recipient, metadataURI string) (uint64, bool, error)
+	// TransferNFT moves the token with the given chain ID to a new owner
+	TransferNFT(ctx context.Context, eventID, tokenID uint64, to string) (bool, error)
+	// BurnNFT revokes the token with the given chain ID from its owner
+	BurnNFT(ctx context.Context, eventID, tokenID uint64) (bool, error)
+	// ListNFTsByOwner enumerates the IDs of the NFTs held by an account
+	ListNFTsByOwner(ctx context.Context, owner string) ([]uint64, error)
+	// TxStatus reports the status of an extrinsic submitted by this backend
+	TxStatus(ctx context.Context, txHash string) (*TxStatus, error)
+}
+
+// TxState is the lifecycle state of a submitted extrinsic
+type TxState string
+
+// Extrinsic states
+const (
+	TxUnknown   TxState = "unknown"
+	TxSubmitted TxState = "submitted"
+	TxInBlock   TxState = "in_block"
+	TxFailed    TxState = "failed"
+)
+
+// TxStatus describes a submitted extrinsic
+type TxStatus struct {
+	Hash      string    `json:"hash"`
+	Call      string    `json:"call,omitempty"`
+	State     TxState   `json:"state"`
+	BlockHash string    `json:"block_hash,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// errSubmitFailed is returned when the node rejects an extrinsic before it's watched
+var errSubmitFailed = errors.New("failed to submit extrinsic")
+
+// maxTrackedTxs caps how many extrinsics a tracker remembers
+const maxTrackedTxs = 1000
+
+// txTracker remembers the status of recently submitted extrinsics
+type txTracker struct {
+	txs   map[string]*TxStatus
+	order []string
+	mutex sync.Mutex
+}
+
+// newTxTracker creates an empty tracker
+func newTxTracker() *txTracker {
+	return &txTracker{txs: make(map[string]*TxStatus)}
+}
+
+// update records the latest state of an extrinsic
+func (t *txTracker) update(txHash, call string, state TxState, blockHash, errMsg string) {
+	if t == nil {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	status, exists := t.txs[txHash]
+	if !exists {
+		// Forget the oldest extrinsic once the tracker is full
+		if len(t.order) >= maxTrackedTxs {
+			delete(t.txs, t.order[0])
+			t.order = t.order[1:]
+		}
+		status = &TxStatus{Hash: txHash, Call: call}
+		t.txs[txHash] = status
+		t.order = append(t.order, txHash)
+	}
+
+	status.State = state
+	status.BlockHash = blockHash
+	status.Error = errMsg
+	status.UpdatedAt = time.Now()
+}
+
+// get returns a copy of an extrinsic's status
+func (t *txTracker) get(txHash string) (*TxStatus, bool) {
+	if t == nil {
+		return nil, false
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	status, exists := t.txs[strings.ToLower(txHash)]
+	if !exists {
+		return nil, false
+	}
+
+	copied := *status
+	return &copied, true
+}
+
+// extrinsicHash computes the blake2b-256 hash an extrinsic is identified by on chain
+func extrinsicHash(ext types.Extrinsic) (string, error) {
+	encoded, err := codec.Encode(ext)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode extrinsic: %v", err)
+	}
+
+	return fmt.Sprintf("%#x", blake2b256(encoded)), nil
+}
+
+// submitAndWatch submits a signed extrinsic and waits until it's included in a block,
+// recording its progress in the tracker. It returns the hash of that block.
+// If ctx is cancelled first it stops watching; the extrinsic stays submitted
+// and its tracked state is left as is.
+func submitAndWatch(ctx context.Context, api *gsrpc.SubstrateAPI, ext types.Extrinsic, call string, txs *txTracker) (types.Hash, error) {
+	txHash, err := extrinsicHash(ext)
+	if err != nil {
+		return types.Hash{}, err
+	}
+
+	sub, err := api.RPC.Author.SubmitAndWatchExtrinsic(ext)
+	if err != nil {
+		txs.update(txHash, call, TxFailed, "", err.Error())
+		return types.Hash{}, fmt.Errorf("%w: %v", errSubmitFailed, err)
+	}
+	defer sub.Unsubscribe()
+
+	log.Printf("Submitted extrinsic %s (%s)", txHash, call)
+	txs.update(txHash, call, TxSubmitted, "", "")
+
+	for {
+		var status types.ExtrinsicStatus
+		select {
+		case status = <-sub.Chan():
+		case err := <-sub.Err():
+			txs.update(txHash, call, TxFailed, "", err.Error())
+			return types.Hash{}, fmt.Errorf("extrinsic subscription failed: %v", err)
+		case <-ctx.Done():
+			return types.Hash{}, fmt.Errorf("stopped watching extrinsic %s: %w", txHash, ctx.Err())
+		}
+
+		if status.IsInBlock {
+			log.Printf("Extrinsic included in block: %#x", status.AsInBlock)
+			txs.update(txHash, call, TxInBlock, fmt.Sprintf("%#x", status.AsInBlock), "")
+			return status.AsInBlock, nil
+		}
+		if status.IsDropped || status.IsInvalid || status.IsUsurped {
+			txs.update(txHash, call, TxFailed, "", fmt.Sprintf("%v", status))
+			return types.Hash{}, fmt.Errorf("extrinsic failed: %v", status)
+		}
+	}
+}
@@ -1,16 +1,17 @@
 package polkadot
 
 import (
-	"encoding/hex"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"strings"
 
 	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
 	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
-	"github.com/vedhavyas/go-subkey/v2" // For Substrate address handling
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
 	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/simulator"
 )
 
 // Client handles interactions with the Polkadot blockchain
@@ -20,6 +21,29 @@ type Client struct {
 	contractCaller ContractCaller
 	chainName      string
 	useMock        bool
+	txs            *txTracker
+
+	// Events can be minted with pallet-nfts instead of the contract
+	defaultBackend string
+	eventBackends  map[uint64]string
+	nfts           *NftsBackend
+	chainEvents    ChainEventStore
+}
+
+// BackendConfig selects the chain backend used for each event
+type BackendConfig struct {
+	Default       string            // BackendContract or BackendNfts
+	EventBackends map[uint64]string // per-event overrides
+	Nfts          *NftsBackend      // required when any event uses BackendNfts
+	// ChainEvents records the ID the contract assigns to each event. Without
+	// it events are assumed to have the same ID on the contract.
+	ChainEvents ChainEventStore
+}
+
+// ChainEventStore persists the ID the attendance contract assigned to each event
+type ChainEventStore interface {
+	GetChainEventID(ctx context.Context, eventID uint64) (uint64, bool, error)
+	SetChainEventID(ctx context.Context, eventID, chainEventID uint64) error
 }
 
 // NewClient creates a new Polkadot client
@@ -32,9 +56,10 @@ func NewClient(rpcURL, contractAddress string) *Client {
 		log.Printf("Using mock implementation for development")
 		// Create a mock API for development
 		return &Client{
-			contractCaller: NewMockContractCaller(),
+			contractCaller: NewLegacyAdapter(sharedSimulator()),
 			useMock:        true,
 			chainName:      "Mock",
+			txs:            newTxTracker(),
 		}
 	}
 
@@ -62,64 +87,34 @@ func NewClient(rpcURL, contractAddress string) *Client {
 	// Parse contract address if provided
 	var contractAddr types.AccountID
 	if contractAddress != "" {
-		// Try to convert from Substrate format (like '5E34Vf...') to AccountID
-		if len(contractAddress) > 0 && (contractAddress[0] == '5' || strings.HasPrefix(contractAddress, "0x")) {
-			log.Printf("Processing address: %s", contractAddress)
-			
-			var addrBytes []byte
-			var err error
-			
-			// Handle different address formats
-			if strings.HasPrefix(contractAddress, "0x") {
-				// Handle hex format
-				hexStr := strings.TrimPrefix(contractAddress, "0x")
-				addrBytes, err = hex.DecodeString(hexStr)
-				if err != nil {
-					log.Printf("Invalid hex address: %v", err)
-				}
-			} else {
-				// Try as Substrate SS58 address
-				_, pubKey, err := subkey.SS58Decode(contractAddress)
-				if err != nil {
-					log.Printf("Invalid SS58 address: %v", err)
-				} else {
-					addrBytes = pubKey
-				}
-			}
-			
-			// If we successfully decoded the address
-			if len(addrBytes) == 32 { // AccountID is 32 bytes
-				copy(contractAddr[:], addrBytes)
-				log.Printf("Successfully converted address to AccountID")
-			} else {
-				log.Printf("Address conversion failed, falling back to mock implementation")
-				return &Client{
-					api:            api,
-					contractCaller: NewMockContractCaller(),
-					useMock:        true,
-					chainName:      chainName,
-				}
-			}
-		} else {
-			log.Printf("Unrecognized address format: %s", contractAddress)
-			log.Printf("Using mock implementation for development")
+		log.Printf("Processing address: %s", contractAddress)
+
+		// Accept both SS58 and canonical hex addresses
+		accountID, _, err := address.Parse(contractAddress)
+		if err != nil {
+			log.Printf("Address conversion failed (%v), falling back to mock implementation", err)
 			return &Client{
 				api:            api,
-				contractCaller: NewMockContractCaller(),
+				contractCaller: NewLegacyAdapter(sharedSimulator()),
 				useMock:        true,
 				chainName:      chainName,
+				txs:            newTxTracker(),
 			}
 		}
+
+		copy(contractAddr[:], accountID[:])
+		log.Printf("Successfully converted address to AccountID")
 	} else {
 		log.Printf("No contract address provided, using mock implementation")
 	}
 
 	// Create contract caller
-	caller := NewContractCaller(api, contractAddr)
+	txs := newTxTracker()
+	caller := NewContractCaller(api, contractAddr, txs)
 
 	// Check if we got a real or mock caller
 	useMock := false
-	if _, isMock := caller.(*MockContractCaller); isMock {
+	if isMockCaller(caller) {
 		useMock = true
 		log.Printf("Using mock contract implementation")
 	} else {
@@ -132,40 +127,142 @@ func NewClient(rpcURL, contractAddress string) *Client {
 		contractCaller: caller,
 		useMock:        useMock,
 		chainName:      chainName,
+		txs:            txs,
+	}
+}
+
+// NewSimulatedClient creates a client backed by a chain simulator,
+// independent of every other client
+func NewSimulatedClient(sim *simulator.Simulator) *Client {
+	return &Client{
+		contractCaller: NewLegacyAdapter(sim),
+		useMock:        true,
+		chainName:      "Mock",
+		txs:            newTxTracker(),
+	}
+}
+
+// Client is the default AttendanceChain implementation
+var _ AttendanceChain = (*Client)(nil)
+
+// ConfigureBackends sets which backend each event is created and minted on
+func (c *Client) ConfigureBackends(cfg BackendConfig) error {
+	if cfg.Default == "" {
+		cfg.Default = BackendContract
+	}
+
+	backends := map[string]bool{cfg.Default: true}
+	for _, backend := range cfg.EventBackends {
+		backends[backend] = true
+	}
+
+	for backend := range backends {
+		switch backend {
+		case BackendContract:
+		case BackendNfts:
+			if cfg.Nfts == nil {
+				return fmt.Errorf("backend %q is selected but not configured", backend)
+			}
+		default:
+			return fmt.Errorf("unknown chain backend %q", backend)
+		}
+	}
+
+	c.defaultBackend = cfg.Default
+	c.eventBackends = cfg.EventBackends
+	c.nfts = cfg.Nfts
+	c.chainEvents = cfg.ChainEvents
+	if c.nfts != nil {
+		c.nfts.txs = c.txs
+	}
+	return nil
+}
+
+// nftsFor returns the pallet-nfts backend if the event uses it, or nil for the contract
+func (c *Client) nftsFor(eventID uint64) *NftsBackend {
+	backend := c.defaultBackend
+	if override, exists := c.eventBackends[eventID]; exists {
+		backend = override
+	}
+
+	if backend == BackendNfts {
+		return c.nfts
+	}
+	return nil
+}
+
+// contractEventID returns the ID the contract assigned to an event
+func (c *Client) contractEventID(ctx context.Context, eventID uint64) (uint64, error) {
+	if c.chainEvents == nil {
+		return eventID, nil
+	}
+
+	chainEventID, found, err := c.chainEvents.GetChainEventID(ctx, eventID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get chain ID of event %d: %v", eventID, err)
+	}
+	if !found {
+		return 0, fmt.Errorf("event %d isn't registered on the contract", eventID)
 	}
+	return chainEventID, nil
 }
 
-// CreateEvent creates a new event in the smart contract
-func (c *Client) CreateEvent(name, date, location string) (uint64, error) {
-	log.Printf("Creating event: %s, %s, %s", name, date, location)
+// CreateEvent creates a new event on the event's chain backend
+func (c *Client) CreateEvent(ctx context.Context, eventID uint64, name, date, location string, transferable bool) (uint64, error) {
+	log.Printf("Creating event: %s, %s, %s (transferable: %t)", name, date, location, transferable)
 	
 	// Input validation
 	if name == "" || date == "" || location == "" {
 		return 0, fmt.Errorf("name, date, and location are required")
 	}
+
+	if nfts := c.nftsFor(eventID); nfts != nil {
+		return nfts.CreateEvent(ctx, eventID, name, date, location, transferable)
+	}
 	
 	// Call the smart contract
-	result, err := c.contractCaller.Call("create_event", name, date, location)
+	result, err := c.contractCaller.Call(ctx, "create_event", name, date, location, transferable)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create event: %v", err)
 	}
 
 	// Parse result
-	var eventID uint64
-	if err := json.Unmarshal(result, &eventID); err != nil {
+	var createdID uint64
+	if err := json.Unmarshal(result, &createdID); err != nil {
 		return 0, fmt.Errorf("failed to parse event ID: %v", err)
 	}
 
-	log.Printf("Event created with ID: %d", eventID)
-	return eventID, nil
+	log.Printf("Event created with ID: %d", createdID)
+
+	// Later calls for the event address it by the contract's ID
+	if c.chainEvents != nil && eventID != 0 {
+		if err := c.chainEvents.SetChainEventID(ctx, eventID, createdID); err != nil {
+			return 0, fmt.Errorf("failed to store chain ID of event %d: %v", eventID, err)
+		}
+	}
+
+	return createdID, nil
 }
 
 // GetEvent gets an event by ID
-func (c *Client) GetEvent(id uint64) (*models.Event, error) {
+func (c *Client) GetEvent(ctx context.Context, id uint64) (*models.Event, error) {
 	log.Printf("Getting event with ID: %d", id)
-	
+
+	if nfts := c.nftsFor(id); nfts != nil {
+		return nfts.GetEvent(ctx, id)
+	}
+
+	chainEventID, err := c.contractEventID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return c.contractEvent(ctx, chainEventID)
+}
+
+// contractEvent reads an event from the contract by its chain ID
+func (c *Client) contractEvent(ctx context.Context, id uint64) (*models.Event, error) {
 	// Call the smart contract
-	result, err := c.contractCaller.Call("get_event", id)
+	result, err := c.contractCaller.Call(ctx, "get_event", id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get event: %v", err)
 	}
@@ -186,12 +283,12 @@ func (c *Client) GetEvent(id uint64) (*models.Event, error) {
 	return &event, nil
 }
 
-// ListEvents lists all events
-func (c *Client) ListEvents() ([]models.Event, error) {
+// ListEvents lists all events created in the contract
+func (c *Client) ListEvents(ctx context.Context) ([]models.Event, error) {
 	log.Printf("Listing all events")
 	
 	// Get total event count
-	countResult, err := c.contractCaller.Call("get_event_count")
+	countResult, err := c.contractCaller.Call(ctx, "get_event_count")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get event count: %v", err)
 	}
@@ -204,8 +301,11 @@ func (c *Client) ListEvents() ([]models.Event, error) {
 	log.Printf("Found %d events", count)
 	events := make([]models.Event, 0, count)
 	for i := uint64(1); i <= count; i++ {
-		event, err := c.GetEvent(i)
+		event, err := c.contractEvent(ctx, i)
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
 			log.Printf("Failed to get event %d: %v", i, err)
 			continue
 		}
@@ -217,53 +317,236 @@ func (c *Client) ListEvents() ([]models.Event, error) {
 	return events, nil
 }
 
-// MintNFT mints a new NFT for an attendee
-func (c *Client) MintNFT(eventID uint64, recipient string, metadata map[string]interface{}) (bool, error) {
+// MintNFT mints a new NFT for an attendee and returns the ID of its token on chain.
+// nftID is used as the item ID on pallet-nfts; the contract assigns its own.
+// The token ID is 0 if the mint went through but the chain didn't report it.
+func (c *Client) MintNFT(ctx context.Context, eventID, nftID uint64, recipient, metadataURI string) (uint64, bool, error) {
 	log.Printf("Minting NFT for event %d to recipient %s", eventID, recipient)
 	
 	// Validate recipient address
 	if recipient == "" {
-		return false, fmt.Errorf("recipient address is required")
+		return 0, false, fmt.Errorf("recipient address is required")
 	}
 	
 	// Validate event ID
 	if eventID == 0 {
-		return false, fmt.Errorf("invalid event ID")
+		return 0, false, fmt.Errorf("invalid event ID")
 	}
-	
-	// Convert metadata to JSON string
-	metadataJSON, err := json.Marshal(metadata)
+
+	if nfts := c.nftsFor(eventID); nfts != nil {
+		return nfts.MintNFT(ctx, eventID, nftID, recipient, metadataURI)
+	}
+
+	chainEventID, err := c.contractEventID(ctx, eventID)
 	if err != nil {
-		return false, fmt.Errorf("failed to marshal metadata: %v", err)
+		return 0, false, err
 	}
 
 	// Call the smart contract
-	result, err := c.contractCaller.Call("mint_nft", eventID, recipient, string(metadataJSON))
+	result, err := c.contractCaller.Call(ctx, "mint_nft", chainEventID, recipient, metadataURI)
 	if err != nil {
-		return false, fmt.Errorf("failed to mint NFT: %v", err)
+		return 0, false, fmt.Errorf("failed to mint NFT: %v", err)
 	}
 
 	// Parse result
-	var success bool
-	if err := json.Unmarshal(result, &success); err != nil {
-		return false, fmt.Errorf("failed to parse result: %v", err)
+	var minted models.MintResult
+	if err := json.Unmarshal(result, &minted); err != nil {
+		return 0, false, fmt.Errorf("failed to parse result: %v", err)
 	}
 
-	if success {
-		log.Printf("NFT minted successfully")
+	if minted.Minted {
+		log.Printf("NFT minted successfully as token %d", minted.NFTID)
 	} else {
 		log.Printf("NFT minting failed")
 	}
 	
+	return minted.NFTID, minted.Minted, nil
+}
+
+// ErrNonTransferable is returned when transferring an NFT of a soulbound event
+var ErrNonTransferable = errors.New("NFT belongs to a non-transferable (soulbound) event")
+
+// TransferNFT transfers an NFT of an event to a new owner.
+// Transfers of soulbound events fail with ErrNonTransferable.
+func (c *Client) TransferNFT(ctx context.Context, eventID, nftID uint64, to string) (bool, error) {
+	log.Printf("Transferring NFT %d of event %d to %s", nftID, eventID, to)
+
+	// Validate input
+	if nftID == 0 {
+		return false, fmt.Errorf("invalid NFT ID")
+	}
+	if to == "" {
+		return false, fmt.Errorf("recipient address is required")
+	}
+
+	// Check the event's transfer policy on chain before submitting anything
+	event, err := c.GetEvent(ctx, eventID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check transfer policy: %w", err)
+	}
+	if event != nil && !event.Transferable {
+		return false, ErrNonTransferable
+	}
+
+	if nfts := c.nftsFor(eventID); nfts != nil {
+		return nfts.TransferNFT(ctx, eventID, nftID, to)
+	}
+
+	// Call the smart contract (PSP34 transfer takes to, id, data)
+	result, err := c.contractCaller.Call(ctx, MethodTransfer, to, nftID, []byte{})
+	if err != nil {
+		return false, fmt.Errorf("failed to transfer NFT: %v", err)
+	}
+
+	// Parse result
+	var success bool
+	if err := json.Unmarshal(result, &success); err != nil {
+		return false, fmt.Errorf("failed to parse result: %v", err)
+	}
+
+	return success, nil
+}
+
+// OwnerOf returns the current owner of an NFT, or "" if it doesn't exist
+func (c *Client) OwnerOf(ctx context.Context, eventID, nftID uint64) (string, error) {
+	if nfts := c.nftsFor(eventID); nfts != nil {
+		return nfts.OwnerOf(ctx, eventID, nftID)
+	}
+
+	result, err := c.contractCaller.Call(ctx, MethodOwnerOf, nftID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get NFT owner: %v", err)
+	}
+
+	var owner *string
+	if len(result) > 0 {
+		if err := json.Unmarshal(result, &owner); err != nil {
+			return "", fmt.Errorf("failed to parse NFT owner: %v", err)
+		}
+	}
+
+	if owner == nil {
+		return "", nil
+	}
+	return *owner, nil
+}
+
+// BalanceOf returns the number of contract NFTs held by an account
+func (c *Client) BalanceOf(ctx context.Context, owner string) (uint64, error) {
+	result, err := c.contractCaller.Call(ctx, MethodBalanceOf, owner)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get NFT balance: %v", err)
+	}
+
+	var balance uint64
+	if err := json.Unmarshal(result, &balance); err != nil {
+		return 0, fmt.Errorf("failed to parse NFT balance: %v", err)
+	}
+
+	return balance, nil
+}
+
+// ListNFTsByOwner enumerates the IDs of the contract NFTs held by an account
+func (c *Client) ListNFTsByOwner(ctx context.Context, owner string) ([]uint64, error) {
+	balance, err := c.BalanceOf(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint64, 0, balance)
+	for i := uint64(0); i < balance; i++ {
+		result, err := c.contractCaller.Call(ctx, MethodOwnersTokenByIndex, owner, i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get owned NFT %d: %v", i, err)
+		}
+
+		var id *uint64
+		if err := json.Unmarshal(result, &id); err != nil {
+			return nil, fmt.Errorf("failed to parse owned NFT %d: %v", i, err)
+		}
+		if id == nil {
+			break
+		}
+
+		ids = append(ids, *id)
+	}
+
+	return ids, nil
+}
+
+// GetNFTAttribute reads a metadata attribute of an NFT.
+// The second return value is false if the NFT or attribute doesn't exist.
+func (c *Client) GetNFTAttribute(ctx context.Context, eventID, nftID uint64, key string) (string, bool, error) {
+	if nfts := c.nftsFor(eventID); nfts != nil {
+		return nfts.GetNFTAttribute(ctx, eventID, nftID, key)
+	}
+
+	result, err := c.contractCaller.Call(ctx, MethodGetAttribute, nftID, key)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get NFT attribute: %v", err)
+	}
+
+	var value *string
+	if len(result) > 0 {
+		if err := json.Unmarshal(result, &value); err != nil {
+			return "", false, fmt.Errorf("failed to parse NFT attribute: %v", err)
+		}
+	}
+
+	if value == nil {
+		return "", false, nil
+	}
+	return *value, true, nil
+}
+
+// TxStatus reports the status of an extrinsic submitted by this client.
+// Extrinsics it doesn't know about are reported as TxUnknown.
+func (c *Client) TxStatus(ctx context.Context, txHash string) (*TxStatus, error) {
+	if txHash == "" {
+		return nil, fmt.Errorf("transaction hash is required")
+	}
+
+	if status, found := c.txs.get(txHash); found {
+		return status, nil
+	}
+
+	return &TxStatus{Hash: txHash, State: TxUnknown}, nil
+}
+
+// BurnNFT burns an NFT, revoking it from its owner
+func (c *Client) BurnNFT(ctx context.Context, eventID, nftID uint64) (bool, error) {
+	log.Printf("Burning NFT %d of event %d", nftID, eventID)
+
+	// Validate NFT ID
+	if nftID == 0 {
+		return false, fmt.Errorf("invalid NFT ID")
+	}
+
+	if nfts := c.nftsFor(eventID); nfts != nil {
+		return nfts.BurnNFT(ctx, eventID, nftID)
+	}
+
+	// Call the smart contract
+	result, err := c.contractCaller.Call(ctx, "burn", nftID)
+	if err != nil {
+		return false, fmt.Errorf("failed to burn NFT: %v", err)
+	}
+
+	// Parse result
+	var success bool
+	if err := json.Unmarshal(result, &success); err != nil {
+		return false, fmt.Errorf("failed to parse result: %v", err)
+	}
+
 	return success, nil
 }
 
-// ListNFTs lists all NFTs
-func (c *Client) ListNFTs() ([]models.NFT, error) {
+// ListNFTs lists all NFTs minted by the contract
+func (c *Client) ListNFTs(ctx context.Context) ([]models.NFT, error) {
 	log.Printf("Listing all NFTs")
 	
 	// Get total NFT count
-	countResult, err := c.contractCaller.Call("get_nft_count")
+	countResult, err := c.contractCaller.Call(ctx, "get_nft_count")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get NFT count: %v", err)
 	}
@@ -0,0 +1,86 @@
+package polkadot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/simulator"
+)
+
+// memoryChainEvents stores chain event IDs in a map
+type memoryChainEvents map[uint64]uint64
+
+func (m memoryChainEvents) GetChainEventID(ctx context.Context, eventID uint64) (uint64, bool, error) {
+	chainEventID, found := m[eventID]
+	return chainEventID, found, nil
+}
+
+func (m memoryChainEvents) SetChainEventID(ctx context.Context, eventID, chainEventID uint64) error {
+	m[eventID] = chainEventID
+	return nil
+}
+
+// TestClientMapsChainEventIDs registers events whose database IDs differ
+// from the IDs the contract assigns, and checks later calls use the latter
+func TestClientMapsChainEventIDs(t *testing.T) {
+	store := memoryChainEvents{}
+	client := &Client{
+		contractCaller: NewLegacyAdapter(simulator.New(simulator.Config{})),
+		txs:            newTxTracker(),
+		chainEvents:    store,
+	}
+	ctx := context.Background()
+
+	sub0, err := client.CreateEvent(ctx, 42, "Sub0", "2025-06-01", "Lisbon", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := client.CreateEvent(ctx, 7, "Polkadot Decoded", "2025-07-01", "Berlin", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store[42] != sub0 || store[7] != decoded || sub0 == decoded {
+		t.Fatalf("stored chain IDs %v, contract returned %d and %d", store, sub0, decoded)
+	}
+
+	for eventID, name := range map[uint64]string{42: "Sub0", 7: "Polkadot Decoded"} {
+		event, err := client.GetEvent(ctx, eventID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if event == nil || event.Name != name {
+			t.Errorf("event %d = %+v, want %s", eventID, event, name)
+		}
+	}
+
+	tokenID, minted, err := client.MintNFT(ctx, 42, 1, aliceHex, "ipfs://badge")
+	if err != nil || !minted {
+		t.Fatalf("mint = %v, %v", minted, err)
+	}
+	owned, err := client.ListNFTsByOwner(ctx, aliceHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(owned) != 1 || owned[0] != tokenID {
+		t.Errorf("owned tokens = %v, want [%d]", owned, tokenID)
+	}
+
+	// The transfer policy is read from the event's chain ID too
+	if _, err := client.TransferNFT(ctx, 7, tokenID, bobHex); !errors.Is(err, ErrNonTransferable) {
+		t.Errorf("transfer of a soulbound event's NFT: error = %v, want %v", err, ErrNonTransferable)
+	}
+	if ok, err := client.TransferNFT(ctx, 42, tokenID, bobHex); err != nil || !ok {
+		t.Errorf("transfer = %v, %v", ok, err)
+	}
+
+	// An event that was never registered fails rather than reaching whichever
+	// contract event happens to have its ID
+	if _, _, err := client.MintNFT(ctx, sub0, 2, aliceHex, "ipfs://badge"); err == nil || !strings.Contains(err.Error(), "isn't registered") {
+		t.Errorf("mint for an unregistered event: error = %v", err)
+	}
+	if _, err := client.GetEvent(ctx, decoded); err == nil {
+		t.Error("unregistered event was read from the contract")
+	}
+}
@@ -1,27 +1,32 @@
 package polkadot
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
 
 	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
-	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
 	"github.com/centrifuge/go-substrate-rpc-client/v4/signature"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
 	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/polkadot/bindings"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/simulator"
 )
 
-// Global singleton mock instance to maintain state between calls
+// Development fallback shared by clients without a reachable contract
 var (
-	globalMockCaller *MockContractCaller
-	mockCallerMutex  sync.Mutex
+	devSimulator     *simulator.Simulator
+	devSimulatorOnce sync.Once
 	contractMetadata *ContractMetadata
 )
 
 // ContractCaller interface for calling smart contracts
 type ContractCaller interface {
-	Call(method string, args ...interface{}) ([]byte, error)
+	Call(ctx context.Context, method string, args ...interface{}) ([]byte, error)
 }
 
 // RealContractCaller implements the ContractCaller interface for real blockchain interactions
@@ -30,15 +35,20 @@ type RealContractCaller struct {
 	contractAddr types.AccountID
 	signer       *signature.KeyringPair
 	// Use a shared mock instance for fallback
-	sharedMock   *MockContractCaller
-	metadata     *ContractMetadata
+	sharedMock ContractCaller
+	metadata   *ContractMetadata
+	contract   *bindings.Contract
+	// messages runs the messages the bindings don't cover
+	messages *metadataCaller
 }
 
-// NewContractCaller creates a new contract caller
-func NewContractCaller(api *gsrpc.SubstrateAPI, contractAddr types.AccountID) ContractCaller {
-	// Get the shared mock instance
-	sharedMock := GetSharedMockContractCaller()
-	
+// NewContractCaller creates a new contract caller.
+// Client speaks PSP34; unless the contract metadata shows a PSP34 contract,
+// the caller is wrapped in a LegacyAdapter for the original attendance messages.
+func NewContractCaller(api *gsrpc.SubstrateAPI, contractAddr types.AccountID, txs *txTracker) ContractCaller {
+	// Get the shared simulator, which implements the legacy messages
+	sharedMock := NewLegacyAdapter(sharedSimulator())
+
 	// If we have a valid API and contract address, return a real caller
 	if api != nil && contractAddr != (types.AccountID{}) {
 		// Load the development keypair for testing
@@ -49,21 +59,43 @@ func NewContractCaller(api *gsrpc.SubstrateAPI, contractAddr types.AccountID) Co
 			log.Printf("Falling back to mock implementation")
 			return sharedMock
 		}
-		
+
 		// Try to load contract metadata
 		metadata, err := loadContractMetadataWithCaching("attendance_nft.json")
 		if err != nil {
 			log.Printf("Failed to load contract metadata: %v", err)
 			log.Printf("Some contract functions may not be available, falling back to mock for those")
 		}
-		
-		return &RealContractCaller{
+
+		exec := &chainExecutor{
+			api:          api,
+			contractAddr: contractAddr,
+			signer:       signer,
+			txs:          txs,
+		}
+		caller := &RealContractCaller{
 			api:          api,
 			contractAddr: contractAddr,
 			signer:       &signer,
 			sharedMock:   sharedMock,
 			metadata:     metadata,
+			contract:     bindings.NewContract(exec),
+			messages:     newMetadataCaller(exec, metadata),
 		}
+
+		if IsPSP34Metadata(metadata) {
+			log.Printf("Contract metadata exposes PSP34, using standard messages")
+			if caller.messages == nil {
+				// Only ink! 4/5 metadata describes its types well enough to
+				// encode messages the bindings don't cover
+				log.Printf("Warning: PSP34 metadata predates ink! 4, its messages can't be encoded")
+				return caller
+			}
+			return newPSP34Adapter(caller.messages)
+		}
+
+		log.Printf("Using legacy attendance contract messages")
+		return NewLegacyAdapter(caller)
 	}
 
 	// Otherwise return a mock caller
@@ -75,313 +107,283 @@ func loadContractMetadataWithCaching(contractFile string) (*ContractMetadata, er
 	if contractMetadata != nil {
 		return contractMetadata, nil
 	}
-	
+
 	metadata, err := LoadContractMetadata(contractFile)
 	if err != nil {
 		return nil, err
 	}
-	
+
 	contractMetadata = metadata
 	return metadata, nil
 }
 
-// Call calls a smart contract method
-func (c *RealContractCaller) Call(method string, args ...interface{}) ([]byte, error) {
+// Call calls a smart contract method through the generated bindings
+func (c *RealContractCaller) Call(ctx context.Context, method string, args ...interface{}) ([]byte, error) {
 	log.Printf("Calling contract method: %s", method)
-	
-	// If we don't have metadata, fall back to mock
-	if c.metadata == nil {
-		log.Printf("No contract metadata available, using mock implementation for method: %s", method)
-		return c.sharedMock.Call(method, args...)
-	}
-	
-	// Try to find the method in the metadata
-	contractMethod, err := FindMethodInMetadata(c.metadata, method)
-	if err != nil {
-		log.Printf("Method not found in metadata: %s, error: %v", method, err)
-		log.Printf("Using mock implementation for unknown method: %s", method)
-		return c.sharedMock.Call(method, args...)
-	}
-	
-	// Add arguments to the method
-	contractMethod.Args = args
-	
-	// Check if this is a read-only operation
-	if isReadOnlyMethod(method) {
-		// For read operations, query the contract state
-		log.Printf("Performing read-only contract call: %s", method)
-		result, err := QueryContractState(c.api, c.contractAddr, contractMethod, args...)
-		if err != nil {
-			log.Printf("Failed to query contract state: %v", err)
-			log.Printf("Falling back to mock implementation for query: %s", method)
-			return c.sharedMock.Call(method, args...)
+
+	result, err := c.callBinding(ctx, method, args)
+	if errors.Is(err, ErrNoBinding) {
+		if !c.messages.has(method) {
+			return nil, err
 		}
-		return result, nil
-	}
-	
-	// For state-changing operations, we need to submit a transaction
-	log.Printf("Preparing state-changing contract call: %s", method)
-	
-	// Prepare the contract call
-	call, err := PrepareContractCall(c.api, c.contractAddr, contractMethod, args...)
-	if err != nil {
-		log.Printf("Failed to prepare contract call: %v", err)
-		log.Printf("Falling back to mock implementation for state change: %s", method)
-		return c.sharedMock.Call(method, args...)
-	}
-	
-	// Create a signed extrinsic
-	ext, err := CreateSignedExtrinsic(c.api, call, *c.signer)
-	if err != nil {
-		log.Printf("Failed to create signed extrinsic: %v", err)
-		log.Printf("Falling back to mock implementation for state change: %s", method)
-		return c.sharedMock.Call(method, args...)
+		// Like messages without a binding before, these never fall back to the mock
+		return c.messages.Call(ctx, method, args...)
 	}
-	
-	// Submit the extrinsic
-	sub, err := c.api.RPC.Author.SubmitAndWatchExtrinsic(ext)
 	if err != nil {
-		log.Printf("Failed to submit extrinsic: %v", err)
-		log.Printf("Falling back to mock implementation for state change: %s", method)
-		return c.sharedMock.Call(method, args...)
-	}
-	defer sub.Unsubscribe()
-	
-	// Wait for the transaction to be included in a block
-	for {
-		status := <-sub.Chan()
-		if status.IsInBlock {
-			log.Printf("Extrinsic included in block: %#x", status.AsInBlock)
-			break
-		}
-		if status.IsDropped || status.IsInvalid || status.IsUsurped {
-			return nil, fmt.Errorf("extrinsic failed: %v", status)
+		// Only reads fall back to the mock. A write the node rejected didn't
+		// happen, so reporting it as done would mark it done in the database.
+		if ctx.Err() == nil && isReadOnlyMethod(method) {
+			log.Printf("Contract call %s failed: %v", method, err)
+			log.Printf("Falling back to mock implementation for: %s", method)
+			return c.sharedMock.Call(ctx, method, args...)
 		}
+		return nil, err
 	}
-	
-	// For now, return a success response
-	// In a more complete implementation, we would extract events and return the actual result
-	
-	// Return the encoded result based on the method
-	switch method {
-	case "create_event":
-		// Mock a successful event creation with ID 1
-		return json.Marshal(uint64(1))
-	case "mint_nft":
-		// Mock a successful NFT minting
-		return json.Marshal(true)
-	default:
-		// For other methods, just return a success indicator
-		return json.Marshal(true)
-	}
-}
-
-// isReadOnlyMethod determines if a method is read-only (view/pure function)
-func isReadOnlyMethod(method string) bool {
-	readOnlyMethods := map[string]bool{
-		"get_event":      true,
-		"get_nft":        true,
-		"get_event_count": true,
-		"get_nft_count":  true,
-	}
-	
-	return readOnlyMethods[method]
-}
-
-// GetSharedMockContractCaller returns the global mock contract caller instance
-func GetSharedMockContractCaller() *MockContractCaller {
-	mockCallerMutex.Lock()
-	defer mockCallerMutex.Unlock()
-	
-	if globalMockCaller == nil {
-		// Initialize with default data
-		events := map[uint64]models.Event{
-			1: {
-				ID:        1,
-				Name:      "Polkadot Meetup",
-				Date:      "2023-06-01",
-				Location:  "Berlin",
-				Organizer: "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY",
-			},
-		}
-		
-		globalMockCaller = &MockContractCaller{
-			events:     events,
-			nfts:       make(map[uint64]models.NFT),
-			eventCount: 1,
-			nftCount:   0,
-		}
-		
-		log.Printf("Created global mock contract caller instance")
-	}
-	
-	return globalMockCaller
-}
 
-// NewMockContractCaller creates a new mock contract caller with some initial data
-func NewMockContractCaller() *MockContractCaller {
-	// Always return the shared instance
-	return GetSharedMockContractCaller()
+	return result, nil
 }
 
-// MockContractCaller provides a mock implementation for development
-type MockContractCaller struct {
-	events     map[uint64]models.Event
-	nfts       map[uint64]models.NFT
-	eventCount uint64
-	nftCount   uint64
-	mutex      sync.Mutex // To protect concurrent access
-}
+// ErrNoBinding is returned for messages neither the generated bindings nor
+// the contract metadata cover. They're never sent to the mock, since that
+// would fake the result of a real contract.
+var ErrNoBinding = errors.New("no contract binding")
 
-// Call mocks a smart contract method call
-func (c *MockContractCaller) Call(method string, args ...interface{}) ([]byte, error) {
-	// Lock to prevent race conditions
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-	
-	log.Printf("Mock contract caller: %s with %d args", method, len(args))
-	
+// callBinding runs a legacy contract message with the typed bindings and
+// converts its result to the JSON the other callers return
+func (c *RealContractCaller) callBinding(ctx context.Context, method string, args []interface{}) ([]byte, error) {
 	switch method {
 	case "create_event":
-		if len(args) < 3 {
-			return nil, fmt.Errorf("create_event requires 3 arguments")
+		if len(args) < 4 {
+			return nil, fmt.Errorf("create_event requires 4 arguments")
 		}
-
 		name, ok1 := args[0].(string)
 		date, ok2 := args[1].(string)
 		location, ok3 := args[2].(string)
-
-		if !ok1 || !ok2 || !ok3 {
+		transferable, ok4 := args[3].(bool)
+		if !ok1 || !ok2 || !ok3 || !ok4 {
 			return nil, fmt.Errorf("invalid argument types")
 		}
 
-		c.eventCount++
-		eventID := c.eventCount
-
-		c.events[eventID] = models.Event{
-			ID:        eventID,
-			Name:      name,
-			Date:      date,
-			Location:  location,
-			Organizer: "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY", // Mock organizer
+		eventID, err := c.contract.CreateEvent(ctx, name, date, location, transferable)
+		if err != nil {
+			return nil, err
 		}
-		
-		log.Printf("Created mock event with ID %d: %s", eventID, name)
-
 		return json.Marshal(eventID)
 
-	case "get_event":
-		if len(args) < 1 {
-			return nil, fmt.Errorf("get_event requires 1 argument")
+	case "mint_nft":
+		if len(args) < 3 {
+			return nil, fmt.Errorf("mint_nft requires 3 arguments")
+		}
+		eventID, err := uint64Arg(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid event ID: %v", err)
+		}
+		recipient, err := accountArg(args[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient: %v", err)
+		}
+		metadata, ok := args[2].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid metadata type")
 		}
 
-		var id uint64
-		switch v := args[0].(type) {
-		case uint64:
-			id = v
-		case float64:
-			id = uint64(v)
-		case int:
-			id = uint64(v)
-		case json.Number:
-			val, err := v.Int64()
-			if err != nil {
-				return nil, fmt.Errorf("invalid event ID: %v", err)
-			}
-			id = uint64(val)
-		case map[string]interface{}:
-			// This handles the case where we're passing a marshaled callData object
-			if methodArgs, ok := v["args"].([]interface{}); ok && len(methodArgs) > 0 {
-				if idVal, ok := methodArgs[0].(float64); ok {
-					id = uint64(idVal)
-				} else {
-					return nil, fmt.Errorf("invalid event ID type in callData")
+		// The contract assigns the token ID; it's read from the NFTMinted
+		// event of the block the mint was included in
+		ctx, sink := withContractEvents(ctx)
+		minted, err := c.contract.MintNFT(ctx, eventID, recipient, metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		result := models.MintResult{Minted: minted}
+		if minted {
+			for _, event := range sink.events {
+				if e, ok := event.(*bindings.NFTMinted); ok && e.EventID == eventID && e.Recipient == recipient {
+					result.NFTID = e.NFTID
 				}
-			} else {
-				return nil, fmt.Errorf("invalid callData format")
 			}
-		default:
-			return nil, fmt.Errorf("invalid event ID type: %T", args[0])
+			if result.NFTID == 0 {
+				log.Printf("Minted an NFT of event %d without seeing its NFTMinted event; its token ID is unknown", eventID)
+			}
 		}
-		
-		log.Printf("Looking up event ID: %d (available: %v)", id, c.events)
+		return json.Marshal(result)
 
-		event, exists := c.events[id]
-		if !exists {
-			log.Printf("Event %d not found in mock storage", id)
-			return []byte{}, nil
+	case "transfer":
+		if len(args) < 2 {
+			return nil, fmt.Errorf("transfer requires 2 arguments")
+		}
+		nftID, err := uint64Arg(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid NFT ID: %v", err)
+		}
+		to, err := accountArg(args[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient: %v", err)
 		}
-		
-		log.Printf("Found event %d: %s", id, event.Name)
-
-		return json.Marshal(event)
 
-	case "mint_nft":
-		if len(args) < 3 {
-			return nil, fmt.Errorf("mint_nft requires 3 arguments")
+		transferred, err := c.contract.Transfer(ctx, nftID, to)
+		if err != nil {
+			return nil, err
 		}
+		return json.Marshal(transferred)
 
-		var eventID uint64
-		var recipient string
-		var metadataJSON string
-		var ok bool
+	case "burn":
+		if len(args) < 1 {
+			return nil, fmt.Errorf("burn requires 1 argument")
+		}
+		nftID, err := uint64Arg(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid NFT ID: %v", err)
+		}
 
-		// Handle eventID with flexible type conversion
-		switch v := args[0].(type) {
-		case uint64:
-			eventID = v
-		case float64:
-			eventID = uint64(v)
-		case int:
-			eventID = uint64(v)
-		default:
-			return nil, fmt.Errorf("invalid event ID type: %T", args[0])
+		burned, err := c.contract.Burn(ctx, nftID)
+		if err != nil {
+			return nil, err
 		}
+		return json.Marshal(burned)
 
-		// Get recipient
-		if recipient, ok = args[1].(string); !ok {
-			return nil, fmt.Errorf("invalid recipient type")
+	case "get_event":
+		if len(args) < 1 {
+			return nil, fmt.Errorf("get_event requires 1 argument")
+		}
+		eventID, err := uint64Arg(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid event ID: %v", err)
 		}
 
-		// Get metadata JSON
-		if metadataJSON, ok = args[2].(string); !ok {
-			return nil, fmt.Errorf("invalid metadata type")
+		event, err := c.contract.GetEvent(ctx, eventID)
+		if err != nil || event == nil {
+			return []byte{}, err
+		}
+		return json.Marshal(models.Event{
+			ID:           eventID,
+			Name:         event.Name,
+			Date:         event.Date,
+			Location:     event.Location,
+			Organizer:    address.AccountID(event.Organizer).Hex(),
+			Transferable: event.Transferable,
+		})
+
+	case "get_nft":
+		if len(args) < 1 {
+			return nil, fmt.Errorf("get_nft requires 1 argument")
+		}
+		nftID, err := uint64Arg(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid NFT ID: %v", err)
 		}
 
-		// Check if event exists
-		_, exists := c.events[eventID]
-		if !exists {
-			log.Printf("Cannot mint NFT: Event %d not found", eventID)
-			return json.Marshal(false)
+		nft, err := c.contract.GetNFT(ctx, nftID)
+		if err != nil || nft == nil {
+			return []byte{}, err
 		}
 
-		// Parse metadata
-		var metadata map[string]interface{}
-		if err := json.Unmarshal([]byte(metadataJSON), &metadata); err != nil {
-			return nil, fmt.Errorf("invalid metadata JSON: %v", err)
+		// Older NFTs store their metadata as JSON; newer ones store a URI
+		result := models.NFT{
+			ID:      nft.ID,
+			EventID: nft.EventID,
+			Owner:   address.AccountID(nft.Owner).Hex(),
+		}
+		if err := json.Unmarshal([]byte(nft.Metadata), &result.Metadata); err != nil {
+			result.Metadata = nil
+			result.MetadataURI = nft.Metadata
 		}
+		return json.Marshal(result)
 
-		c.nftCount++
-		nftID := c.nftCount
+	case "get_owned_nfts":
+		if len(args) < 1 {
+			return nil, fmt.Errorf("get_owned_nfts requires 1 argument")
+		}
+		owner, err := accountArg(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid owner: %v", err)
+		}
 
-		c.nfts[nftID] = models.NFT{
-			ID:       nftID,
-			EventID:  eventID,
-			Owner:    recipient,
-			Metadata: metadata,
+		owned, err := c.contract.GetOwnedNFTs(ctx, owner)
+		if err != nil {
+			return nil, err
 		}
-		
-		log.Printf("Minted NFT %d for event %d, recipient %s", nftID, eventID, recipient)
+		return json.Marshal(owned)
 
-		return json.Marshal(true)
+	case "get_event_count":
+		count, err := c.contract.GetEventCount(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(count)
 
 	case "get_nft_count":
-		return json.Marshal(c.nftCount)
+		count, err := c.contract.GetNFTCount(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(count)
+	}
 
-	case "get_event_count":
-		return json.Marshal(c.eventCount)
+	return nil, fmt.Errorf("%w for method %s", ErrNoBinding, method)
+}
+
+// isReadOnlyMethod determines if a method is read-only (view/pure function)
+func isReadOnlyMethod(method string) bool {
+	readOnlyMethods := map[string]bool{
+		"get_event":              true,
+		"get_nft":                true,
+		"get_owned_nfts":         true,
+		"get_event_count":        true,
+		"get_nft_count":          true,
+		MethodOwnerOf:            true,
+		MethodBalanceOf:          true,
+		MethodTotalSupply:        true,
+		MethodGetAttribute:       true,
+		MethodOwnersTokenByIndex: true,
+	}
 
+	return readOnlyMethods[method]
+}
+
+// sharedSimulator returns the simulator shared by every client that falls back
+// to the development implementation
+func sharedSimulator() *simulator.Simulator {
+	devSimulatorOnce.Do(func() {
+		devSimulator = simulator.New(simulator.Config{Seed: true, Verbose: true})
+		log.Printf("Created development chain simulator")
+	})
+	return devSimulator
+}
+
+// isMockCaller reports whether a caller is backed by a simulator
+func isMockCaller(caller ContractCaller) bool {
+	if adapter, ok := caller.(*LegacyAdapter); ok {
+		caller = adapter.inner
+	}
+	_, isMock := caller.(*simulator.Simulator)
+	return isMock
+}
+
+// accountArg converts an address argument to a contract AccountId
+func accountArg(arg interface{}) (bindings.AccountID, error) {
+	account, ok := arg.(string)
+	if !ok {
+		return bindings.AccountID{}, fmt.Errorf("unsupported type: %T", arg)
+	}
+
+	id, _, err := address.Parse(account)
+	if err != nil {
+		return bindings.AccountID{}, err
+	}
+	return bindings.AccountID(id), nil
+}
+
+// uint64Arg converts a numeric call argument to uint64
+func uint64Arg(arg interface{}) (uint64, error) {
+	switch v := arg.(type) {
+	case uint64:
+		return v, nil
+	case float64:
+		return uint64(v), nil
+	case int:
+		return uint64(v), nil
 	default:
-		return nil, fmt.Errorf("unknown method: %s", method)
+		return 0, fmt.Errorf("unsupported type: %T", arg)
 	}
-}
\ No newline at end of file
+}
@@ -6,7 +6,6 @@ import (
 	"io/ioutil"
 	"log"
 	"path/filepath"
-	"strings"
 
 	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
 	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
@@ -19,6 +18,7 @@ type ContractMetadata struct {
 		Hash    string `json:"hash"`
 		Language string `json:"language"`
 		Compiler string `json:"compiler"`
+		Wasm     string `json:"wasm,omitempty"` // only present in .contract bundles
 	} `json:"source"`
 	Contract struct {
 		Name      string `json:"name"`
@@ -72,15 +72,8 @@ type ContractMetadata struct {
 			} `json:"events"`
 		} `json:"spec"`
 	} `json:"V1"`
-}
-
-// Method represents an ink! contract method
-type Method struct {
-	Name      string
-	Selector  string
-	Args      []interface{}
-	Mutates   bool
-	ReturnType string
+	// ink is the ink! 4/5 spec and type registry, nil for older metadata
+	ink *inkMetadata
 }
 
 // LoadContractMetadata loads the contract metadata from a file
@@ -127,62 +120,12 @@ func LoadContractMetadata(contractFile string) (*ContractMetadata, error) {
 		return nil, fmt.Errorf("failed to parse contract metadata: %v", err)
 	}
 
-	return &metadata, nil
-}
-
-// FindMethodInMetadata finds a method in the contract metadata
-func FindMethodInMetadata(metadata *ContractMetadata, methodName string) (*Method, error) {
-	for _, message := range metadata.V1.Spec.Messages {
-		if strings.EqualFold(message.Name, methodName) {
-			return &Method{
-				Name:     message.Name,
-				Selector: message.Selector,
-				Mutates:  message.Mutates,
-			}, nil
-		}
-	}
-	return nil, fmt.Errorf("method not found in contract metadata: %s", methodName)
-}
-
-// PrepareContractCall prepares a contract call for the given method and arguments
-func PrepareContractCall(api *gsrpc.SubstrateAPI, contractAddr types.AccountID, method *Method, args ...interface{}) (types.Call, error) {
-	// First get the metadata
-	meta, err := api.RPC.State.GetMetadataLatest()
+	metadata.ink, err = parseInkMetadata(data)
 	if err != nil {
-		return types.Call{}, fmt.Errorf("failed to get metadata: %v", err)
+		return nil, err
 	}
-	
-	// Here we would properly encode the method selector and arguments
-	// For now, this is a simplified implementation that doesn't actually encode correctly
-	
-	// In a real implementation, we would:
-	// 1. Encode the method selector (usually a 4-byte value)
-	// 2. Encode each argument according to its SCALE encoding
-	// 3. Concatenate these into the contract call data
-	
-	// This is a placeholder that doesn't correctly encode the data
-	gasLimit := types.NewUCompactFromUInt(1000000000)
-	value := types.NewUCompactFromUInt(0) // zero value transfer
-	
-	// Real implementation would encode the data properly
-	selector := []byte(method.Selector)
-	data := selector // Just the selector, no args encoding yet
-	
-	// Create the contract call
-	call, err := types.NewCall(
-		meta,
-		"Contracts.call",
-		contractAddr,
-		value,
-		gasLimit,
-		data,
-	)
-	
-	if err != nil {
-		return types.Call{}, fmt.Errorf("failed to create contract call: %v", err)
-	}
-	
-	return call, nil
+
+	return &metadata, nil
 }
 
 // CreateSignedExtrinsic creates a signed extrinsic for a contract call
@@ -248,13 +191,3 @@ func CreateSignedExtrinsic(api *gsrpc.SubstrateAPI, call types.Call, keypair sig
 	
 	return ext, nil
 }
-
-// QueryContractState performs a read-only query of contract state
-func QueryContractState(api *gsrpc.SubstrateAPI, contractAddr types.AccountID, method *Method, args ...interface{}) ([]byte, error) {
-	// This is a simplified placeholder that doesn't do the actual query
-	// In a real implementation, we would encode the selector and arguments
-	// and use the Contracts.call_readonly RPC call
-	
-	// For now, return a failure as this is not fully implemented
-	return []byte{}, fmt.Errorf("QueryContractState not fully implemented yet")
-} 
\ No newline at end of file
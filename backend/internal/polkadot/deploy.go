@@ -0,0 +1,444 @@
+package polkadot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/hash"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/scale"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/signature"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types/codec"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
+)
+
+// Default gas limit for instantiating the contract
+const (
+	DefaultInstantiateRefTime   = 50_000_000_000
+	DefaultInstantiateProofSize = 1_000_000
+)
+
+// ContractBundle is compiled contract code together with its metadata
+type ContractBundle struct {
+	Code     []byte
+	Metadata *ContractMetadata
+}
+
+// LoadContractBundle loads a cargo-contract .contract bundle, or a .wasm file
+// with its metadata in a .json file next to it
+func LoadContractBundle(path string) (*ContractBundle, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".contract":
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read contract bundle: %v", err)
+		}
+
+		var metadata ContractMetadata
+		if err := json.Unmarshal(data, &metadata); err != nil {
+			return nil, fmt.Errorf("failed to parse contract bundle: %v", err)
+		}
+
+		if metadata.Source.Wasm == "" {
+			return nil, fmt.Errorf("contract bundle %s has no wasm code", path)
+		}
+
+		code, err := codec.HexDecodeString(metadata.Source.Wasm)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode contract code: %v", err)
+		}
+
+		// Keep the code out of the metadata we carry around
+		metadata.Source.Wasm = ""
+		return &ContractBundle{Code: code, Metadata: &metadata}, nil
+
+	case ".wasm":
+		code, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read contract code: %v", err)
+		}
+
+		metadataPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".json"
+		metadata, err := LoadContractMetadata(metadataPath)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ContractBundle{Code: code, Metadata: metadata}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported contract file %s, expected .contract or .wasm", path)
+	}
+}
+
+// CodeHash returns the blake2b-256 hash the chain stores the code under
+func (b *ContractBundle) CodeHash() types.H256 {
+	return types.NewH256(blake2b256(b.Code))
+}
+
+// ConstructorSelector returns the selector of a constructor in the bundle's metadata
+func (b *ContractBundle) ConstructorSelector(name string) ([]byte, error) {
+	for _, constructor := range b.Metadata.V1.Spec.Constructors {
+		if strings.EqualFold(constructor.Name, name) {
+			if len(constructor.Args) > 0 {
+				return nil, fmt.Errorf("constructor %s takes arguments, which are not supported", name)
+			}
+			return codec.HexDecodeString(constructor.Selector)
+		}
+	}
+	return nil, fmt.Errorf("constructor not found in contract metadata: %s", name)
+}
+
+// Deployment records a deployed contract so later upgrades can be checked against it
+type Deployment struct {
+	Address    string            `json:"address"`
+	CodeHash   string            `json:"code_hash"`
+	Chain      string            `json:"chain"`
+	Metadata   *ContractMetadata `json:"metadata"`
+	DeployedAt time.Time         `json:"deployed_at"`
+}
+
+// LoadDeployment reads a deployment record
+func LoadDeployment(path string) (*Deployment, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deployment record: %v", err)
+	}
+
+	var deployment Deployment
+	if err := json.Unmarshal(data, &deployment); err != nil {
+		return nil, fmt.Errorf("failed to parse deployment record: %v", err)
+	}
+
+	return &deployment, nil
+}
+
+// Save writes a deployment record
+func (d *Deployment) Save(path string) error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal deployment record: %v", err)
+	}
+
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write deployment record: %v", err)
+	}
+
+	return nil
+}
+
+// CheckCompatibility lists the changes in next that would break callers of
+// a contract built from prev: removed messages, changed selectors, arguments
+// or mutability, and removed events. No problems means next can replace prev.
+func CheckCompatibility(prev, next *ContractMetadata) []string {
+	var problems []string
+
+	messages := make(map[string]int, len(next.V1.Spec.Messages))
+	for i, message := range next.V1.Spec.Messages {
+		messages[message.Name] = i
+	}
+
+	for _, old := range prev.V1.Spec.Messages {
+		i, exists := messages[old.Name]
+		if !exists {
+			problems = append(problems, fmt.Sprintf("message %s was removed", old.Name))
+			continue
+		}
+
+		message := next.V1.Spec.Messages[i]
+		if message.Selector != old.Selector {
+			problems = append(problems, fmt.Sprintf("message %s changed selector from %s to %s", old.Name, old.Selector, message.Selector))
+		}
+		if message.Mutates != old.Mutates {
+			problems = append(problems, fmt.Sprintf("message %s changed mutability", old.Name))
+		}
+
+		if len(message.Args) != len(old.Args) {
+			problems = append(problems, fmt.Sprintf("message %s changed from %d to %d arguments", old.Name, len(old.Args), len(message.Args)))
+			continue
+		}
+		for j, arg := range old.Args {
+			newType := strings.Join(message.Args[j].Type.DisplayName, "::")
+			oldType := strings.Join(arg.Type.DisplayName, "::")
+			if newType != oldType {
+				problems = append(problems, fmt.Sprintf("message %s argument %s changed type from %s to %s", old.Name, arg.Name, oldType, newType))
+			}
+		}
+
+		newReturn := strings.Join(message.ReturnType.DisplayName, "::")
+		oldReturn := strings.Join(old.ReturnType.DisplayName, "::")
+		if newReturn != oldReturn {
+			problems = append(problems, fmt.Sprintf("message %s changed return type from %s to %s", old.Name, oldReturn, newReturn))
+		}
+	}
+
+	events := make(map[string]bool, len(next.V1.Spec.Events))
+	for _, event := range next.V1.Spec.Events {
+		events[event.Name] = true
+	}
+	for _, old := range prev.V1.Spec.Events {
+		if !events[old.Name] {
+			problems = append(problems, fmt.Sprintf("event %s was removed", old.Name))
+		}
+	}
+
+	return problems
+}
+
+// Deployer uploads, instantiates and upgrades contracts with pallet-contracts
+type Deployer struct {
+	api    *gsrpc.SubstrateAPI
+	signer signature.KeyringPair
+	txs    *txTracker
+}
+
+// NewDeployer connects to a node and loads the deploying account
+func NewDeployer(rpcURL, signerURI string) (*Deployer, error) {
+	api, err := gsrpc.NewSubstrateAPI(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %v", rpcURL, err)
+	}
+
+	signer, err := signature.KeyringPairFromSecret(signerURI, uint16(address.PrefixGeneric))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create signer: %v", err)
+	}
+
+	return &Deployer{api: api, signer: signer, txs: newTxTracker()}, nil
+}
+
+// Signer returns the deploying account's address
+func (d *Deployer) Signer() string {
+	return d.signer.Address
+}
+
+// ChainName returns the name of the connected chain
+func (d *Deployer) ChainName() string {
+	chain, err := d.api.RPC.System.Chain()
+	if err != nil {
+		return "Unknown"
+	}
+	return string(chain)
+}
+
+// CodeExists reports whether code with the given hash has been uploaded
+func (d *Deployer) CodeExists(codeHash types.H256) (bool, error) {
+	meta, err := d.api.RPC.State.GetMetadataLatest()
+	if err != nil {
+		return false, fmt.Errorf("failed to get metadata: %v", err)
+	}
+
+	return d.storageExists(meta, "PristineCode", codeHash[:])
+}
+
+// UploadCode uploads contract code, skipping the upload if it's already on chain
+func (d *Deployer) UploadCode(ctx context.Context, code []byte) (types.H256, error) {
+	codeHash := types.NewH256(blake2b256(code))
+
+	exists, err := d.CodeExists(codeHash)
+	if err != nil {
+		return codeHash, err
+	}
+	if exists {
+		log.Printf("Code %#x is already uploaded", codeHash)
+		return codeHash, nil
+	}
+
+	meta, err := d.api.RPC.State.GetMetadataLatest()
+	if err != nil {
+		return codeHash, fmt.Errorf("failed to get metadata: %v", err)
+	}
+
+	// storage_deposit_limit is Option<Compact<Balance>>; None encodes the same for every Option
+	call, err := types.NewCall(
+		meta,
+		"Contracts.upload_code",
+		types.NewBytes(code),
+		types.NewOptionU128Empty(),
+		types.NewU8(0), // Determinism::Enforced
+	)
+	if err != nil {
+		return codeHash, fmt.Errorf("failed to create Contracts.upload_code call: %v", err)
+	}
+
+	if err := d.submit(ctx, "Contracts.upload_code", call); err != nil {
+		return codeHash, err
+	}
+
+	log.Printf("Uploaded code %#x (%d bytes)", codeHash, len(code))
+	return codeHash, nil
+}
+
+// Instantiate creates a contract from uploaded code and returns its address
+func (d *Deployer) Instantiate(ctx context.Context, codeHash types.H256, constructor, salt []byte, gasLimit types.Weight) (address.AccountID, error) {
+	meta, err := d.api.RPC.State.GetMetadataLatest()
+	if err != nil {
+		return address.AccountID{}, fmt.Errorf("failed to get metadata: %v", err)
+	}
+
+	call, err := types.NewCall(
+		meta,
+		"Contracts.instantiate",
+		types.NewUCompactFromUInt(0), // value
+		gasLimit,
+		types.NewOptionU128Empty(), // storage_deposit_limit: None
+		codeHash,
+		types.NewBytes(constructor),
+		types.NewBytes(salt),
+	)
+	if err != nil {
+		return address.AccountID{}, fmt.Errorf("failed to create Contracts.instantiate call: %v", err)
+	}
+
+	if err := d.submit(ctx, "Contracts.instantiate", call); err != nil {
+		return address.AccountID{}, err
+	}
+
+	var deployer address.AccountID
+	copy(deployer[:], d.signer.PublicKey)
+	contract := ContractAddress(deployer, codeHash, constructor, salt)
+
+	// Inclusion doesn't mean the constructor succeeded, so check the contract exists
+	exists, err := d.storageExists(meta, "ContractInfoOf", contract[:])
+	if err != nil {
+		return contract, err
+	}
+	if !exists {
+		return contract, fmt.Errorf("contract was not created at %s, the constructor may have failed", contract.SS58(address.PrefixGeneric))
+	}
+
+	return contract, nil
+}
+
+// DeployedCodeHash returns the hash of the code a contract currently runs
+func (d *Deployer) DeployedCodeHash(contract address.AccountID) (types.H256, error) {
+	meta, err := d.api.RPC.State.GetMetadataLatest()
+	if err != nil {
+		return types.H256{}, fmt.Errorf("failed to get metadata: %v", err)
+	}
+
+	key, err := types.CreateStorageKey(meta, "Contracts", "ContractInfoOf", contract[:])
+	if err != nil {
+		return types.H256{}, fmt.Errorf("failed to create storage key: %v", err)
+	}
+
+	raw, err := d.api.RPC.State.GetStorageRawLatest(key)
+	if err != nil {
+		return types.H256{}, fmt.Errorf("failed to read contract info: %v", err)
+	}
+	if raw == nil || len(*raw) == 0 {
+		return types.H256{}, fmt.Errorf("no contract at %s", contract.SS58(address.PrefixGeneric))
+	}
+
+	// ContractInfo starts with the trie ID; depending on the runtime version the
+	// code hash follows directly or after a deposit account
+	var trieID types.Bytes
+	decoder := scale.NewDecoder(bytes.NewReader(*raw))
+	if err := decoder.Decode(&trieID); err != nil {
+		return types.H256{}, fmt.Errorf("failed to decode contract info: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		var candidate types.H256
+		if err := decoder.Decode(&candidate); err != nil {
+			break
+		}
+
+		exists, err := d.storageExists(meta, "PristineCode", candidate[:])
+		if err != nil {
+			return types.H256{}, err
+		}
+		if exists {
+			return candidate, nil
+		}
+	}
+
+	return types.H256{}, fmt.Errorf("failed to find the code hash in the contract info")
+}
+
+// SetCode switches a contract to new code. pallet-contracts only accepts
+// set_code from root, so by default the call is wrapped in Sudo.sudo.
+func (d *Deployer) SetCode(ctx context.Context, contract address.AccountID, codeHash types.H256, useSudo bool) error {
+	meta, err := d.api.RPC.State.GetMetadataLatest()
+	if err != nil {
+		return fmt.Errorf("failed to get metadata: %v", err)
+	}
+
+	dest, err := types.NewMultiAddressFromAccountID(contract[:])
+	if err != nil {
+		return fmt.Errorf("failed to build contract address: %v", err)
+	}
+
+	call, err := types.NewCall(meta, "Contracts.set_code", dest, codeHash)
+	if err != nil {
+		return fmt.Errorf("failed to create Contracts.set_code call: %v", err)
+	}
+
+	if useSudo {
+		call, err = types.NewCall(meta, "Sudo.sudo", call)
+		if err != nil {
+			return fmt.Errorf("failed to create Sudo.sudo call: %v", err)
+		}
+	}
+
+	return d.submit(ctx, "Contracts.set_code", call)
+}
+
+// storageExists reports whether a Contracts storage entry exists
+func (d *Deployer) storageExists(meta *types.Metadata, item string, key []byte) (bool, error) {
+	storageKey, err := types.CreateStorageKey(meta, "Contracts", item, key)
+	if err != nil {
+		return false, fmt.Errorf("failed to create storage key for Contracts.%s: %v", item, err)
+	}
+
+	raw, err := d.api.RPC.State.GetStorageRawLatest(storageKey)
+	if err != nil {
+		return false, fmt.Errorf("failed to read Contracts.%s: %v", item, err)
+	}
+
+	return raw != nil && len(*raw) > 0, nil
+}
+
+// submit signs a call and waits until it's included in a block
+func (d *Deployer) submit(ctx context.Context, name string, call types.Call) error {
+	ext, err := CreateSignedExtrinsic(d.api, call, d.signer)
+	if err != nil {
+		return err
+	}
+
+	_, err = submitAndWatch(ctx, d.api, ext, name, d.txs)
+	return err
+}
+
+// ContractAddress derives the address pallet-contracts assigns to a new contract
+func ContractAddress(deployer address.AccountID, codeHash types.H256, input, salt []byte) address.AccountID {
+	data := make([]byte, 0, 16+32+32+len(input)+len(salt))
+	data = append(data, "contract_addr_v1"...)
+	data = append(data, deployer[:]...)
+	data = append(data, codeHash[:]...)
+	data = append(data, input...)
+	data = append(data, salt...)
+
+	var contract address.AccountID
+	copy(contract[:], blake2b256(data))
+	return contract
+}
+
+// blake2b256 hashes data with blake2b-256
+func blake2b256(data []byte) []byte {
+	hasher, err := hash.NewBlake2b256(nil)
+	if err != nil {
+		// Only fails for invalid keys, and no key is used
+		panic(err)
+	}
+	hasher.Write(data)
+	return hasher.Sum(nil)
+}
@@ -0,0 +1,150 @@
+package polkadot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/registry"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/registry/retriever"
+	regstate "github.com/centrifuge/go-substrate-rpc-client/v4/registry/state"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/polkadot/bindings"
+)
+
+// contractEmitted is the runtime event carrying a contract event
+const contractEmitted = "Contracts.ContractEmitted"
+
+// contractEventsKey is the context key of a contractEventSink
+type contractEventsKey struct{}
+
+// contractEventSink collects the contract events of the messages a
+// chainExecutor submits
+type contractEventSink struct {
+	events []interface{}
+}
+
+// withContractEvents returns a context under which chainExecutor records the
+// contract events emitted by the extrinsics it submits, once they're included
+func withContractEvents(ctx context.Context) (context.Context, *contractEventSink) {
+	sink := &contractEventSink{}
+	return context.WithValue(ctx, contractEventsKey{}, sink), sink
+}
+
+// contractEventsFrom returns the sink of a context, or nil if it has none
+func contractEventsFrom(ctx context.Context) *contractEventSink {
+	sink, _ := ctx.Value(contractEventsKey{}).(*contractEventSink)
+	return sink
+}
+
+// eventRetriever returns the retriever reading runtime events, creating it
+// on first use
+func (e *chainExecutor) eventRetriever() (retriever.EventRetriever, error) {
+	e.eventsMutex.Lock()
+	defer e.eventsMutex.Unlock()
+
+	if e.events != nil {
+		return e.events, nil
+	}
+
+	events, err := retriever.NewDefaultEventRetriever(regstate.NewEventProvider(e.api.RPC.State), e.api.RPC.State)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event retriever: %v", err)
+	}
+	e.events = events
+	return events, nil
+}
+
+// emittedEvents reads the events the contract emitted while an extrinsic
+// was applied in the block it was included in
+func (e *chainExecutor) emittedEvents(blockHash types.Hash, ext types.Extrinsic) ([]interface{}, error) {
+	txHash, err := extrinsicHash(ext)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := e.api.RPC.Chain.GetBlock(blockHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %#x: %v", blockHash, err)
+	}
+
+	index := -1
+	for i, included := range block.Block.Extrinsics {
+		if hash, err := extrinsicHash(included); err == nil && hash == txHash {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("extrinsic %s not found in block %#x", txHash, blockHash)
+	}
+
+	events, err := e.eventRetriever()
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := events.GetEvents(blockHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events of block %#x: %v", blockHash, err)
+	}
+
+	var emitted []interface{}
+	for _, record := range records {
+		if record.Name != contractEmitted || record.Phase == nil ||
+			!record.Phase.IsApplyExtrinsic || record.Phase.AsApplyExtrinsic != uint32(index) {
+			continue
+		}
+
+		// ContractEmitted { contract, data }; ink! puts the event's signature in the first topic
+		if len(record.Fields) < 2 || len(record.Topics) == 0 {
+			continue
+		}
+		contract, ok := decodedBytes(record.Fields[0].Value)
+		if !ok || !bytes.Equal(contract, e.contractAddr[:]) {
+			continue
+		}
+		data, ok := decodedBytes(record.Fields[1].Value)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s data", contractEmitted)
+		}
+
+		event, err := bindings.DecodeEvent(record.Topics[0], data)
+		if err != nil {
+			return nil, err
+		}
+		emitted = append(emitted, event)
+	}
+
+	return emitted, nil
+}
+
+// decodedBytes flattens a decoded byte array or vector, such as an
+// AccountId32 or a Vec<u8>
+func decodedBytes(value interface{}) ([]byte, bool) {
+	switch v := value.(type) {
+	case types.U8:
+		return []byte{byte(v)}, true
+	case []interface{}:
+		var result []byte
+		for _, item := range v {
+			b, ok := decodedBytes(item)
+			if !ok {
+				return nil, false
+			}
+			result = append(result, b...)
+		}
+		return result, true
+	case registry.DecodedFields:
+		var result []byte
+		for _, field := range v {
+			b, ok := decodedBytes(field.Value)
+			if !ok {
+				return nil, false
+			}
+			result = append(result, b...)
+		}
+		return result, true
+	}
+	return nil, false
+}
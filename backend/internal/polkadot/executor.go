@@ -0,0 +1,190 @@
+package polkadot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/registry/retriever"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/scale"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/signature"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types/codec"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/polkadot/bindings"
+)
+
+// revertFlag is set in ExecReturnValue.flags when a message reverted
+const revertFlag = 1
+
+// chainExecutor runs contract messages against a node. Every message is
+// dry-run through the ContractsApi_call runtime API; mutating messages are
+// then submitted as Contracts.call with the gas the dry run required.
+type chainExecutor struct {
+	api          *gsrpc.SubstrateAPI
+	contractAddr types.AccountID
+	signer       signature.KeyringPair
+	txs          *txTracker
+
+	events      retriever.EventRetriever
+	eventsMutex sync.Mutex
+}
+
+// dryRunArgs are the arguments of ContractsApi_call
+type dryRunArgs struct {
+	Origin              types.AccountID
+	Dest                types.AccountID
+	Value               types.U128
+	GasLimit            types.OptionBytes // None, which encodes the same for every Option
+	StorageDepositLimit types.OptionBytes // None
+	InputData           types.Bytes
+}
+
+// Exec implements bindings.Executor
+func (e *chainExecutor) Exec(ctx context.Context, msg bindings.Message) ([]byte, error) {
+	gasRequired, output, err := e.dryRun(ctx, msg.Input)
+	if err != nil {
+		return nil, fmt.Errorf("%s dry run failed: %v", msg.Label, err)
+	}
+
+	if !msg.Mutates {
+		return output, nil
+	}
+
+	meta, err := e.api.RPC.State.GetMetadataLatest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata: %v", err)
+	}
+
+	dest, err := types.NewMultiAddressFromAccountID(e.contractAddr[:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid contract address: %v", err)
+	}
+
+	call, err := types.NewCall(
+		meta,
+		"Contracts.call",
+		dest,
+		types.NewUCompactFromUInt(0),
+		gasRequired,
+		types.NewOptionU128Empty(), // storage_deposit_limit: None
+		types.NewBytes(msg.Input),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create contract call: %v", err)
+	}
+
+	ext, err := CreateSignedExtrinsic(e.api, call, e.signer)
+	if err != nil {
+		return nil, err
+	}
+
+	blockHash, err := submitAndWatch(ctx, e.api, ext, msg.Label, e.txs)
+	if err != nil {
+		return nil, err
+	}
+
+	// The message went through, so failing to read its events only loses them
+	if sink := contractEventsFrom(ctx); sink != nil {
+		emitted, err := e.emittedEvents(blockHash, ext)
+		if err != nil {
+			log.Printf("Failed to read the contract events of %s: %v", msg.Label, err)
+		}
+		sink.events = append(sink.events, emitted...)
+	}
+
+	// The extrinsic doesn't return the message output, so report what the dry
+	// run produced; for create_event this is the ID the contract assigned
+	// unless another transaction landed in between.
+	return output, nil
+}
+
+// dryRun executes a message without submitting it and returns the gas it
+// required and its output
+func (e *chainExecutor) dryRun(ctx context.Context, input []byte) (types.Weight, []byte, error) {
+	origin, err := types.NewAccountID(e.signer.PublicKey)
+	if err != nil {
+		return types.Weight{}, nil, fmt.Errorf("invalid signer: %v", err)
+	}
+
+	args, err := codec.Encode(dryRunArgs{
+		Origin:    *origin,
+		Dest:      e.contractAddr,
+		Value:     types.NewU128(*big.NewInt(0)),
+		InputData: types.NewBytes(input),
+	})
+	if err != nil {
+		return types.Weight{}, nil, fmt.Errorf("failed to encode call: %v", err)
+	}
+
+	var res string
+	if err := e.api.Client.CallContext(ctx, &res, "state_call", "ContractsApi_call", codec.HexEncodeToString(args)); err != nil {
+		return types.Weight{}, nil, fmt.Errorf("state_call failed: %v", err)
+	}
+
+	data, err := codec.HexDecodeString(res)
+	if err != nil {
+		return types.Weight{}, nil, fmt.Errorf("invalid state_call result: %v", err)
+	}
+
+	return decodeExecResult(data)
+}
+
+// decodeExecResult decodes the ContractExecResult of a dry run up to the
+// message result; the trailing event records are ignored
+func decodeExecResult(data []byte) (types.Weight, []byte, error) {
+	decoder := scale.NewDecoder(bytes.NewReader(data))
+
+	var gasConsumed, gasRequired types.Weight
+	if err := decoder.Decode(&gasConsumed); err != nil {
+		return types.Weight{}, nil, fmt.Errorf("failed to decode gas consumed: %v", err)
+	}
+	if err := decoder.Decode(&gasRequired); err != nil {
+		return types.Weight{}, nil, fmt.Errorf("failed to decode gas required: %v", err)
+	}
+
+	// storage_deposit: enum { Refund(Balance), Charge(Balance) }
+	var depositKind byte
+	var deposit types.U128
+	if err := decoder.Decode(&depositKind); err != nil {
+		return types.Weight{}, nil, fmt.Errorf("failed to decode storage deposit: %v", err)
+	}
+	if err := decoder.Decode(&deposit); err != nil {
+		return types.Weight{}, nil, fmt.Errorf("failed to decode storage deposit: %v", err)
+	}
+
+	var debugMessage types.Bytes
+	if err := decoder.Decode(&debugMessage); err != nil {
+		return types.Weight{}, nil, fmt.Errorf("failed to decode debug message: %v", err)
+	}
+
+	// result: Result<ExecReturnValue, DispatchError>
+	isErr, err := decoder.ReadOneByte()
+	if err != nil {
+		return types.Weight{}, nil, fmt.Errorf("failed to decode result: %v", err)
+	}
+	if isErr != 0 {
+		if len(debugMessage) > 0 {
+			return types.Weight{}, nil, fmt.Errorf("contract call failed: %s", string(debugMessage))
+		}
+		return types.Weight{}, nil, fmt.Errorf("contract call failed with a dispatch error")
+	}
+
+	var flags uint32
+	var output types.Bytes
+	if err := decoder.Decode(&flags); err != nil {
+		return types.Weight{}, nil, fmt.Errorf("failed to decode return flags: %v", err)
+	}
+	if err := decoder.Decode(&output); err != nil {
+		return types.Weight{}, nil, fmt.Errorf("failed to decode return data: %v", err)
+	}
+
+	if flags&revertFlag != 0 {
+		return types.Weight{}, nil, fmt.Errorf("contract reverted")
+	}
+
+	return gasRequired, output, nil
+}
@@ -0,0 +1,210 @@
+package polkadot
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/scale"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/polkadot/bindings"
+)
+
+// inkMetadata is the subset of ink! 4/5 contract metadata needed to encode
+// messages and decode their output at runtime
+type inkMetadata struct {
+	Spec struct {
+		Messages []inkMessage `json:"messages"`
+	} `json:"spec"`
+	Types []inkTypeEntry `json:"types"`
+}
+
+// inkMessage is a contract message
+type inkMessage struct {
+	Label      string      `json:"label"`
+	Selector   string      `json:"selector"`
+	Mutates    bool        `json:"mutates"`
+	Args       []inkArg    `json:"args"`
+	ReturnType *inkTypeRef `json:"returnType"`
+}
+
+// inkArg is a message argument
+type inkArg struct {
+	Label string     `json:"label"`
+	Type  inkTypeRef `json:"type"`
+}
+
+// inkTypeRef points into the type registry
+type inkTypeRef struct {
+	DisplayName []string `json:"displayName"`
+	Type        int      `json:"type"`
+}
+
+// inkTypeEntry is a scale-info registry entry
+type inkTypeEntry struct {
+	ID   int `json:"id"`
+	Type struct {
+		Path []string   `json:"path"`
+		Def  inkTypeDef `json:"def"`
+	} `json:"type"`
+}
+
+// inkTypeDef is the definition of a registry type; exactly one field is set
+type inkTypeDef struct {
+	Primitive string `json:"primitive"`
+	Composite *struct {
+		Fields []inkField `json:"fields"`
+	} `json:"composite"`
+	Variant *struct {
+		Variants []inkVariant `json:"variants"`
+	} `json:"variant"`
+	Sequence *struct {
+		Type int `json:"type"`
+	} `json:"sequence"`
+	Array *struct {
+		Len  int `json:"len"`
+		Type int `json:"type"`
+	} `json:"array"`
+	Tuple   *[]int `json:"tuple"`
+	Compact *struct {
+		Type int `json:"type"`
+	} `json:"compact"`
+}
+
+// inkVariant is a variant of an enum type
+type inkVariant struct {
+	Name   string     `json:"name"`
+	Index  int        `json:"index"`
+	Fields []inkField `json:"fields"`
+}
+
+// inkField is a field of a composite type or variant
+type inkField struct {
+	Name     string `json:"name"`
+	Type     int    `json:"type"`
+	TypeName string `json:"typeName"`
+}
+
+// parseInkMetadata parses ink! 4/5 metadata. It returns nil for other
+// metadata versions.
+func parseInkMetadata(data []byte) (*inkMetadata, error) {
+	var header struct {
+		Version json.RawMessage `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("failed to parse contract metadata: %v", err)
+	}
+
+	// ink! 4 writes the version as "4", ink! 5 as 5
+	version := strings.Trim(string(header.Version), `"`)
+	if version != "4" && version != "5" {
+		return nil, nil
+	}
+
+	var metadata inkMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse contract metadata: %v", err)
+	}
+	return &metadata, nil
+}
+
+// message returns a message by label
+func (m *inkMetadata) message(label string) *inkMessage {
+	for i := range m.Spec.Messages {
+		if m.Spec.Messages[i].Label == label {
+			return &m.Spec.Messages[i]
+		}
+	}
+	return nil
+}
+
+// lookup returns a registry type by ID
+func (m *inkMetadata) lookup(id int) (*inkTypeEntry, error) {
+	if id >= 0 && id < len(m.Types) && m.Types[id].ID == id {
+		return &m.Types[id], nil
+	}
+
+	for i := range m.Types {
+		if m.Types[i].ID == id {
+			return &m.Types[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("type %d not found in registry", id)
+}
+
+// metadataCaller runs any message declared in the contract metadata. Its
+// arguments are SCALE-encoded and its output decoded with the metadata's type
+// registry, so it covers messages the generated bindings don't.
+type metadataCaller struct {
+	exec     bindings.Executor
+	metadata *inkMetadata
+}
+
+// newMetadataCaller returns nil when the metadata has no ink! 4/5 spec
+func newMetadataCaller(exec bindings.Executor, metadata *ContractMetadata) *metadataCaller {
+	if metadata == nil || metadata.ink == nil {
+		return nil
+	}
+	return &metadataCaller{exec: exec, metadata: metadata.ink}
+}
+
+// has reports whether the contract declares a message
+func (c *metadataCaller) has(label string) bool {
+	return c != nil && c.metadata.message(label) != nil
+}
+
+// Call implements ContractCaller and returns the decoded output as JSON
+func (c *metadataCaller) Call(ctx context.Context, method string, args ...interface{}) ([]byte, error) {
+	value, err := c.call(ctx, method, args...)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// call runs a message and returns its decoded output. Ok values of a Result
+// are unwrapped; an Err is returned as a *contractError.
+func (c *metadataCaller) call(ctx context.Context, label string, args ...interface{}) (interface{}, error) {
+	msg := c.metadata.message(label)
+	if msg == nil {
+		return nil, fmt.Errorf("contract metadata has no message %s", label)
+	}
+	if len(args) != len(msg.Args) {
+		return nil, fmt.Errorf("%s requires %d arguments", label, len(msg.Args))
+	}
+
+	selectorBytes, err := hex.DecodeString(strings.TrimPrefix(msg.Selector, "0x"))
+	if err != nil || len(selectorBytes) != 4 {
+		return nil, fmt.Errorf("invalid selector %q for %s", msg.Selector, label)
+	}
+	var selector [4]byte
+	copy(selector[:], selectorBytes)
+
+	var buf bytes.Buffer
+	buf.Write(selector[:])
+
+	encoder := scale.NewEncoder(&buf)
+	for i, arg := range args {
+		if err := c.metadata.encode(encoder, msg.Args[i].Type.Type, arg); err != nil {
+			return nil, fmt.Errorf("failed to encode argument %s of %s: %v", msg.Args[i].Label, label, err)
+		}
+	}
+
+	output, err := c.exec.Exec(ctx, bindings.Message{Label: label, Selector: selector, Input: buf.Bytes(), Mutates: msg.Mutates})
+	if err != nil {
+		return nil, err
+	}
+
+	if msg.ReturnType == nil {
+		return nil, nil
+	}
+
+	value, err := c.metadata.decode(scale.NewDecoder(bytes.NewReader(output)), msg.ReturnType.Type)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", label, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,670 @@
+package polkadot
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/scale"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/polkadot/bindings"
+)
+
+// contractError is the Err variant of a Result a message returned, e.g.
+// PSP34Error::TokenNotExists
+type contractError struct {
+	Variant string
+	Value   interface{} // the variant's field, if it has one
+}
+
+func (e *contractError) Error() string {
+	if e.Value != nil {
+		return fmt.Sprintf("contract returned %s(%v)", e.Variant, e.Value)
+	}
+	return fmt.Sprintf("contract returned %s", e.Variant)
+}
+
+// primitiveSizes are the encoded sizes of the fixed-width integer primitives
+var primitiveSizes = map[string]int{
+	"u8": 1, "u16": 2, "u32": 4, "u64": 8, "u128": 16, "u256": 32,
+	"i8": 1, "i16": 2, "i32": 4, "i64": 8, "i128": 16, "i256": 32,
+}
+
+// encode SCALE-encodes a Go value as registry type id. Accepted values:
+//   - integers from any Go integer, a whole float64, a decimal string or *big.Int
+//   - AccountId and other [u8; N] arrays from an address string or a byte array
+//   - Vec<u8> from []byte or a string
+//   - Option from nil (None) or the inner value (Some)
+//   - enums from a unit variant's name, a {"Variant": value} map, or a number,
+//     which selects the variant holding a u64 (e.g. PSP34 Id::U64)
+//   - structs from a map keyed by field name; newtypes from their inner value
+func (m *inkMetadata) encode(e *scale.Encoder, id int, value interface{}) error {
+	entry, err := m.lookup(id)
+	if err != nil {
+		return err
+	}
+	def := entry.Type.Def
+
+	switch {
+	case def.Primitive != "":
+		return encodePrimitive(e, def.Primitive, value)
+
+	case def.Composite != nil:
+		fields := def.Composite.Fields
+		if len(fields) == 1 && fields[0].Name == "" {
+			return m.encode(e, fields[0].Type, value)
+		}
+		return m.encodeFields(e, fields, value)
+
+	case def.Variant != nil:
+		return m.encodeVariant(e, entry, value)
+
+	case def.Sequence != nil:
+		if m.isU8(def.Sequence.Type) {
+			data, err := bytesArg(value)
+			if err != nil {
+				return err
+			}
+			if err := e.EncodeUintCompact(*big.NewInt(int64(len(data)))); err != nil {
+				return err
+			}
+			return e.Write(data)
+		}
+
+		items, err := sliceArg(value)
+		if err != nil {
+			return err
+		}
+		if err := e.EncodeUintCompact(*big.NewInt(int64(len(items)))); err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := m.encode(e, def.Sequence.Type, item); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case def.Array != nil:
+		if m.isU8(def.Array.Type) {
+			data, err := fixedBytesArg(value, def.Array.Len)
+			if err != nil {
+				return err
+			}
+			return e.Write(data)
+		}
+
+		items, err := sliceArg(value)
+		if err != nil {
+			return err
+		}
+		if len(items) != def.Array.Len {
+			return fmt.Errorf("expected %d items, got %d", def.Array.Len, len(items))
+		}
+		for _, item := range items {
+			if err := m.encode(e, def.Array.Type, item); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case def.Tuple != nil:
+		if len(*def.Tuple) == 0 {
+			return nil
+		}
+		items, err := sliceArg(value)
+		if err != nil {
+			return err
+		}
+		if len(items) != len(*def.Tuple) {
+			return fmt.Errorf("expected a %d-tuple, got %d items", len(*def.Tuple), len(items))
+		}
+		for i, item := range items {
+			if err := m.encode(e, (*def.Tuple)[i], item); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case def.Compact != nil:
+		n, err := bigIntArg(value)
+		if err != nil {
+			return err
+		}
+		if n.Sign() < 0 {
+			return fmt.Errorf("compact value %s is negative", n)
+		}
+		return e.EncodeUintCompact(*n)
+	}
+
+	return fmt.Errorf("type %d has an unsupported definition", id)
+}
+
+// encodeFields encodes a struct from a map keyed by field name, or a tuple
+// struct from a slice
+func (m *inkMetadata) encodeFields(e *scale.Encoder, fields []inkField, value interface{}) error {
+	if len(fields) > 0 && fields[0].Name == "" {
+		items, err := sliceArg(value)
+		if err != nil {
+			return err
+		}
+		if len(items) != len(fields) {
+			return fmt.Errorf("expected %d fields, got %d", len(fields), len(items))
+		}
+		for i, field := range fields {
+			if err := m.encode(e, field.Type, items[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	values, ok := value.(map[string]interface{})
+	if !ok && len(fields) > 0 {
+		return fmt.Errorf("unsupported type for a struct: %T", value)
+	}
+	for _, field := range fields {
+		fieldValue, exists := values[field.Name]
+		if !exists {
+			return fmt.Errorf("missing field %s", field.Name)
+		}
+		if err := m.encode(e, field.Type, fieldValue); err != nil {
+			return fmt.Errorf("field %s: %v", field.Name, err)
+		}
+	}
+	return nil
+}
+
+// encodeVariant encodes an enum value
+func (m *inkMetadata) encodeVariant(e *scale.Encoder, entry *inkTypeEntry, value interface{}) error {
+	variants := entry.Type.Def.Variant.Variants
+
+	if isOption(entry) {
+		if value == nil {
+			return e.PushByte(0)
+		}
+		some := findVariant(variants, "Some")
+		if some == nil || len(some.Fields) != 1 {
+			return fmt.Errorf("invalid Option type %d", entry.ID)
+		}
+		if err := e.PushByte(byte(some.Index)); err != nil {
+			return err
+		}
+		return m.encode(e, some.Fields[0].Type, value)
+	}
+
+	var variant *inkVariant
+	var fieldValue interface{}
+	switch v := value.(type) {
+	case string:
+		variant = findVariant(variants, v)
+		if variant == nil || len(variant.Fields) != 0 {
+			return fmt.Errorf("%s is not a unit variant of %s", v, typePath(entry))
+		}
+	case map[string]interface{}:
+		if len(v) != 1 {
+			return fmt.Errorf("expected a single variant, got %d keys", len(v))
+		}
+		for name, field := range v {
+			variant, fieldValue = findVariant(variants, name), field
+		}
+		if variant == nil {
+			return fmt.Errorf("unknown variant of %s", typePath(entry))
+		}
+	default:
+		// A bare number selects the variant holding a u64, the
+		// representation token IDs use
+		for i := range variants {
+			if len(variants[i].Fields) == 1 && m.isPrimitive(variants[i].Fields[0].Type, "u64") {
+				variant, fieldValue = &variants[i], value
+				break
+			}
+		}
+		if variant == nil {
+			return fmt.Errorf("unsupported type %T for %s", value, typePath(entry))
+		}
+	}
+
+	if err := e.PushByte(byte(variant.Index)); err != nil {
+		return err
+	}
+
+	switch len(variant.Fields) {
+	case 0:
+		return nil
+	case 1:
+		if variant.Fields[0].Name == "" {
+			return m.encode(e, variant.Fields[0].Type, fieldValue)
+		}
+	}
+	return m.encodeFields(e, variant.Fields, fieldValue)
+}
+
+// decode SCALE-decodes registry type id. Integers decode to uint64/int64, or
+// *big.Int when they don't fit; AccountId to a hex address; Vec<u8> to
+// []byte; structs to maps; Option to nil or the inner value; unit variants
+// to their name and single-field variants to their field. A Result's Ok value
+// is returned as is and its Err as a *contractError, or
+// bindings.ErrCouldNotReadInput for an ink! LangError.
+func (m *inkMetadata) decode(d *scale.Decoder, id int) (interface{}, error) {
+	entry, err := m.lookup(id)
+	if err != nil {
+		return nil, err
+	}
+	def := entry.Type.Def
+
+	switch {
+	case def.Primitive != "":
+		return decodePrimitive(d, def.Primitive)
+
+	case def.Composite != nil:
+		fields := def.Composite.Fields
+		if isAccountID(entry) {
+			var account address.AccountID
+			if err := d.Read(account[:]); err != nil {
+				return nil, err
+			}
+			return account.Hex(), nil
+		}
+		if len(fields) == 1 && fields[0].Name == "" {
+			return m.decode(d, fields[0].Type)
+		}
+		return m.decodeFields(d, fields)
+
+	case def.Variant != nil:
+		return m.decodeVariant(d, entry)
+
+	case def.Sequence != nil:
+		length, err := d.DecodeUintCompact()
+		if err != nil {
+			return nil, err
+		}
+		if !length.IsInt64() || length.Int64() > math.MaxInt32 {
+			return nil, fmt.Errorf("sequence length %s is too large", length)
+		}
+		return m.decodeItems(d, def.Sequence.Type, int(length.Int64()))
+
+	case def.Array != nil:
+		return m.decodeItems(d, def.Array.Type, def.Array.Len)
+
+	case def.Tuple != nil:
+		if len(*def.Tuple) == 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, len(*def.Tuple))
+		for i, itemType := range *def.Tuple {
+			if items[i], err = m.decode(d, itemType); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+
+	case def.Compact != nil:
+		n, err := d.DecodeUintCompact()
+		if err != nil {
+			return nil, err
+		}
+		return bigIntValue(n), nil
+	}
+
+	return nil, fmt.Errorf("type %d has an unsupported definition", id)
+}
+
+// decodeItems decodes count items of a sequence or array; u8 items are
+// returned as []byte
+func (m *inkMetadata) decodeItems(d *scale.Decoder, itemType, count int) (interface{}, error) {
+	if m.isU8(itemType) {
+		data := make([]byte, count)
+		if err := d.Read(data); err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+
+	items := make([]interface{}, count)
+	for i := range items {
+		item, err := m.decode(d, itemType)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+// decodeFields decodes a struct to a map, or a tuple struct to a slice
+func (m *inkMetadata) decodeFields(d *scale.Decoder, fields []inkField) (interface{}, error) {
+	if len(fields) > 0 && fields[0].Name == "" {
+		items := make([]interface{}, len(fields))
+		for i, field := range fields {
+			item, err := m.decode(d, field.Type)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	}
+
+	values := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		value, err := m.decode(d, field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", field.Name, err)
+		}
+		values[field.Name] = value
+	}
+	return values, nil
+}
+
+// decodeVariant decodes an enum value
+func (m *inkMetadata) decodeVariant(d *scale.Decoder, entry *inkTypeEntry) (interface{}, error) {
+	variant, err := readVariant(d, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	if isResult(entry) && variant.Name == "Err" {
+		return nil, m.decodeErr(d, variant.Fields[0].Type)
+	}
+
+	switch {
+	case len(variant.Fields) == 1 && variant.Fields[0].Name == "":
+		return m.decode(d, variant.Fields[0].Type)
+	case len(variant.Fields) > 0:
+		return m.decodeFields(d, variant.Fields)
+	case isOption(entry):
+		return nil, nil
+	default:
+		return variant.Name, nil
+	}
+}
+
+// decodeErr decodes the Err value of a Result as an error
+func (m *inkMetadata) decodeErr(d *scale.Decoder, id int) error {
+	entry, err := m.lookup(id)
+	if err != nil {
+		return err
+	}
+
+	if typePath(entry) == "ink_primitives::LangError" {
+		return bindings.ErrCouldNotReadInput
+	}
+
+	if entry.Type.Def.Variant == nil {
+		value, err := m.decode(d, id)
+		if err != nil {
+			return err
+		}
+		return &contractError{Variant: "Err", Value: value}
+	}
+
+	// Keep the variant name, so PSP34Error::Custom(msg) isn't reported as msg
+	variant, err := readVariant(d, entry)
+	if err != nil {
+		return err
+	}
+
+	var value interface{}
+	switch {
+	case len(variant.Fields) == 1 && variant.Fields[0].Name == "":
+		value, err = m.decode(d, variant.Fields[0].Type)
+	case len(variant.Fields) > 0:
+		value, err = m.decodeFields(d, variant.Fields)
+	}
+	if err != nil {
+		return err
+	}
+	return &contractError{Variant: variant.Name, Value: value}
+}
+
+// readVariant reads the index of an enum value
+func readVariant(d *scale.Decoder, entry *inkTypeEntry) (*inkVariant, error) {
+	index, err := d.ReadOneByte()
+	if err != nil {
+		return nil, err
+	}
+
+	variants := entry.Type.Def.Variant.Variants
+	for i := range variants {
+		if variants[i].Index == int(index) {
+			return &variants[i], nil
+		}
+	}
+	return nil, fmt.Errorf("invalid variant %d of %s", index, typePath(entry))
+}
+
+// isU8 reports whether registry type id is u8
+func (m *inkMetadata) isU8(id int) bool {
+	return m.isPrimitive(id, "u8")
+}
+
+// isPrimitive reports whether registry type id is the named primitive
+func (m *inkMetadata) isPrimitive(id int, name string) bool {
+	entry, err := m.lookup(id)
+	return err == nil && entry.Type.Def.Primitive == name
+}
+
+// typePath returns the Rust path of a registry type
+func typePath(entry *inkTypeEntry) string {
+	return strings.Join(entry.Type.Path, "::")
+}
+
+func isOption(entry *inkTypeEntry) bool { return typePath(entry) == "Option" }
+
+func isResult(entry *inkTypeEntry) bool { return typePath(entry) == "Result" }
+
+func isAccountID(entry *inkTypeEntry) bool {
+	path := entry.Type.Path
+	return len(path) > 0 && path[len(path)-1] == "AccountId"
+}
+
+// findVariant returns a variant by name
+func findVariant(variants []inkVariant, name string) *inkVariant {
+	for i := range variants {
+		if variants[i].Name == name {
+			return &variants[i]
+		}
+	}
+	return nil
+}
+
+// encodePrimitive encodes a bool, str or fixed-width integer
+func encodePrimitive(e *scale.Encoder, primitive string, value interface{}) error {
+	switch primitive {
+	case "bool":
+		v, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("unsupported type for bool: %T", value)
+		}
+		return e.Encode(v)
+	case "str":
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unsupported type for str: %T", value)
+		}
+		return e.Encode(v)
+	}
+
+	size, ok := primitiveSizes[primitive]
+	if !ok {
+		return fmt.Errorf("primitive %s is not supported", primitive)
+	}
+
+	n, err := bigIntArg(value)
+	if err != nil {
+		return err
+	}
+
+	bits := uint(size * 8)
+	if primitive[0] == 'i' {
+		limit := new(big.Int).Lsh(big.NewInt(1), bits-1)
+		if n.Cmp(limit) >= 0 || n.Cmp(new(big.Int).Neg(limit)) < 0 {
+			return fmt.Errorf("%s overflows %s", n, primitive)
+		}
+		if n.Sign() < 0 {
+			n = new(big.Int).Add(n, new(big.Int).Lsh(big.NewInt(1), bits))
+		}
+	} else if n.Sign() < 0 || n.BitLen() > int(bits) {
+		return fmt.Errorf("%s overflows %s", n, primitive)
+	}
+
+	// SCALE integers are little endian
+	data := n.FillBytes(make([]byte, size))
+	for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
+		data[i], data[j] = data[j], data[i]
+	}
+	return e.Write(data)
+}
+
+// decodePrimitive decodes a bool, str or fixed-width integer
+func decodePrimitive(d *scale.Decoder, primitive string) (interface{}, error) {
+	switch primitive {
+	case "bool":
+		var v bool
+		err := d.Decode(&v)
+		return v, err
+	case "str":
+		var v string
+		err := d.Decode(&v)
+		return v, err
+	}
+
+	size, ok := primitiveSizes[primitive]
+	if !ok {
+		return nil, fmt.Errorf("primitive %s is not supported", primitive)
+	}
+
+	data := make([]byte, size)
+	if err := d.Read(data); err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
+		data[i], data[j] = data[j], data[i]
+	}
+
+	n := new(big.Int).SetBytes(data)
+	if primitive[0] == 'i' && n.Bit(size*8-1) == 1 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(size*8)))
+	}
+	return bigIntValue(n), nil
+}
+
+// bigIntValue narrows an integer to uint64 or int64 when it fits
+func bigIntValue(n *big.Int) interface{} {
+	switch {
+	case n.IsUint64():
+		return n.Uint64()
+	case n.IsInt64():
+		return n.Int64()
+	default:
+		return n
+	}
+}
+
+// bigIntArg converts an integer argument to a big.Int
+func bigIntArg(arg interface{}) (*big.Int, error) {
+	switch v := arg.(type) {
+	case *big.Int:
+		return v, nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	case uint32:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint16:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint8:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case int32:
+		return big.NewInt(int64(v)), nil
+	case float64:
+		if v != math.Trunc(v) {
+			return nil, fmt.Errorf("%v is not an integer", v)
+		}
+		n, _ := big.NewFloat(v).Int(nil)
+		return n, nil
+	case json.Number:
+		return bigIntArg(string(v))
+	case string:
+		n, ok := new(big.Int).SetString(v, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %s", strconv.Quote(v))
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("unsupported type for an integer: %T", arg)
+	}
+}
+
+// bytesArg converts a Vec<u8> argument to bytes
+func bytesArg(arg interface{}) ([]byte, error) {
+	switch v := arg.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	case nil:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported type for bytes: %T", arg)
+	}
+}
+
+// fixedBytesArg converts a [u8; size] argument to bytes. Strings are parsed
+// as addresses, so AccountId arguments can be passed the way the API accepts
+// them.
+func fixedBytesArg(arg interface{}, size int) ([]byte, error) {
+	var data []byte
+	switch v := arg.(type) {
+	case string:
+		if size != len(address.AccountID{}) {
+			return nil, fmt.Errorf("unsupported type for [u8; %d]: %T", size, arg)
+		}
+		id, _, err := address.Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		data = id[:]
+	case address.AccountID:
+		data = v[:]
+	case bindings.AccountID:
+		data = v[:]
+	case []byte:
+		data = v
+	default:
+		return nil, fmt.Errorf("unsupported type for [u8; %d]: %T", size, arg)
+	}
+
+	if len(data) != size {
+		return nil, fmt.Errorf("expected %d bytes, got %d", size, len(data))
+	}
+	return data, nil
+}
+
+// sliceArg converts a sequence argument of any slice type to []interface{}
+func sliceArg(arg interface{}) ([]interface{}, error) {
+	if items, ok := arg.([]interface{}); ok {
+		return items, nil
+	}
+
+	v := reflect.ValueOf(arg)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("unsupported type for a sequence: %T", arg)
+	}
+
+	items := make([]interface{}, v.Len())
+	for i := range items {
+		items[i] = v.Index(i).Interface()
+	}
+	return items, nil
+}
@@ -0,0 +1,196 @@
+package polkadot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/polkadot/bindings"
+)
+
+const aliceHex = "0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d"
+
+// recordingExecutor records the messages it runs and returns canned output
+type recordingExecutor struct {
+	messages []bindings.Message
+	outputs  map[string][]byte
+}
+
+func (e *recordingExecutor) Exec(ctx context.Context, msg bindings.Message) ([]byte, error) {
+	e.messages = append(e.messages, msg)
+	output, ok := e.outputs[msg.Label]
+	if !ok {
+		return nil, errors.New("unexpected message " + msg.Label)
+	}
+	return output, nil
+}
+
+func loadTestMetadata(t *testing.T, path string) *inkMetadata {
+	t.Helper()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata, err := parseInkMetadata(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata == nil {
+		t.Fatalf("%s is not ink! 4/5 metadata", path)
+	}
+	return metadata
+}
+
+// TestMetadataCallerMatchesBindings encodes legacy messages from the contract
+// metadata and checks they're byte for byte what the generated bindings send
+func TestMetadataCallerMatchesBindings(t *testing.T) {
+	metadata := loadTestMetadata(t, "bindings/attendance_nft.json")
+	alice, err := accountArg(aliceHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outputs := map[string][]byte{
+		"create_event": {0x00, 0x07, 0, 0, 0, 0, 0, 0, 0},
+		"mint_nft":     {0x00, 0x01},
+	}
+	fromBindings := &recordingExecutor{outputs: outputs}
+	fromMetadata := &recordingExecutor{outputs: outputs}
+	contract := bindings.NewContract(fromBindings)
+	caller := &metadataCaller{exec: fromMetadata, metadata: metadata}
+	ctx := context.Background()
+
+	eventID, err := contract.CreateEvent(ctx, "Polkadot Decoded", "2025-06-01", "Berlin", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := caller.call(ctx, "create_event", "Polkadot Decoded", "2025-06-01", "Berlin", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != eventID {
+		t.Errorf("create_event = %v, bindings returned %v", value, eventID)
+	}
+
+	if _, err := contract.MintNFT(ctx, 7, alice, "ipfs://badge"); err != nil {
+		t.Fatal(err)
+	}
+	value, err = caller.call(ctx, "mint_nft", uint64(7), aliceHex, "ipfs://badge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != true {
+		t.Errorf("mint_nft = %v, want true", value)
+	}
+
+	if len(fromMetadata.messages) != len(fromBindings.messages) {
+		t.Fatalf("sent %d messages, bindings sent %d", len(fromMetadata.messages), len(fromBindings.messages))
+	}
+	for i, got := range fromMetadata.messages {
+		want := fromBindings.messages[i]
+		if got.Selector != want.Selector || got.Mutates != want.Mutates || !bytes.Equal(got.Input, want.Input) {
+			t.Errorf("%s = %+v, bindings sent %+v", got.Label, got, want)
+		}
+	}
+}
+
+func TestMetadataCallerDecode(t *testing.T) {
+	metadata := loadTestMetadata(t, "bindings/attendance_nft.json")
+
+	// Ok(Some(EventInfo { name, date, location, organizer, transferable }))
+	event := []byte{0x00, 0x01}
+	for _, s := range []string{"Sub0", "2025-06-01", "Lisbon"} {
+		event = append(event, byte(len(s)<<2))
+		event = append(event, s...)
+	}
+	alice, _ := accountArg(aliceHex)
+	event = append(event, alice[:]...)
+	event = append(event, 0x01)
+
+	tests := []struct {
+		name    string
+		label   string
+		args    []interface{}
+		output  []byte
+		want    interface{}
+		wantErr error
+	}{
+		{
+			name:   "struct",
+			label:  "get_event",
+			args:   []interface{}{uint64(1)},
+			output: event,
+			want: map[string]interface{}{
+				"name":         "Sub0",
+				"date":         "2025-06-01",
+				"location":     "Lisbon",
+				"organizer":    aliceHex,
+				"transferable": true,
+			},
+		},
+		{
+			name:   "none",
+			label:  "get_event",
+			args:   []interface{}{uint64(9)},
+			output: []byte{0x00, 0x00},
+			want:   nil,
+		},
+		{
+			name:   "sequence",
+			label:  "get_owned_nfts",
+			args:   []interface{}{aliceHex},
+			output: []byte{0x00, 0x08, 0x02, 0, 0, 0, 0, 0, 0, 0, 0x05, 0, 0, 0, 0, 0, 0, 0},
+			want:   []interface{}{uint64(2), uint64(5)},
+		},
+		{
+			name:    "lang error",
+			label:   "get_event_count",
+			output:  []byte{0x01, 0x01},
+			wantErr: bindings.ErrCouldNotReadInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := &recordingExecutor{outputs: map[string][]byte{tt.label: tt.output}}
+			caller := &metadataCaller{exec: exec, metadata: metadata}
+
+			got, err := caller.call(context.Background(), tt.label, tt.args...)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetadataCallerRejectsBadArguments(t *testing.T) {
+	metadata := loadTestMetadata(t, "bindings/attendance_nft.json")
+	caller := &metadataCaller{exec: &recordingExecutor{}, metadata: metadata}
+	ctx := context.Background()
+
+	if _, err := caller.call(ctx, "get_event"); err == nil {
+		t.Error("missing argument was accepted")
+	}
+	if _, err := caller.call(ctx, "get_event", -1); err == nil {
+		t.Error("negative u64 was accepted")
+	}
+	if _, err := caller.call(ctx, "get_owned_nfts", "not an address"); err == nil {
+		t.Error("invalid address was accepted")
+	}
+	if _, err := caller.call(ctx, "PSP34::owner_of", uint64(1)); err == nil {
+		t.Error("undeclared message was accepted")
+	}
+}
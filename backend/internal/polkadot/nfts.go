@@ -0,0 +1,466 @@
+package polkadot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/signature"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types/codec"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// Chain backends an event can be minted on
+const (
+	BackendContract = "contract"
+	BackendNfts     = "pallet-nfts"
+)
+
+// pallet-nfts collection and item settings are "disabled" bit flags
+const (
+	nftsSettingTransferable uint64 = 1 << 0
+)
+
+// pallet-nfts attribute namespace for attributes set by the collection owner
+const nftsNamespaceCollectionOwner uint8 = 1
+
+// CollectionStore persists the pallet-nfts collection created for each event
+type CollectionStore interface {
+	GetCollectionID(ctx context.Context, eventID uint64) (uint32, bool, error)
+	SetCollectionID(ctx context.Context, eventID uint64, collectionID uint32) error
+}
+
+// nftsMintSettings mirrors pallet_nfts::MintSettings
+type nftsMintSettings struct {
+	MintType            types.U8 // 0 = Issuer
+	Price               types.OptionU128
+	StartBlock          types.OptionU32
+	EndBlock            types.OptionU32
+	DefaultItemSettings types.U64
+}
+
+// nftsCollectionConfig mirrors pallet_nfts::CollectionConfig
+type nftsCollectionConfig struct {
+	Settings     types.U64
+	MaxSupply    types.OptionU32
+	MintSettings nftsMintSettings
+}
+
+// nftsItemDetails holds the leading owner field of pallet_nfts::ItemDetails
+type nftsItemDetails struct {
+	Owner types.AccountID
+}
+
+// nftsMetadata holds the leading fields of pallet_nfts collection and item metadata
+type nftsMetadata struct {
+	Deposit types.U128
+	Data    types.Bytes
+}
+
+// nftsEventMetadata is the JSON stored as collection metadata for an event
+type nftsEventMetadata struct {
+	EventID      uint64 `json:"event_id"`
+	Name         string `json:"name"`
+	Date         string `json:"date"`
+	Location     string `json:"location"`
+	Transferable bool   `json:"transferable"`
+}
+
+// NftsBackend mints attendance badges with pallet-nfts (e.g. on Asset Hub).
+// Each event gets its own collection and NFT IDs are used as item IDs.
+type NftsBackend struct {
+	api         *gsrpc.SubstrateAPI
+	signer      signature.KeyringPair
+	collections CollectionStore
+	txs         *txTracker
+}
+
+// NewNftsBackend connects to a chain with pallet-nfts
+func NewNftsBackend(rpcURL, signerURI string, collections CollectionStore) (*NftsBackend, error) {
+	if collections == nil {
+		return nil, fmt.Errorf("a collection store is required")
+	}
+
+	log.Printf("Connecting to pallet-nfts chain at %s...", rpcURL)
+	api, err := gsrpc.NewSubstrateAPI(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %v", rpcURL, err)
+	}
+
+	signer, err := signature.KeyringPairFromSecret(signerURI, uint16(address.PrefixGeneric))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create signer: %v", err)
+	}
+
+	return &NftsBackend{
+		api:         api,
+		signer:      signer,
+		collections: collections,
+	}, nil
+}
+
+// CreateEvent creates a collection for the event and stores its details on chain
+func (b *NftsBackend) CreateEvent(ctx context.Context, eventID uint64, name, date, location string, transferable bool) (uint64, error) {
+	meta, err := b.api.RPC.State.GetMetadataLatest()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get metadata: %v", err)
+	}
+
+	// The new collection gets the next free ID
+	key, err := types.CreateStorageKey(meta, "Nfts", "NextCollectionId")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create storage key: %v", err)
+	}
+
+	var collectionID types.U32
+	if _, err := b.api.RPC.State.GetStorageLatest(key, &collectionID); err != nil {
+		return 0, fmt.Errorf("failed to read next collection ID: %v", err)
+	}
+
+	admin, err := types.NewMultiAddressFromAccountID(b.signer.PublicKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build admin address: %v", err)
+	}
+
+	// Soulbound events disable transfers for the whole collection
+	settings := uint64(0)
+	itemSettings := uint64(0)
+	if !transferable {
+		settings |= nftsSettingTransferable
+		itemSettings |= nftsSettingTransferable
+	}
+
+	config := nftsCollectionConfig{
+		Settings:  types.NewU64(settings),
+		MaxSupply: types.NewOptionU32Empty(),
+		MintSettings: nftsMintSettings{
+			Price:               types.NewOptionU128Empty(),
+			StartBlock:          types.NewOptionU32Empty(),
+			EndBlock:            types.NewOptionU32Empty(),
+			DefaultItemSettings: types.NewU64(itemSettings),
+		},
+	}
+
+	createCall, err := types.NewCall(meta, "Nfts.create", admin, config)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create Nfts.create call: %v", err)
+	}
+
+	metadataJSON, err := json.Marshal(nftsEventMetadata{
+		EventID:      eventID,
+		Name:         name,
+		Date:         date,
+		Location:     location,
+		Transferable: transferable,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal event metadata: %v", err)
+	}
+
+	metadataCall, err := types.NewCall(meta, "Nfts.set_collection_metadata", collectionID, types.NewBytes(metadataJSON))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create Nfts.set_collection_metadata call: %v", err)
+	}
+
+	attributeCall, err := b.attributeCall(meta, uint32(collectionID), nil, "event_id", strconv.FormatUint(eventID, 10))
+	if err != nil {
+		return 0, err
+	}
+
+	if err := b.submitBatch(ctx, meta, "Nfts.create", createCall, metadataCall, attributeCall); err != nil {
+		return 0, err
+	}
+
+	if err := b.collections.SetCollectionID(ctx, eventID, uint32(collectionID)); err != nil {
+		return 0, fmt.Errorf("failed to store collection ID: %v", err)
+	}
+
+	log.Printf("Created collection %d for event %d", collectionID, eventID)
+	return eventID, nil
+}
+
+// GetEvent reads an event from its collection metadata
+func (b *NftsBackend) GetEvent(ctx context.Context, eventID uint64) (*models.Event, error) {
+	collectionID, found, err := b.collections.GetCollectionID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+
+	meta, err := b.api.RPC.State.GetMetadataLatest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata: %v", err)
+	}
+
+	var metadata nftsMetadata
+	found, err = b.readStorage(meta, "CollectionMetadataOf", &metadata, collectionID)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	var details nftsEventMetadata
+	if err := json.Unmarshal(metadata.Data, &details); err != nil {
+		return nil, fmt.Errorf("failed to parse collection metadata: %v", err)
+	}
+
+	var owner types.AccountID
+	var collection struct{ Owner types.AccountID }
+	if found, err := b.readStorage(meta, "Collection", &collection, collectionID); err == nil && found {
+		owner = collection.Owner
+	}
+
+	return &models.Event{
+		ID:           eventID,
+		Name:         details.Name,
+		Date:         details.Date,
+		Location:     details.Location,
+		Organizer:    address.AccountID(owner).Hex(),
+		Transferable: details.Transferable,
+	}, nil
+}
+
+// MintNFT mints an item in the event's collection and sets its metadata URI.
+// The item ID is nftID, which is also the token ID it returns.
+func (b *NftsBackend) MintNFT(ctx context.Context, eventID, nftID uint64, recipient, metadataURI string) (uint64, bool, error) {
+	collectionID, err := b.collectionFor(ctx, eventID)
+	if err != nil {
+		return 0, false, err
+	}
+
+	to, _, err := address.Parse(recipient)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid recipient: %v", err)
+	}
+
+	meta, err := b.api.RPC.State.GetMetadataLatest()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get metadata: %v", err)
+	}
+
+	mintTo, err := types.NewMultiAddressFromAccountID(to[:])
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to build recipient address: %v", err)
+	}
+
+	mintCall, err := types.NewCall(meta, "Nfts.mint", types.NewU32(collectionID), types.NewU32(uint32(nftID)), mintTo, types.NewOptionBytesEmpty())
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to create Nfts.mint call: %v", err)
+	}
+
+	metadataCall, err := types.NewCall(meta, "Nfts.set_metadata", types.NewU32(collectionID), types.NewU32(uint32(nftID)), types.NewBytes([]byte(metadataURI)))
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to create Nfts.set_metadata call: %v", err)
+	}
+
+	item := uint32(nftID)
+	attributeCall, err := b.attributeCall(meta, collectionID, &item, "event_id", strconv.FormatUint(eventID, 10))
+	if err != nil {
+		return 0, false, err
+	}
+
+	if err := b.submitBatch(ctx, meta, "Nfts.mint", mintCall, metadataCall, attributeCall); err != nil {
+		return 0, false, err
+	}
+
+	return nftID, true, nil
+}
+
+// TransferNFT transfers an item to a new owner
+func (b *NftsBackend) TransferNFT(ctx context.Context, eventID, nftID uint64, to string) (bool, error) {
+	collectionID, err := b.collectionFor(ctx, eventID)
+	if err != nil {
+		return false, err
+	}
+
+	dest, _, err := address.Parse(to)
+	if err != nil {
+		return false, fmt.Errorf("invalid recipient: %v", err)
+	}
+
+	meta, err := b.api.RPC.State.GetMetadataLatest()
+	if err != nil {
+		return false, fmt.Errorf("failed to get metadata: %v", err)
+	}
+
+	destAddr, err := types.NewMultiAddressFromAccountID(dest[:])
+	if err != nil {
+		return false, fmt.Errorf("failed to build recipient address: %v", err)
+	}
+
+	call, err := types.NewCall(meta, "Nfts.transfer", types.NewU32(collectionID), types.NewU32(uint32(nftID)), destAddr)
+	if err != nil {
+		return false, fmt.Errorf("failed to create Nfts.transfer call: %v", err)
+	}
+
+	if err := b.submit(ctx, "Nfts.transfer", call); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// BurnNFT destroys an item
+func (b *NftsBackend) BurnNFT(ctx context.Context, eventID, nftID uint64) (bool, error) {
+	collectionID, err := b.collectionFor(ctx, eventID)
+	if err != nil {
+		return false, err
+	}
+
+	meta, err := b.api.RPC.State.GetMetadataLatest()
+	if err != nil {
+		return false, fmt.Errorf("failed to get metadata: %v", err)
+	}
+
+	call, err := types.NewCall(meta, "Nfts.burn", types.NewU32(collectionID), types.NewU32(uint32(nftID)))
+	if err != nil {
+		return false, fmt.Errorf("failed to create Nfts.burn call: %v", err)
+	}
+
+	if err := b.submit(ctx, "Nfts.burn", call); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// OwnerOf reads the owner of an item from storage, or "" if it doesn't exist
+func (b *NftsBackend) OwnerOf(ctx context.Context, eventID, nftID uint64) (string, error) {
+	collectionID, err := b.collectionFor(ctx, eventID)
+	if err != nil {
+		return "", err
+	}
+
+	meta, err := b.api.RPC.State.GetMetadataLatest()
+	if err != nil {
+		return "", fmt.Errorf("failed to get metadata: %v", err)
+	}
+
+	var item nftsItemDetails
+	found, err := b.readStorage(meta, "Item", &item, collectionID, uint32(nftID))
+	if err != nil || !found {
+		return "", err
+	}
+
+	return address.AccountID(item.Owner).Hex(), nil
+}
+
+// GetNFTAttribute reads a key from an item's metadata
+func (b *NftsBackend) GetNFTAttribute(ctx context.Context, eventID, nftID uint64, key string) (string, bool, error) {
+	collectionID, err := b.collectionFor(ctx, eventID)
+	if err != nil {
+		return "", false, err
+	}
+
+	meta, err := b.api.RPC.State.GetMetadataLatest()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get metadata: %v", err)
+	}
+
+	var metadata nftsMetadata
+	found, err := b.readStorage(meta, "ItemMetadataOf", &metadata, collectionID, uint32(nftID))
+	if err != nil || !found {
+		return "", false, err
+	}
+
+	if key == "metadata" {
+		return string(metadata.Data), true, nil
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal(metadata.Data, &values); err != nil {
+		return "", false, fmt.Errorf("failed to parse item metadata: %v", err)
+	}
+
+	value, exists := values[key]
+	if !exists {
+		return "", false, nil
+	}
+
+	return fmt.Sprint(value), true, nil
+}
+
+// collectionFor looks up the collection created for an event
+func (b *NftsBackend) collectionFor(ctx context.Context, eventID uint64) (uint32, error) {
+	collectionID, found, err := b.collections.GetCollectionID(ctx, eventID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get collection for event %d: %v", eventID, err)
+	}
+	if !found {
+		return 0, fmt.Errorf("event %d has no pallet-nfts collection", eventID)
+	}
+	return collectionID, nil
+}
+
+// attributeCall builds an Nfts.set_attribute call in the collection owner namespace
+func (b *NftsBackend) attributeCall(meta *types.Metadata, collectionID uint32, item *uint32, key, value string) (types.Call, error) {
+	maybeItem := types.NewOptionU32Empty()
+	if item != nil {
+		maybeItem = types.NewOptionU32(types.NewU32(*item))
+	}
+
+	call, err := types.NewCall(
+		meta,
+		"Nfts.set_attribute",
+		types.NewU32(collectionID),
+		maybeItem,
+		types.NewU8(nftsNamespaceCollectionOwner),
+		types.NewBytes([]byte(key)),
+		types.NewBytes([]byte(value)),
+	)
+	if err != nil {
+		return types.Call{}, fmt.Errorf("failed to create Nfts.set_attribute call: %v", err)
+	}
+
+	return call, nil
+}
+
+// readStorage reads a pallet-nfts storage entry keyed by the given values
+func (b *NftsBackend) readStorage(meta *types.Metadata, item string, target interface{}, keys ...uint32) (bool, error) {
+	args := make([][]byte, 0, len(keys))
+	for _, k := range keys {
+		encoded, err := codec.Encode(types.NewU32(k))
+		if err != nil {
+			return false, fmt.Errorf("failed to encode storage key: %v", err)
+		}
+		args = append(args, encoded)
+	}
+
+	key, err := types.CreateStorageKey(meta, "Nfts", item, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to create storage key for Nfts.%s: %v", item, err)
+	}
+
+	found, err := b.api.RPC.State.GetStorageLatest(key, target)
+	if err != nil {
+		return false, fmt.Errorf("failed to read Nfts.%s: %v", item, err)
+	}
+
+	return found, nil
+}
+
+// submitBatch submits several calls atomically with Utility.batch_all
+func (b *NftsBackend) submitBatch(ctx context.Context, meta *types.Metadata, name string, calls ...types.Call) error {
+	batch, err := types.NewCall(meta, "Utility.batch_all", calls)
+	if err != nil {
+		return fmt.Errorf("failed to create batch call: %v", err)
+	}
+	return b.submit(ctx, name, batch)
+}
+
+// submit signs a call and waits until it's included in a block
+func (b *NftsBackend) submit(ctx context.Context, name string, call types.Call) error {
+	ext, err := CreateSignedExtrinsic(b.api, call, b.signer)
+	if err != nil {
+		return err
+	}
+
+	_, err = submitAndWatch(ctx, b.api, ext, name, b.txs)
+	return err
+}
@@ -0,0 +1,320 @@
+package polkadot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// PSP34 message labels as they appear in ink! contract metadata
+const (
+	MethodOwnerOf            = "PSP34::owner_of"
+	MethodBalanceOf          = "PSP34::balance_of"
+	MethodTransfer           = "PSP34::transfer"
+	MethodTotalSupply        = "PSP34::total_supply"
+	MethodGetAttribute       = "PSP34Metadata::get_attribute"
+	MethodOwnersTokenByIndex = "PSP34Enumerable::owners_token_by_index"
+	MethodBurn               = "PSP34Burnable::burn"
+)
+
+// IsPSP34Metadata reports whether contract metadata exposes the PSP34 messages
+func IsPSP34Metadata(metadata *ContractMetadata) bool {
+	if metadata == nil {
+		return false
+	}
+
+	for _, message := range metadata.V1.Spec.Messages {
+		if strings.EqualFold(message.Name, MethodOwnerOf) {
+			return true
+		}
+	}
+	return metadata.ink != nil && metadata.ink.message(MethodOwnerOf) != nil
+}
+
+// LegacyAdapter exposes the PSP34 messages used by Client on top of the
+// legacy attendance contract (get_nft, get_owned_nfts, transfer). Calls to
+// any other method are passed through unchanged.
+type LegacyAdapter struct {
+	inner ContractCaller
+}
+
+// NewLegacyAdapter wraps a caller that speaks the legacy contract messages
+func NewLegacyAdapter(inner ContractCaller) *LegacyAdapter {
+	return &LegacyAdapter{inner: inner}
+}
+
+// Call translates PSP34 messages to their legacy equivalents
+func (a *LegacyAdapter) Call(ctx context.Context, method string, args ...interface{}) ([]byte, error) {
+	switch method {
+	case MethodOwnerOf:
+		if len(args) < 1 {
+			return nil, fmt.Errorf("%s requires 1 argument", method)
+		}
+
+		nft, err := a.getNFT(ctx, args[0])
+		if err != nil {
+			return nil, err
+		}
+		if nft == nil {
+			return json.Marshal(nil)
+		}
+
+		return json.Marshal(nft.Owner)
+
+	case MethodBalanceOf:
+		if len(args) < 1 {
+			return nil, fmt.Errorf("%s requires 1 argument", method)
+		}
+
+		owned, err := a.ownedNFTs(ctx, args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(uint64(len(owned)))
+
+	case MethodOwnersTokenByIndex:
+		if len(args) < 2 {
+			return nil, fmt.Errorf("%s requires 2 arguments", method)
+		}
+
+		index, err := uint64Arg(args[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid token index: %v", err)
+		}
+
+		owned, err := a.ownedNFTs(ctx, args[0])
+		if err != nil {
+			return nil, err
+		}
+		if index >= uint64(len(owned)) {
+			return json.Marshal(nil)
+		}
+
+		return json.Marshal(owned[index])
+
+	case MethodTransfer:
+		// PSP34 orders the arguments (to, id, data); the legacy message takes (nft_id, to)
+		if len(args) < 2 {
+			return nil, fmt.Errorf("%s requires 2 arguments", method)
+		}
+
+		return a.inner.Call(ctx, "transfer", args[1], args[0])
+
+	case MethodTotalSupply:
+		return a.inner.Call(ctx, "get_nft_count")
+
+	case MethodGetAttribute:
+		if len(args) < 2 {
+			return nil, fmt.Errorf("%s requires 2 arguments", method)
+		}
+
+		key, ok := args[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid attribute key type")
+		}
+
+		nft, err := a.getNFT(ctx, args[0])
+		if err != nil {
+			return nil, err
+		}
+		if nft == nil {
+			return json.Marshal(nil)
+		}
+
+		switch key {
+		case "event_id":
+			return json.Marshal(strconv.FormatUint(nft.EventID, 10))
+		case "metadata":
+			if nft.MetadataURI != "" {
+				return json.Marshal(nft.MetadataURI)
+			}
+			metadataJSON, err := json.Marshal(nft.Metadata)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal metadata: %v", err)
+			}
+			return json.Marshal(string(metadataJSON))
+		}
+
+		value, exists := nft.Metadata[key]
+		if !exists {
+			return json.Marshal(nil)
+		}
+
+		return json.Marshal(fmt.Sprint(value))
+
+	default:
+		return a.inner.Call(ctx, method, args...)
+	}
+}
+
+// getNFT reads an NFT with the legacy get_nft message
+func (a *LegacyAdapter) getNFT(ctx context.Context, id interface{}) (*models.NFT, error) {
+	result, err := a.inner.Call(ctx, "get_nft", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get NFT: %v", err)
+	}
+
+	if len(result) == 0 || string(result) == "null" {
+		return nil, nil
+	}
+
+	var nft models.NFT
+	if err := json.Unmarshal(result, &nft); err != nil {
+		return nil, fmt.Errorf("failed to parse NFT: %v", err)
+	}
+
+	return &nft, nil
+}
+
+// ownedNFTs reads an account's NFT IDs with the legacy get_owned_nfts message
+func (a *LegacyAdapter) ownedNFTs(ctx context.Context, owner interface{}) ([]uint64, error) {
+	result, err := a.inner.Call(ctx, "get_owned_nfts", owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get owned NFTs: %v", err)
+	}
+
+	var owned []uint64
+	if len(result) > 0 {
+		if err := json.Unmarshal(result, &owned); err != nil {
+			return nil, fmt.Errorf("failed to parse owned NFTs: %v", err)
+		}
+	}
+
+	sort.Slice(owned, func(i, j int) bool { return owned[i] < owned[j] })
+	return owned, nil
+}
+
+// psp34Adapter runs Client's calls against a PSP34 attendance contract. The
+// messages are encoded from the contract metadata, and their results are
+// converted to what the legacy contract returns, so Client works unchanged
+// with either contract.
+type psp34Adapter struct {
+	inner *metadataCaller
+}
+
+// newPSP34Adapter wraps a caller for a contract with PSP34 metadata
+func newPSP34Adapter(inner *metadataCaller) *psp34Adapter {
+	return &psp34Adapter{inner: inner}
+}
+
+// Call implements ContractCaller
+func (a *psp34Adapter) Call(ctx context.Context, method string, args ...interface{}) ([]byte, error) {
+	switch method {
+	case "mint_nft":
+		value, err := a.inner.call(ctx, method, args...)
+		if err != nil {
+			return nil, err
+		}
+
+		// The contract returns the ID of the token it minted
+		result := models.MintResult{Minted: true}
+		switch v := value.(type) {
+		case bool:
+			result.Minted = v
+		case uint64:
+			result.NFTID = v
+		default:
+			log.Printf("Minted token %v, which isn't a numeric ID", value)
+		}
+		return json.Marshal(result)
+
+	case "get_event":
+		if len(args) < 1 {
+			return nil, fmt.Errorf("get_event requires 1 argument")
+		}
+		eventID, err := uint64Arg(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid event ID: %v", err)
+		}
+
+		value, err := a.inner.call(ctx, method, eventID)
+		if err != nil {
+			return nil, err
+		}
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return []byte{}, nil
+		}
+
+		event := models.Event{ID: eventID}
+		event.Name, _ = fields["name"].(string)
+		event.Date, _ = fields["date"].(string)
+		event.Location, _ = fields["location"].(string)
+		event.Organizer, _ = fields["organizer"].(string)
+		event.Transferable, _ = fields["transferable"].(bool)
+		return json.Marshal(event)
+
+	case MethodTransfer:
+		if _, err := a.inner.call(ctx, method, args...); err != nil {
+			return nil, err
+		}
+		return json.Marshal(true)
+
+	case MethodOwnersTokenByIndex:
+		if len(args) < 2 {
+			return nil, fmt.Errorf("%s requires 2 arguments", method)
+		}
+
+		// The index is past the owner's last token
+		value, err := a.inner.call(ctx, method, args...)
+		var contractErr *contractError
+		if errors.As(err, &contractErr) {
+			return json.Marshal(nil)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(value)
+
+	case MethodGetAttribute:
+		if len(args) < 2 {
+			return nil, fmt.Errorf("%s requires 2 arguments", method)
+		}
+
+		value, err := a.inner.call(ctx, method, args...)
+		if err != nil {
+			return nil, err
+		}
+		if data, ok := value.([]byte); ok {
+			return json.Marshal(string(data))
+		}
+		return json.Marshal(nil)
+
+	case "burn":
+		if len(args) < 1 {
+			return nil, fmt.Errorf("burn requires 1 argument")
+		}
+
+		// PSP34Burnable takes the account to burn from
+		if !a.inner.has(method) {
+			owner, err := a.inner.call(ctx, MethodOwnerOf, args[0])
+			if err != nil {
+				return nil, err
+			}
+			if owner == nil {
+				return json.Marshal(false)
+			}
+			args = []interface{}{owner, args[0]}
+			method = MethodBurn
+		}
+
+		if _, err := a.inner.call(ctx, method, args...); err != nil {
+			return nil, err
+		}
+		return json.Marshal(true)
+
+	case "get_nft_count":
+		return a.inner.Call(ctx, MethodTotalSupply)
+
+	default:
+		return a.inner.Call(ctx, method, args...)
+	}
+}
@@ -0,0 +1,293 @@
+package polkadot
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+const bobHex = "0x8eaf04151687736326c9fea17e25fc5287613693c912909cb226aa4794f26a48"
+
+func u64LE(n uint64) []byte {
+	return binary.LittleEndian.AppendUint64(nil, n)
+}
+
+// scaleString encodes a string shorter than 64 bytes
+func scaleString(s string) []byte {
+	return append([]byte{byte(len(s) << 2)}, s...)
+}
+
+// tokenID encodes PSP34 Id::U64
+func tokenID(n uint64) []byte {
+	return append([]byte{0x03}, u64LE(n)...)
+}
+
+func account(t *testing.T, hexAddress string) []byte {
+	t.Helper()
+	id, err := accountArg(hexAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id[:]
+}
+
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func selector(t *testing.T, s string) []byte {
+	t.Helper()
+	data, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestIsPSP34Metadata(t *testing.T) {
+	legacy, err := LoadContractMetadata("bindings/attendance_nft.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if IsPSP34Metadata(legacy) {
+		t.Error("legacy metadata detected as PSP34")
+	}
+
+	psp34, err := LoadContractMetadata("testdata/psp34_attendance.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsPSP34Metadata(psp34) {
+		t.Error("PSP34 metadata not detected")
+	}
+}
+
+// TestPSP34Client runs Client against a PSP34 attendance contract, checking
+// the messages it sends and how it reads their SCALE output
+func TestPSP34Client(t *testing.T) {
+	metadata, err := LoadContractMetadata("testdata/psp34_attendance.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := account(t, aliceHex)
+	bob := account(t, bobHex)
+
+	// Ok(Some(EventInfo { name, date, location, organizer, transferable }))
+	eventInfo := concat([]byte{0x00, 0x01}, scaleString("Sub0"), scaleString("2025-06-01"), scaleString("Lisbon"), alice)
+	transferableEvent := concat(eventInfo, []byte{0x01})
+	soulboundEvent := concat(eventInfo, []byte{0x00})
+
+	tests := []struct {
+		name      string
+		outputs   map[string][]byte
+		run       func(c *Client) (interface{}, error)
+		want      interface{}
+		wantErr   string
+		wantLabel string
+		wantInput []byte
+	}{
+		{
+			name:    "create event",
+			outputs: map[string][]byte{"create_event": concat([]byte{0x00}, u64LE(4))},
+			run: func(c *Client) (interface{}, error) {
+				return c.CreateEvent(context.Background(), 0, "Sub0", "2025-06-01", "Lisbon", false)
+			},
+			want:      uint64(4),
+			wantLabel: "create_event",
+			wantInput: concat(selector(t, "0x8067c49f"), scaleString("Sub0"), scaleString("2025-06-01"), scaleString("Lisbon"), []byte{0x00}),
+		},
+		{
+			name:    "get event",
+			outputs: map[string][]byte{"get_event": transferableEvent},
+			run: func(c *Client) (interface{}, error) {
+				event, err := c.GetEvent(context.Background(), 4)
+				if err != nil || event == nil {
+					return nil, err
+				}
+				return event.ID == 4 && event.Name == "Sub0" && event.Organizer == aliceHex && event.Transferable, nil
+			},
+			want:      true,
+			wantLabel: "get_event",
+			wantInput: concat(selector(t, "0x1c7f7fa8"), u64LE(4)),
+		},
+		{
+			name:    "mint",
+			outputs: map[string][]byte{"mint_nft": concat([]byte{0x00, 0x00}, tokenID(12))},
+			run: func(c *Client) (interface{}, error) {
+				nftID, minted, err := c.MintNFT(context.Background(), 4, 0, aliceHex, "ipfs://badge")
+				if !minted {
+					return nil, err
+				}
+				return nftID, err
+			},
+			want:      uint64(12),
+			wantLabel: "mint_nft",
+			wantInput: concat(selector(t, "0x219a113e"), u64LE(4), alice, scaleString("ipfs://badge")),
+		},
+		{
+			name:    "mint rejected",
+			outputs: map[string][]byte{"mint_nft": concat([]byte{0x00, 0x01, 0x00}, scaleString("event is closed"))},
+			run: func(c *Client) (interface{}, error) {
+				_, _, err := c.MintNFT(context.Background(), 4, 0, aliceHex, "ipfs://badge")
+				return nil, err
+			},
+			wantErr: "Custom(event is closed)",
+		},
+		{
+			name:    "owner of",
+			outputs: map[string][]byte{MethodOwnerOf: concat([]byte{0x00, 0x01}, alice)},
+			run: func(c *Client) (interface{}, error) {
+				return c.OwnerOf(context.Background(), 4, 12)
+			},
+			want:      aliceHex,
+			wantLabel: MethodOwnerOf,
+			wantInput: concat(selector(t, "0x1168624d"), tokenID(12)),
+		},
+		{
+			name:    "owner of missing token",
+			outputs: map[string][]byte{MethodOwnerOf: {0x00, 0x00}},
+			run: func(c *Client) (interface{}, error) {
+				return c.OwnerOf(context.Background(), 4, 99)
+			},
+			want: "",
+		},
+		{
+			name: "transfer",
+			outputs: map[string][]byte{
+				"get_event":    transferableEvent,
+				MethodTransfer: {0x00, 0x00},
+			},
+			run: func(c *Client) (interface{}, error) {
+				return c.TransferNFT(context.Background(), 4, 12, bobHex)
+			},
+			want:      true,
+			wantLabel: MethodTransfer,
+			wantInput: concat(selector(t, "0x3128d61b"), bob, tokenID(12), []byte{0x00}),
+		},
+		{
+			name: "transfer not approved",
+			outputs: map[string][]byte{
+				"get_event":    transferableEvent,
+				MethodTransfer: {0x00, 0x01, 0x02},
+			},
+			run: func(c *Client) (interface{}, error) {
+				return c.TransferNFT(context.Background(), 4, 12, bobHex)
+			},
+			wantErr: "NotApproved",
+		},
+		{
+			name:    "transfer soulbound",
+			outputs: map[string][]byte{"get_event": soulboundEvent},
+			run: func(c *Client) (interface{}, error) {
+				return c.TransferNFT(context.Background(), 4, 12, bobHex)
+			},
+			wantErr: ErrNonTransferable.Error(),
+		},
+		{
+			name: "list owned",
+			outputs: map[string][]byte{
+				MethodBalanceOf:          {0x00, 0x01, 0, 0, 0},
+				MethodOwnersTokenByIndex: concat([]byte{0x00, 0x00}, tokenID(12)),
+			},
+			run: func(c *Client) (interface{}, error) {
+				ids, err := c.ListNFTsByOwner(context.Background(), aliceHex)
+				if len(ids) != 1 {
+					return ids, err
+				}
+				return ids[0], err
+			},
+			want:      uint64(12),
+			wantLabel: MethodOwnersTokenByIndex,
+			wantInput: concat(selector(t, "0x3bcfb511"), alice, make([]byte, 16)),
+		},
+		{
+			name:    "attribute",
+			outputs: map[string][]byte{MethodGetAttribute: concat([]byte{0x00, 0x01}, scaleString("ipfs://badge"))},
+			run: func(c *Client) (interface{}, error) {
+				value, _, err := c.GetNFTAttribute(context.Background(), 4, 12, "metadata")
+				return value, err
+			},
+			want:      "ipfs://badge",
+			wantLabel: MethodGetAttribute,
+			wantInput: concat(selector(t, "0xf19d48d1"), tokenID(12), scaleString("metadata")),
+		},
+		{
+			name: "burn",
+			outputs: map[string][]byte{
+				MethodOwnerOf: concat([]byte{0x00, 0x01}, alice),
+				MethodBurn:    {0x00, 0x00},
+			},
+			run: func(c *Client) (interface{}, error) {
+				return c.BurnNFT(context.Background(), 4, 12)
+			},
+			want:      true,
+			wantLabel: MethodBurn,
+			wantInput: concat(selector(t, "0x63c9877a"), alice, tokenID(12)),
+		},
+		{
+			name:    "could not read input",
+			outputs: map[string][]byte{MethodBalanceOf: {0x01, 0x01}},
+			run: func(c *Client) (interface{}, error) {
+				return c.BalanceOf(context.Background(), aliceHex)
+			},
+			wantErr: "could not read",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := &recordingExecutor{outputs: tt.outputs}
+			client := &Client{
+				contractCaller: newPSP34Adapter(newMetadataCaller(exec, metadata)),
+				txs:            newTxTracker(),
+			}
+
+			got, err := tt.run(client)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+
+			if tt.wantLabel == "" {
+				return
+			}
+			last := exec.messages[len(exec.messages)-1]
+			if last.Label != tt.wantLabel {
+				t.Fatalf("sent %s, want %s", last.Label, tt.wantLabel)
+			}
+			if !bytes.Equal(last.Input, tt.wantInput) {
+				t.Errorf("input = %x, want %x", last.Input, tt.wantInput)
+			}
+		})
+	}
+}
+
+func TestPSP34OwnersTokenByIndexPastEnd(t *testing.T) {
+	metadata, err := LoadContractMetadata("testdata/psp34_attendance.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Ok(Err(PSP34Error::TokenNotExists))
+	exec := &recordingExecutor{outputs: map[string][]byte{MethodOwnersTokenByIndex: {0x00, 0x01, 0x04}}}
+	adapter := newPSP34Adapter(newMetadataCaller(exec, metadata))
+
+	result, err := adapter.Call(context.Background(), MethodOwnersTokenByIndex, aliceHex, uint64(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(result) != "null" {
+		t.Errorf("result = %s, want null", result)
+	}
+}
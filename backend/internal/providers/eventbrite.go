@@ -0,0 +1,131 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// EventbriteBaseURL is the Eventbrite API
+const EventbriteBaseURL = "https://www.eventbriteapi.com/v3"
+
+// Eventbrite receives barcode check-ins from Eventbrite. Eventbrite doesn't
+// sign webhooks, so the secret is passed in the webhook URL's secret query
+// parameter, and the attendee is fetched from the API.
+type Eventbrite struct {
+	token          string
+	baseURL        string
+	walletQuestion string
+}
+
+// NewEventbrite creates the Eventbrite provider with a private API token
+func NewEventbrite(token, baseURL, walletQuestion string) *Eventbrite {
+	if baseURL == "" {
+		baseURL = EventbriteBaseURL
+	}
+	return &Eventbrite{
+		token:          token,
+		baseURL:        strings.TrimRight(baseURL, "/"),
+		walletQuestion: walletQuestion,
+	}
+}
+
+// eventbriteWebhook is an Eventbrite webhook payload
+type eventbriteWebhook struct {
+	APIURL string `json:"api_url"`
+	Config struct {
+		Action string `json:"action"`
+	} `json:"config"`
+}
+
+// eventbriteAttendee is an attendee from the Eventbrite API
+type eventbriteAttendee struct {
+	ID      string `json:"id"`
+	EventID string `json:"event_id"`
+	Profile struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	} `json:"profile"`
+	Answers []struct {
+		QuestionID string      `json:"question_id"`
+		Question   string      `json:"question"`
+		Answer     interface{} `json:"answer"`
+	} `json:"answers"`
+}
+
+// Name implements CheckInProvider
+func (p *Eventbrite) Name() string {
+	return "eventbrite"
+}
+
+// VerifyWebhook implements CheckInProvider
+func (p *Eventbrite) VerifyWebhook(r *http.Request, body []byte, secret string) error {
+	if secret == "" {
+		return ErrNoSecret
+	}
+
+	if !signatureMatches(r.URL.Query().Get("secret"), secret) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ParseCheckIn implements CheckInProvider. The event and attendee IDs come
+// from the attendee's API URL, /events/{event_id}/attendees/{attendee_id}/.
+func (p *Eventbrite) ParseCheckIn(body []byte) (*models.CheckInEvent, error) {
+	var webhook eventbriteWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		return nil, ErrInvalidPayload
+	}
+
+	if webhook.Config.Action != "barcode.checked_in" && webhook.Config.Action != "attendee.checked_in" {
+		return nil, ErrIgnored
+	}
+
+	u, err := url.Parse(webhook.APIURL)
+	if err != nil {
+		return nil, ErrInvalidPayload
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i+3 < len(parts); i++ {
+		if parts[i] == "events" && parts[i+2] == "attendees" {
+			return &models.CheckInEvent{EventID: parts[i+1], AttendeeID: parts[i+3]}, nil
+		}
+	}
+
+	return nil, ErrInvalidPayload
+}
+
+// GetAttendee implements CheckInProvider
+func (p *Eventbrite) GetAttendee(ctx context.Context, checkIn *models.CheckInEvent, walletQuestion string) (*models.Attendee, error) {
+	if walletQuestion == "" {
+		walletQuestion = p.walletQuestion
+	}
+
+	endpoint := fmt.Sprintf("%s/events/%s/attendees/%s/",
+		p.baseURL, url.PathEscape(checkIn.EventID), url.PathEscape(checkIn.AttendeeID))
+
+	var attendee eventbriteAttendee
+	if err := getJSON(ctx, endpoint, "Bearer "+p.token, &attendee); err != nil {
+		return nil, fmt.Errorf("failed to get Eventbrite attendee: %w", err)
+	}
+
+	result := &models.Attendee{
+		ID:    attendee.ID,
+		Name:  attendee.Profile.Name,
+		Email: attendee.Profile.Email,
+	}
+	for _, answer := range attendee.Answers {
+		if questionMatches(walletQuestion, answer.QuestionID, answer.Question) {
+			result.WalletAddress = answerString(answer.Answer)
+			break
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,86 @@
+package providers
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// Generic receives check-ins from any system that can post signed JSON.
+// The body is signed with a hex HMAC-SHA256 in X-Signature, optionally
+// prefixed with "sha256=", and carries the attendee.
+type Generic struct {
+	walletQuestion string
+}
+
+// NewGeneric creates the generic signed JSON provider
+func NewGeneric(walletQuestion string) *Generic {
+	return &Generic{walletQuestion: walletQuestion}
+}
+
+// genericWebhook is a generic check-in payload
+type genericWebhook struct {
+	EventID       string            `json:"event_id"`
+	AttendeeID    string            `json:"attendee_id"`
+	Timestamp     string            `json:"timestamp"`
+	Name          string            `json:"name"`
+	Email         string            `json:"email"`
+	WalletAddress string            `json:"wallet_address"`
+	Answers       map[string]string `json:"answers"`
+}
+
+// Name implements CheckInProvider
+func (p *Generic) Name() string {
+	return "generic"
+}
+
+// VerifyWebhook implements CheckInProvider
+func (p *Generic) VerifyWebhook(r *http.Request, body []byte, secret string) error {
+	if secret == "" {
+		return ErrNoSecret
+	}
+
+	signature := strings.TrimPrefix(r.Header.Get("X-Signature"), "sha256=")
+	if !signatureMatches(strings.ToLower(signature), hex.EncodeToString(hmacSHA256(secret, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ParseCheckIn implements CheckInProvider
+func (p *Generic) ParseCheckIn(body []byte) (*models.CheckInEvent, error) {
+	var webhook genericWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil || webhook.EventID == "" || webhook.AttendeeID == "" {
+		return nil, ErrInvalidPayload
+	}
+
+	return &models.CheckInEvent{
+		EventID:    webhook.EventID,
+		AttendeeID: webhook.AttendeeID,
+		Timestamp:  webhook.Timestamp,
+		Attendee: &models.Attendee{
+			ID:            webhook.AttendeeID,
+			Name:          webhook.Name,
+			Email:         webhook.Email,
+			WalletAddress: strings.TrimSpace(webhook.WalletAddress),
+		},
+		Answers: webhook.Answers,
+	}, nil
+}
+
+// GetAttendee implements CheckInProvider. A wallet_address in the payload
+// takes precedence over the answer to the wallet question.
+func (p *Generic) GetAttendee(ctx context.Context, checkIn *models.CheckInEvent, walletQuestion string) (*models.Attendee, error) {
+	if checkIn.Attendee != nil && checkIn.Attendee.WalletAddress != "" {
+		return checkIn.Attendee, nil
+	}
+
+	if walletQuestion == "" {
+		walletQuestion = p.walletQuestion
+	}
+	return payloadAttendee(checkIn, walletQuestion)
+}
@@ -0,0 +1,73 @@
+package providers
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/luma"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// Luma receives check-ins from Luma. Its payload is the check-in as is,
+// signed with a hex HMAC-SHA256 in X-Luma-Signature.
+type Luma struct {
+	client *luma.Client
+}
+
+// NewLuma creates the Luma provider
+func NewLuma(client *luma.Client) *Luma {
+	return &Luma{client: client}
+}
+
+// Name implements CheckInProvider
+func (p *Luma) Name() string {
+	return "luma"
+}
+
+// VerifyWebhook implements CheckInProvider
+func (p *Luma) VerifyWebhook(r *http.Request, body []byte, secret string) error {
+	if secret == "" {
+		return ErrNoSecret
+	}
+
+	if !signatureMatches(r.Header.Get("X-Luma-Signature"), hex.EncodeToString(hmacSHA256(secret, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ParseCheckIn implements CheckInProvider
+func (p *Luma) ParseCheckIn(body []byte) (*models.CheckInEvent, error) {
+	var checkIn models.CheckInEvent
+	if err := json.Unmarshal(body, &checkIn); err != nil || checkIn.EventID == "" || checkIn.AttendeeID == "" {
+		return nil, ErrInvalidPayload
+	}
+	return &checkIn, nil
+}
+
+// GetAttendee implements CheckInProvider. Without an API key it fails with
+// luma.ErrNotConfigured.
+func (p *Luma) GetAttendee(ctx context.Context, checkIn *models.CheckInEvent, walletQuestion string) (*models.Attendee, error) {
+	if walletQuestion == "" {
+		return p.client.GetAttendee(ctx, checkIn.EventID, checkIn.AttendeeID)
+	}
+
+	guest, err := p.client.GetGuest(ctx, checkIn.EventID, checkIn.AttendeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Attendee{
+		ID:            guest.ID,
+		Name:          guest.Name,
+		Email:         guest.Email,
+		WalletAddress: guest.AnswerTo(walletQuestion),
+	}, nil
+}
+
+// Client returns the Luma API client
+func (p *Luma) Client() *luma.Client {
+	return p.client
+}
@@ -0,0 +1,150 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// PretixBaseURL is the hosted pretix instance
+const PretixBaseURL = "https://pretix.eu"
+
+// Pretix receives check-ins from pretix. Pretix doesn't sign webhooks, so
+// the secret is passed in the webhook URL's secret query parameter. Events
+// are identified as organizer/event, and the attendee is fetched from the
+// order that checked in.
+type Pretix struct {
+	token          string
+	baseURL        string
+	walletQuestion string
+}
+
+// NewPretix creates the pretix provider with an API token for baseURL
+func NewPretix(token, baseURL, walletQuestion string) *Pretix {
+	if baseURL == "" {
+		baseURL = PretixBaseURL
+	}
+	return &Pretix{
+		token:          token,
+		baseURL:        strings.TrimRight(baseURL, "/"),
+		walletQuestion: walletQuestion,
+	}
+}
+
+// pretixWebhook is a pretix webhook payload
+type pretixWebhook struct {
+	Organizer string `json:"organizer"`
+	Event     string `json:"event"`
+	Code      string `json:"code"`
+	Action    string `json:"action"`
+}
+
+// pretixOrder is an order from the pretix API
+type pretixOrder struct {
+	Code      string `json:"code"`
+	Email     string `json:"email"`
+	Positions []struct {
+		ID            int64  `json:"id"`
+		AttendeeName  string `json:"attendee_name"`
+		AttendeeEmail string `json:"attendee_email"`
+		Answers       []struct {
+			Question           int64       `json:"question"`
+			QuestionIdentifier string      `json:"question_identifier"`
+			Answer             interface{} `json:"answer"`
+		} `json:"answers"`
+		Checkins []json.RawMessage `json:"checkins"`
+	} `json:"positions"`
+}
+
+// Name implements CheckInProvider
+func (p *Pretix) Name() string {
+	return "pretix"
+}
+
+// VerifyWebhook implements CheckInProvider
+func (p *Pretix) VerifyWebhook(r *http.Request, body []byte, secret string) error {
+	if secret == "" {
+		return ErrNoSecret
+	}
+
+	if !signatureMatches(r.URL.Query().Get("secret"), secret) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ParseCheckIn implements CheckInProvider
+func (p *Pretix) ParseCheckIn(body []byte) (*models.CheckInEvent, error) {
+	var webhook pretixWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		return nil, ErrInvalidPayload
+	}
+
+	if webhook.Action != "pretix.event.checkin" {
+		return nil, ErrIgnored
+	}
+
+	if webhook.Organizer == "" || webhook.Event == "" || webhook.Code == "" {
+		return nil, ErrInvalidPayload
+	}
+
+	return &models.CheckInEvent{
+		EventID:    webhook.Organizer + "/" + webhook.Event,
+		AttendeeID: webhook.Code,
+	}, nil
+}
+
+// GetAttendee implements CheckInProvider. Orders can hold several tickets;
+// the attendee is the first position that has checked in.
+func (p *Pretix) GetAttendee(ctx context.Context, checkIn *models.CheckInEvent, walletQuestion string) (*models.Attendee, error) {
+	if walletQuestion == "" {
+		walletQuestion = p.walletQuestion
+	}
+
+	organizer, event, ok := strings.Cut(checkIn.EventID, "/")
+	if !ok {
+		return nil, fmt.Errorf("%w: pretix event must be organizer/event", ErrNotFound)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v1/organizers/%s/events/%s/orders/%s/",
+		p.baseURL, url.PathEscape(organizer), url.PathEscape(event), url.PathEscape(checkIn.AttendeeID))
+
+	var order pretixOrder
+	if err := getJSON(ctx, endpoint, "Token "+p.token, &order); err != nil {
+		return nil, fmt.Errorf("failed to get pretix order: %w", err)
+	}
+	if len(order.Positions) == 0 {
+		return nil, fmt.Errorf("%w: pretix order %s has no tickets", ErrNotFound, order.Code)
+	}
+
+	position := order.Positions[0]
+	for _, candidate := range order.Positions {
+		if len(candidate.Checkins) > 0 {
+			position = candidate
+			break
+		}
+	}
+
+	attendee := &models.Attendee{
+		ID:    order.Code + "-" + strconv.FormatInt(position.ID, 10),
+		Name:  position.AttendeeName,
+		Email: position.AttendeeEmail,
+	}
+	if attendee.Email == "" {
+		attendee.Email = order.Email
+	}
+	for _, answer := range position.Answers {
+		if questionMatches(walletQuestion, answer.QuestionIdentifier, strconv.FormatInt(answer.Question, 10)) {
+			attendee.WalletAddress = answerString(answer.Answer)
+			break
+		}
+	}
+
+	return attendee, nil
+}
@@ -0,0 +1,188 @@
+// Package providers adapts the check-in webhooks of ticketing providers to
+// the check-ins that mint attendance NFTs
+package providers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// Errors returned by providers
+var (
+	ErrInvalidSignature = errors.New("webhook signature is missing or invalid")
+	ErrNoSecret         = errors.New("no webhook secret is configured")
+	ErrInvalidPayload   = errors.New("webhook payload is invalid")
+	// ErrIgnored is returned for webhooks that aren't check-ins, such as
+	// registrations or reverted check-ins
+	ErrIgnored     = errors.New("webhook is not a check-in")
+	ErrNotFound    = errors.New("attendee not found")
+	ErrUnavailable = errors.New("provider API request failed")
+)
+
+// CheckInProvider is a ticketing provider whose check-in webhooks mint NFTs
+type CheckInProvider interface {
+	// Name identifies the provider in webhook URLs and event sources
+	Name() string
+	// VerifyWebhook checks that a webhook was sent by the provider. Without
+	// a secret it fails with ErrNoSecret.
+	VerifyWebhook(r *http.Request, body []byte, secret string) error
+	// ParseCheckIn normalizes a webhook payload. It returns ErrIgnored for
+	// webhooks that aren't check-ins.
+	ParseCheckIn(body []byte) (*models.CheckInEvent, error)
+	// GetAttendee gets the attendee who checked in, with their answer to the
+	// wallet question as their wallet address. A non-empty walletQuestion
+	// overrides the provider's configured question.
+	GetAttendee(ctx context.Context, checkIn *models.CheckInEvent, walletQuestion string) (*models.Attendee, error)
+}
+
+// Registry holds the providers webhooks can come from, with the webhook
+// secret used for events that don't set their own
+type Registry struct {
+	providers     map[string]CheckInProvider
+	secrets       map[string]string
+	allowUnsigned bool
+}
+
+// NewRegistry creates an empty provider registry. allowUnsigned accepts
+// webhooks without a secret to check them with, which is only meant for
+// development.
+func NewRegistry(allowUnsigned bool) *Registry {
+	return &Registry{
+		providers:     make(map[string]CheckInProvider),
+		secrets:       make(map[string]string),
+		allowUnsigned: allowUnsigned,
+	}
+}
+
+// Register adds a provider with its default webhook secret
+func (r *Registry) Register(provider CheckInProvider, secret string) {
+	r.providers[provider.Name()] = provider
+	r.secrets[provider.Name()] = secret
+}
+
+// Get gets a provider by name, with its default webhook secret
+func (r *Registry) Get(name string) (CheckInProvider, string, bool) {
+	provider, ok := r.providers[name]
+	return provider, r.secrets[name], ok
+}
+
+// Verify checks a webhook with its provider. Webhooks without a secret are
+// rejected unless the registry allows unsigned webhooks.
+func (r *Registry) Verify(provider CheckInProvider, req *http.Request, body []byte, secret string) error {
+	if secret == "" && r.allowUnsigned {
+		return nil
+	}
+	return provider.VerifyWebhook(req, body, secret)
+}
+
+// Names lists the registered providers
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// signatureMatches compares a received signature to the expected one in
+// constant time
+func signatureMatches(received, expected string) bool {
+	return received != "" && hmac.Equal([]byte(received), []byte(expected))
+}
+
+// hmacSHA256 computes the HMAC-SHA256 of a webhook body
+func hmacSHA256(secret string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// questionMatches reports whether a registration question is the one named
+// by question, by ID or case-insensitively by label
+func questionMatches(question string, ids ...string) bool {
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return false
+	}
+	for _, id := range ids {
+		if id != "" && strings.EqualFold(strings.TrimSpace(id), question) {
+			return true
+		}
+	}
+	return false
+}
+
+// apiClient is the HTTP client for provider APIs
+var apiClient = &http.Client{Timeout: 15 * time.Second}
+
+// getJSON fetches a provider API resource and decodes it into out
+func getJSON(ctx context.Context, url, authorization string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := apiClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%w: status %d: %s", ErrUnavailable, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// payloadAttendee gets the attendee carried by a check-in's payload, taking
+// their wallet address from their answer to walletQuestion if they have one
+func payloadAttendee(checkIn *models.CheckInEvent, walletQuestion string) (*models.Attendee, error) {
+	if checkIn.Attendee == nil {
+		return nil, ErrNotFound
+	}
+
+	attendee := *checkIn.Attendee
+	for question, answer := range checkIn.Answers {
+		if questionMatches(walletQuestion, question) {
+			attendee.WalletAddress = answer
+			break
+		}
+	}
+
+	return &attendee, nil
+}
+
+// answerString formats a registration answer of any JSON type
+func answerString(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(value)
+	default:
+		return strings.TrimSpace(fmt.Sprint(value))
+	}
+}
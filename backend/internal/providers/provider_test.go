@@ -0,0 +1,105 @@
+package providers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+const testSecret = "webhook-secret"
+
+var testBody = []byte(`{"event_id":"evt-1","attendee_id":"att-1"}`)
+
+func hexSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func base64Signature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider CheckInProvider
+		target   string
+		header   string
+		value    string
+		secret   string
+		want     error
+	}{
+		{"luma signed", &Luma{}, "/", "X-Luma-Signature", hexSignature(testSecret, testBody), testSecret, nil},
+		{"luma wrong secret", &Luma{}, "/", "X-Luma-Signature", hexSignature("other", testBody), testSecret, ErrInvalidSignature},
+		{"luma unsigned", &Luma{}, "/", "", "", testSecret, ErrInvalidSignature},
+		{"luma no secret", &Luma{}, "/", "X-Luma-Signature", hexSignature("", testBody), "", ErrNoSecret},
+
+		{"generic signed", &Generic{}, "/", "X-Signature", "sha256=" + hexSignature(testSecret, testBody), testSecret, nil},
+		{"generic bare signature", &Generic{}, "/", "X-Signature", hexSignature(testSecret, testBody), testSecret, nil},
+		{"generic wrong secret", &Generic{}, "/", "X-Signature", "sha256=" + hexSignature("other", testBody), testSecret, ErrInvalidSignature},
+		{"generic no secret", &Generic{}, "/", "", "", "", ErrNoSecret},
+
+		{"tito signed", &Tito{}, "/", "Tito-Signature", base64Signature(testSecret, testBody), testSecret, nil},
+		{"tito hex signature", &Tito{}, "/", "Tito-Signature", hexSignature(testSecret, testBody), testSecret, ErrInvalidSignature},
+		{"tito no secret", &Tito{}, "/", "", "", "", ErrNoSecret},
+
+		{"eventbrite secret", &Eventbrite{}, "/?secret=" + testSecret, "", "", testSecret, nil},
+		{"eventbrite wrong secret", &Eventbrite{}, "/?secret=other", "", "", testSecret, ErrInvalidSignature},
+		{"eventbrite missing secret", &Eventbrite{}, "/", "", "", testSecret, ErrInvalidSignature},
+		{"eventbrite no secret", &Eventbrite{}, "/?secret=", "", "", "", ErrNoSecret},
+
+		{"pretix secret", &Pretix{}, "/?secret=" + testSecret, "", "", testSecret, nil},
+		{"pretix wrong secret", &Pretix{}, "/?secret=other", "", "", testSecret, ErrInvalidSignature},
+		{"pretix no secret", &Pretix{}, "/", "", "", "", ErrNoSecret},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+
+			err := tt.provider.VerifyWebhook(req, testBody, tt.secret)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("VerifyWebhook = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsTamperedBody(t *testing.T) {
+	req := httptest.NewRequest("POST", "/", nil)
+	req.Header.Set("X-Luma-Signature", hexSignature(testSecret, testBody))
+
+	tampered := append([]byte(nil), testBody...)
+	tampered[len(tampered)-2] = 'X'
+	if err := (&Luma{}).VerifyWebhook(req, tampered, testSecret); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifyWebhook of a tampered body = %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+func TestRegistryVerify(t *testing.T) {
+	req := httptest.NewRequest("POST", "/", nil)
+
+	strict := NewRegistry(false)
+	if err := strict.Verify(&Luma{}, req, testBody, ""); !errors.Is(err, ErrNoSecret) {
+		t.Errorf("Verify without a secret = %v, want %v", err, ErrNoSecret)
+	}
+
+	lenient := NewRegistry(true)
+	if err := lenient.Verify(&Luma{}, req, testBody, ""); err != nil {
+		t.Errorf("Verify without a secret when unsigned webhooks are allowed = %v", err)
+	}
+	// A configured secret is still checked
+	if err := lenient.Verify(&Luma{}, req, testBody, testSecret); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify of an unsigned webhook with a secret = %v, want %v", err, ErrInvalidSignature)
+	}
+}
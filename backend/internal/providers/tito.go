@@ -0,0 +1,99 @@
+package providers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// Tito receives check-ins from Tito. Webhooks are signed with a base64
+// HMAC-SHA256 in Tito-Signature and carry the ticket, so the API isn't
+// needed. Events are identified as account/event.
+type Tito struct {
+	walletQuestion string
+}
+
+// NewTito creates the Tito provider
+func NewTito(walletQuestion string) *Tito {
+	return &Tito{walletQuestion: walletQuestion}
+}
+
+// titoWebhook is a Tito checkin.created webhook payload
+type titoWebhook struct {
+	Type      string `json:"_type"`
+	CreatedAt string `json:"created_at"`
+	Event     struct {
+		Slug        string `json:"slug"`
+		AccountSlug string `json:"account_slug"`
+	} `json:"event"`
+	Ticket struct {
+		Slug      string `json:"slug"`
+		Reference string `json:"reference"`
+		Name      string `json:"name"`
+		Email     string `json:"email"`
+		// Responses maps question slugs to the ticket holder's answers
+		Responses map[string]interface{} `json:"responses"`
+	} `json:"ticket"`
+}
+
+// Name implements CheckInProvider
+func (p *Tito) Name() string {
+	return "tito"
+}
+
+// VerifyWebhook implements CheckInProvider
+func (p *Tito) VerifyWebhook(r *http.Request, body []byte, secret string) error {
+	if secret == "" {
+		return ErrNoSecret
+	}
+
+	if !signatureMatches(r.Header.Get("Tito-Signature"), base64.StdEncoding.EncodeToString(hmacSHA256(secret, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ParseCheckIn implements CheckInProvider
+func (p *Tito) ParseCheckIn(body []byte) (*models.CheckInEvent, error) {
+	var webhook titoWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		return nil, ErrInvalidPayload
+	}
+
+	if webhook.Type != "checkin" {
+		return nil, ErrIgnored
+	}
+
+	if webhook.Event.AccountSlug == "" || webhook.Event.Slug == "" || webhook.Ticket.Slug == "" {
+		return nil, ErrInvalidPayload
+	}
+
+	answers := make(map[string]string, len(webhook.Ticket.Responses))
+	for question, answer := range webhook.Ticket.Responses {
+		answers[question] = answerString(answer)
+	}
+
+	return &models.CheckInEvent{
+		EventID:    webhook.Event.AccountSlug + "/" + webhook.Event.Slug,
+		AttendeeID: webhook.Ticket.Slug,
+		Timestamp:  webhook.CreatedAt,
+		Attendee: &models.Attendee{
+			ID:    webhook.Ticket.Slug,
+			Name:  strings.TrimSpace(webhook.Ticket.Name),
+			Email: webhook.Ticket.Email,
+		},
+		Answers: answers,
+	}, nil
+}
+
+// GetAttendee implements CheckInProvider
+func (p *Tito) GetAttendee(ctx context.Context, checkIn *models.CheckInEvent, walletQuestion string) (*models.Attendee, error) {
+	if walletQuestion == "" {
+		walletQuestion = p.walletQuestion
+	}
+	return payloadAttendee(checkIn, walletQuestion)
+}
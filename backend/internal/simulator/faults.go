@@ -0,0 +1,91 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Fault is a way an extrinsic can fail before it's included in a block
+type Fault string
+
+// Faults that can be injected
+const (
+	// FaultDropped simulates an extrinsic dropped from the transaction pool
+	FaultDropped Fault = "dropped"
+	// FaultInvalid simulates an extrinsic rejected as invalid
+	FaultInvalid Fault = "invalid"
+)
+
+// Errors returned for injected faults
+var (
+	ErrDropped = errors.New("extrinsic failed: dropped")
+	ErrInvalid = errors.New("extrinsic failed: invalid")
+)
+
+// fault is a pending injected fault
+type fault struct {
+	kind   Fault
+	method string // "" matches any state-changing message
+	count  int
+}
+
+// InjectFault makes the next count extrinsics calling method fail with the
+// given fault, leaving the state untouched. An empty method matches every
+// state-changing message. Faults are consumed in the order they're injected.
+func (s *Simulator) InjectFault(kind Fault, method string, count int) error {
+	switch kind {
+	case FaultDropped, FaultInvalid:
+	default:
+		return fmt.Errorf("unknown fault %q", kind)
+	}
+
+	if count <= 0 {
+		return fmt.Errorf("fault count must be positive")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.faults = append(s.faults, fault{kind: kind, method: method, count: count})
+	return nil
+}
+
+// ClearFaults removes all pending faults
+func (s *Simulator) ClearFaults() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.faults = nil
+}
+
+// SetLatency delays every call by d, simulating block time and RPC round trips
+func (s *Simulator) SetLatency(d time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.latency = d
+}
+
+// takeFault consumes the first pending fault matching method; the caller must hold the mutex
+func (s *Simulator) takeFault(method string) error {
+	for i := range s.faults {
+		f := &s.faults[i]
+		if f.method != "" && f.method != method {
+			continue
+		}
+
+		kind := f.kind
+		f.count--
+		if f.count == 0 {
+			s.faults = append(s.faults[:i], s.faults[i+1:]...)
+		}
+
+		if kind == FaultDropped {
+			return ErrDropped
+		}
+		return ErrInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,597 @@
+// Package simulator provides a deterministic in-memory copy of the
+// attendance NFT contract. It speaks the same messages as the contract
+// (create_event, mint_nft, transfer, burn and the getters), enforces the
+// same organizer/owner authorization and emits the same contract events,
+// so it can stand in for a chain in tests and local development.
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/address"
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// DefaultAccount is the development account (//Alice) the backend signs with
+const DefaultAccount = "0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d"
+
+// Contract event names, matching the #[ink(event)] structs
+const (
+	EventCreated   = "EventCreated"
+	NFTMinted      = "NFTMinted"
+	NFTTransferred = "NFTTransferred"
+	NFTBurned      = "NFTBurned"
+)
+
+// Config configures a simulator
+type Config struct {
+	// Owner is the account that instantiated the contract (defaults to DefaultAccount)
+	Owner string
+	// Caller is the account signing calls (defaults to Owner)
+	Caller string
+	// Seed creates a demo event owned by Owner
+	Seed bool
+	// Verbose logs every call
+	Verbose bool
+}
+
+// ContractEvent is an event emitted by the contract
+type ContractEvent struct {
+	Block     uint64 `json:"block"`
+	Name      string `json:"name"`
+	EventID   uint64 `json:"event_id,omitempty"`
+	NFTID     uint64 `json:"nft_id,omitempty"`
+	Organizer string `json:"organizer,omitempty"`
+	Recipient string `json:"recipient,omitempty"`
+	From      string `json:"from,omitempty"`
+	To        string `json:"to,omitempty"`
+	Owner     string `json:"owner,omitempty"`
+}
+
+// state is the contract storage plus chain bookkeeping
+type state struct {
+	events     map[uint64]models.Event
+	nfts       map[uint64]models.NFT
+	ownedNFTs  map[string][]uint64
+	eventCount uint64
+	nftCount   uint64
+	block      uint64
+	emitted    []ContractEvent
+}
+
+// clone deep-copies the state
+func (s *state) clone() *state {
+	copied := &state{
+		events:     make(map[uint64]models.Event, len(s.events)),
+		nfts:       make(map[uint64]models.NFT, len(s.nfts)),
+		ownedNFTs:  make(map[string][]uint64, len(s.ownedNFTs)),
+		eventCount: s.eventCount,
+		nftCount:   s.nftCount,
+		block:      s.block,
+		emitted:    append([]ContractEvent(nil), s.emitted...),
+	}
+
+	for id, event := range s.events {
+		copied.events[id] = event
+	}
+	for id, nft := range s.nfts {
+		if nft.Metadata != nil {
+			metadata := make(map[string]interface{}, len(nft.Metadata))
+			for k, v := range nft.Metadata {
+				metadata[k] = v
+			}
+			nft.Metadata = metadata
+		}
+		copied.nfts[id] = nft
+	}
+	for owner, ids := range s.ownedNFTs {
+		copied.ownedNFTs[owner] = append([]uint64(nil), ids...)
+	}
+
+	return copied
+}
+
+// Snapshot is a saved simulator state that can be restored later
+type Snapshot struct {
+	state *state
+}
+
+// Block returns the block number the snapshot was taken at
+func (s Snapshot) Block() uint64 {
+	if s.state == nil {
+		return 0
+	}
+	return s.state.block
+}
+
+// Simulator is an in-memory attendance NFT contract
+type Simulator struct {
+	config      Config
+	owner       string
+	caller      string
+	state       *state
+	faults      []fault
+	latency     time.Duration
+	subscribers map[int]chan ContractEvent
+	nextSub     int
+	mutex       sync.Mutex
+}
+
+// New creates a simulator in its initial state
+func New(cfg Config) *Simulator {
+	s := &Simulator{
+		config:      cfg,
+		subscribers: make(map[int]chan ContractEvent),
+	}
+	s.reset()
+	return s
+}
+
+// Reset restores the initial state and clears injected faults and latency
+func (s *Simulator) Reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.reset()
+}
+
+// reset restores the initial state; the caller must hold the mutex
+func (s *Simulator) reset() {
+	s.owner = canonicalOr(s.config.Owner, DefaultAccount)
+	s.caller = canonicalOr(s.config.Caller, s.owner)
+	s.faults = nil
+	s.latency = 0
+	s.state = &state{
+		events:    make(map[uint64]models.Event),
+		nfts:      make(map[uint64]models.NFT),
+		ownedNFTs: make(map[string][]uint64),
+	}
+
+	if s.config.Seed {
+		s.state.eventCount = 1
+		s.state.events[1] = models.Event{
+			ID:           1,
+			Name:         "Polkadot Meetup",
+			Date:         "2023-06-01",
+			Location:     "Berlin",
+			Organizer:    s.owner,
+			Transferable: true,
+		}
+	}
+}
+
+// Snapshot saves the current contract state, block number and event log
+func (s *Simulator) Snapshot() Snapshot {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return Snapshot{state: s.state.clone()}
+}
+
+// Restore rolls the simulator back to a snapshot
+func (s *Simulator) Restore(snapshot Snapshot) error {
+	if snapshot.state == nil {
+		return fmt.Errorf("empty snapshot")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.state = snapshot.state.clone()
+	return nil
+}
+
+// SetCaller changes the account that signs subsequent calls
+func (s *Simulator) SetCaller(account string) error {
+	canonical, err := address.Canonical(account)
+	if err != nil {
+		return fmt.Errorf("invalid caller: %w", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.caller = canonical
+	return nil
+}
+
+// Caller returns the account that signs calls
+func (s *Simulator) Caller() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.caller
+}
+
+// BlockNumber returns the current block; every successful state change produces a block
+func (s *Simulator) BlockNumber() uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.state.block
+}
+
+// Events returns every contract event emitted so far
+func (s *Simulator) Events() []ContractEvent {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]ContractEvent(nil), s.state.emitted...)
+}
+
+// Subscribe streams contract events as they're emitted.
+// The returned function ends the subscription and closes the channel.
+func (s *Simulator) Subscribe(buffer int) (<-chan ContractEvent, func()) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ch := make(chan ContractEvent, buffer)
+	id := s.nextSub
+	s.nextSub++
+	s.subscribers[id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+
+			delete(s.subscribers, id)
+			close(ch)
+		})
+	}
+}
+
+// Call executes a contract message. It satisfies polkadot.ContractCaller.
+// A cancelled context aborts the call before it touches the state.
+func (s *Simulator) Call(ctx context.Context, method string, args ...interface{}) ([]byte, error) {
+	s.mutex.Lock()
+	latency := s.latency
+	s.mutex.Unlock()
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.config.Verbose {
+		log.Printf("Simulator: %s with %d args", method, len(args))
+	}
+
+	if isStateChanging(method) {
+		if err := s.takeFault(method); err != nil {
+			return nil, err
+		}
+	}
+
+	switch method {
+	case "create_event":
+		return s.createEvent(args)
+	case "mint_nft":
+		return s.mintNFT(args)
+	case "transfer":
+		return s.transfer(args)
+	case "burn":
+		return s.burn(args)
+	case "get_event":
+		return s.getEvent(args)
+	case "get_nft":
+		return s.getNFT(args)
+	case "get_owned_nfts":
+		return s.getOwnedNFTs(args)
+	case "get_event_count":
+		return json.Marshal(s.state.eventCount)
+	case "get_nft_count":
+		return json.Marshal(s.state.nftCount)
+	default:
+		return nil, fmt.Errorf("unknown method: %s", method)
+	}
+}
+
+// isStateChanging reports whether a message is submitted as an extrinsic
+func isStateChanging(method string) bool {
+	switch method {
+	case "create_event", "mint_nft", "transfer", "burn":
+		return true
+	}
+	return false
+}
+
+// createEvent registers an event organized by the caller
+func (s *Simulator) createEvent(args []interface{}) ([]byte, error) {
+	if len(args) < 4 {
+		return nil, fmt.Errorf("create_event requires 4 arguments")
+	}
+
+	name, ok1 := args[0].(string)
+	date, ok2 := args[1].(string)
+	location, ok3 := args[2].(string)
+	transferable, ok4 := args[3].(bool)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return nil, fmt.Errorf("invalid argument types")
+	}
+
+	s.state.eventCount++
+	eventID := s.state.eventCount
+
+	s.state.events[eventID] = models.Event{
+		ID:           eventID,
+		Name:         name,
+		Date:         date,
+		Location:     location,
+		Organizer:    s.caller,
+		Transferable: transferable,
+	}
+
+	s.emit(ContractEvent{Name: EventCreated, EventID: eventID, Organizer: s.caller})
+
+	return json.Marshal(eventID)
+}
+
+// mintNFT mints an NFT if the caller organizes the event or owns the contract
+func (s *Simulator) mintNFT(args []interface{}) ([]byte, error) {
+	if len(args) < 3 {
+		return nil, fmt.Errorf("mint_nft requires 3 arguments")
+	}
+
+	eventID, err := uint64Arg(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid event ID: %v", err)
+	}
+
+	recipient, err := accountArg(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient: %v", err)
+	}
+
+	metadataArg, ok := args[2].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid metadata type")
+	}
+
+	event, exists := s.state.events[eventID]
+	if !exists {
+		return json.Marshal(models.MintResult{})
+	}
+
+	// Only the event organizer or the contract owner can mint
+	if s.caller != event.Organizer && s.caller != s.owner {
+		return json.Marshal(models.MintResult{})
+	}
+
+	s.state.nftCount++
+	nftID := s.state.nftCount
+
+	// Like the contract, store the metadata as given: older mints pass JSON,
+	// newer ones a URI
+	nft := models.NFT{
+		ID:      nftID,
+		EventID: eventID,
+		Owner:   recipient,
+	}
+	if err := json.Unmarshal([]byte(metadataArg), &nft.Metadata); err != nil {
+		nft.Metadata = nil
+		nft.MetadataURI = metadataArg
+	}
+	s.state.nfts[nftID] = nft
+	s.state.ownedNFTs[recipient] = append(s.state.ownedNFTs[recipient], nftID)
+
+	s.emit(ContractEvent{Name: NFTMinted, NFTID: nftID, Recipient: recipient, EventID: eventID})
+
+	return json.Marshal(models.MintResult{Minted: true, NFTID: nftID})
+}
+
+// transfer moves an NFT of a transferable event to a new owner
+func (s *Simulator) transfer(args []interface{}) ([]byte, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("transfer requires 2 arguments")
+	}
+
+	nftID, err := uint64Arg(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid NFT ID: %v", err)
+	}
+
+	to, err := accountArg(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient: %v", err)
+	}
+
+	nft, exists := s.state.nfts[nftID]
+	if !exists || !s.canManage(nft) {
+		return json.Marshal(false)
+	}
+
+	// Soulbound badges never change hands
+	if event, exists := s.state.events[nft.EventID]; !exists || !event.Transferable {
+		return json.Marshal(false)
+	}
+
+	from := nft.Owner
+	s.removeOwned(from, nftID)
+	s.state.ownedNFTs[to] = append(s.state.ownedNFTs[to], nftID)
+
+	nft.Owner = to
+	s.state.nfts[nftID] = nft
+
+	s.emit(ContractEvent{Name: NFTTransferred, NFTID: nftID, From: from, To: to})
+
+	return json.Marshal(true)
+}
+
+// burn destroys an NFT
+func (s *Simulator) burn(args []interface{}) ([]byte, error) {
+	if len(args) < 1 {
+		return nil, fmt.Errorf("burn requires 1 argument")
+	}
+
+	nftID, err := uint64Arg(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid NFT ID: %v", err)
+	}
+
+	nft, exists := s.state.nfts[nftID]
+	if !exists || !s.canManage(nft) {
+		return json.Marshal(false)
+	}
+
+	s.removeOwned(nft.Owner, nftID)
+	delete(s.state.nfts, nftID)
+
+	s.emit(ContractEvent{Name: NFTBurned, NFTID: nftID, Owner: nft.Owner})
+
+	return json.Marshal(true)
+}
+
+// getEvent returns an event, or an empty result if it doesn't exist
+func (s *Simulator) getEvent(args []interface{}) ([]byte, error) {
+	if len(args) < 1 {
+		return nil, fmt.Errorf("get_event requires 1 argument")
+	}
+
+	eventID, err := uint64Arg(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid event ID: %v", err)
+	}
+
+	event, exists := s.state.events[eventID]
+	if !exists {
+		return []byte{}, nil
+	}
+
+	return json.Marshal(event)
+}
+
+// getNFT returns an NFT, or an empty result if it doesn't exist
+func (s *Simulator) getNFT(args []interface{}) ([]byte, error) {
+	if len(args) < 1 {
+		return nil, fmt.Errorf("get_nft requires 1 argument")
+	}
+
+	nftID, err := uint64Arg(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid NFT ID: %v", err)
+	}
+
+	nft, exists := s.state.nfts[nftID]
+	if !exists {
+		return []byte{}, nil
+	}
+
+	return json.Marshal(nft)
+}
+
+// getOwnedNFTs returns the owned-NFT index of an account
+func (s *Simulator) getOwnedNFTs(args []interface{}) ([]byte, error) {
+	if len(args) < 1 {
+		return nil, fmt.Errorf("get_owned_nfts requires 1 argument")
+	}
+
+	owner, err := accountArg(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid owner: %v", err)
+	}
+
+	owned := append([]uint64{}, s.state.ownedNFTs[owner]...)
+	sort.Slice(owned, func(i, j int) bool { return owned[i] < owned[j] })
+
+	return json.Marshal(owned)
+}
+
+// canManage reports whether the caller may transfer or burn an NFT
+func (s *Simulator) canManage(nft models.NFT) bool {
+	if s.caller == nft.Owner || s.caller == s.owner {
+		return true
+	}
+
+	event, exists := s.state.events[nft.EventID]
+	return exists && event.Organizer == s.caller
+}
+
+// removeOwned removes an NFT from an account's owned list
+func (s *Simulator) removeOwned(owner string, nftID uint64) {
+	owned := s.state.ownedNFTs[owner]
+	for i, id := range owned {
+		if id == nftID {
+			s.state.ownedNFTs[owner] = append(owned[:i:i], owned[i+1:]...)
+			return
+		}
+	}
+}
+
+// emit seals a block holding the event and delivers it to subscribers
+func (s *Simulator) emit(event ContractEvent) {
+	s.state.block++
+	event.Block = s.state.block
+	s.state.emitted = append(s.state.emitted, event)
+
+	for _, ch := range s.subscribers {
+		// Slow subscribers miss events rather than stalling the chain
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// canonicalOr canonicalizes an account, falling back to a default when it's empty or invalid
+func canonicalOr(account, fallback string) string {
+	if account != "" {
+		if canonical, err := address.Canonical(account); err == nil {
+			return canonical
+		}
+		log.Printf("Simulator: ignoring invalid account %q", account)
+	}
+	return fallback
+}
+
+// accountArg canonicalizes an account argument
+func accountArg(arg interface{}) (string, error) {
+	account, ok := arg.(string)
+	if !ok {
+		return "", fmt.Errorf("unsupported type: %T", arg)
+	}
+	return address.Canonical(account)
+}
+
+// uint64Arg converts a numeric call argument to uint64
+func uint64Arg(arg interface{}) (uint64, error) {
+	switch v := arg.(type) {
+	case uint64:
+		return v, nil
+	case uint32:
+		return uint64(v), nil
+	case int:
+		return uint64(v), nil
+	case int64:
+		return uint64(v), nil
+	case float64:
+		return uint64(v), nil
+	case json.Number:
+		val, err := v.Int64()
+		if err != nil {
+			return 0, err
+		}
+		return uint64(val), nil
+	default:
+		return 0, fmt.Errorf("unsupported type: %T", arg)
+	}
+}
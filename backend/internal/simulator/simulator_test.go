@@ -0,0 +1,205 @@
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/samuelarogbonlo/polkadot-attendance-nft/backend/internal/models"
+)
+
+// Development accounts other than the contract owner, DefaultAccount (//Alice)
+const (
+	bob     = "0x8eaf04151687736326c9fea17e25fc5287613693c912909cb226aa4794f26a48"
+	charlie = "0x90b5ab205c6974c9ea841be688864633dc9ca8a357843eeacf2314649965fe22"
+	dave    = "0x306721211d5404bd9da88e0204360a1a9ab8b87c66c1bc2fcdd37f3c2222cc20"
+)
+
+// call runs a message as caller and decodes its result into out
+func call(t *testing.T, s *Simulator, caller string, out interface{}, method string, args ...interface{}) {
+	t.Helper()
+
+	if err := s.SetCaller(caller); err != nil {
+		t.Fatalf("SetCaller failed: %v", err)
+	}
+	result, err := s.Call(context.Background(), method, args...)
+	if err != nil {
+		t.Fatalf("%s failed: %v", method, err)
+	}
+	if err := json.Unmarshal(result, out); err != nil {
+		t.Fatalf("failed to decode %s result %q: %v", method, result, err)
+	}
+}
+
+// createEvent creates an event organized by organizer
+func createEvent(t *testing.T, s *Simulator, organizer string, transferable bool) uint64 {
+	t.Helper()
+
+	var eventID uint64
+	call(t, s, organizer, &eventID, "create_event", "Meetup", "2024-05-01", "Berlin", transferable)
+	return eventID
+}
+
+// mint mints an NFT of an event to recipient as caller
+func mint(t *testing.T, s *Simulator, caller string, eventID uint64, recipient string) models.MintResult {
+	t.Helper()
+
+	var result models.MintResult
+	call(t, s, caller, &result, "mint_nft", eventID, recipient, "ipfs://metadata")
+	return result
+}
+
+func TestMintAuthorization(t *testing.T) {
+	tests := []struct {
+		name   string
+		caller string
+		minted bool
+	}{
+		{"organizer", bob, true},
+		{"contract owner", DefaultAccount, true},
+		{"stranger", charlie, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(Config{})
+			eventID := createEvent(t, s, bob, true)
+
+			result := mint(t, s, tt.caller, eventID, dave)
+			if result.Minted != tt.minted {
+				t.Fatalf("minted = %v, want %v", result.Minted, tt.minted)
+			}
+			if tt.minted && result.NFTID == 0 {
+				t.Error("mint returned no NFT ID")
+			}
+			if !tt.minted && len(s.Events()) != 1 {
+				t.Errorf("a rejected mint emitted events: %+v", s.Events())
+			}
+		})
+	}
+}
+
+func TestMintUnknownEvent(t *testing.T) {
+	s := New(Config{})
+	if result := mint(t, s, DefaultAccount, 99, dave); result.Minted {
+		t.Error("minted an NFT of an event that doesn't exist")
+	}
+}
+
+func TestMintStoresMetadataURI(t *testing.T) {
+	s := New(Config{})
+	eventID := createEvent(t, s, bob, true)
+	result := mint(t, s, bob, eventID, dave)
+
+	var nft models.NFT
+	call(t, s, bob, &nft, "get_nft", result.NFTID)
+	if nft.MetadataURI != "ipfs://metadata" || nft.Metadata != nil {
+		t.Errorf("get_nft = %+v, want the metadata URI", nft)
+	}
+	if nft.Owner != dave {
+		t.Errorf("owner = %s, want %s", nft.Owner, dave)
+	}
+}
+
+func TestTransferAuthorization(t *testing.T) {
+	tests := []struct {
+		name         string
+		caller       string
+		transferable bool
+		transferred  bool
+	}{
+		{"owner", dave, true, true},
+		{"organizer", bob, true, true},
+		{"contract owner", DefaultAccount, true, true},
+		{"stranger", charlie, true, false},
+		{"owner of a soulbound NFT", dave, false, false},
+		{"contract owner of a soulbound NFT", DefaultAccount, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(Config{})
+			eventID := createEvent(t, s, bob, tt.transferable)
+			nftID := mint(t, s, bob, eventID, dave).NFTID
+
+			var transferred bool
+			call(t, s, tt.caller, &transferred, "transfer", nftID, charlie)
+			if transferred != tt.transferred {
+				t.Fatalf("transferred = %v, want %v", transferred, tt.transferred)
+			}
+
+			want := dave
+			if tt.transferred {
+				want = charlie
+			}
+			var nft models.NFT
+			call(t, s, bob, &nft, "get_nft", nftID)
+			if nft.Owner != want {
+				t.Errorf("owner = %s, want %s", nft.Owner, want)
+			}
+
+			var owned []uint64
+			call(t, s, bob, &owned, "get_owned_nfts", want)
+			if len(owned) != 1 || owned[0] != nftID {
+				t.Errorf("owned NFTs of %s = %v, want [%d]", want, owned, nftID)
+			}
+		})
+	}
+}
+
+func TestBurnAuthorization(t *testing.T) {
+	tests := []struct {
+		name   string
+		caller string
+		burned bool
+	}{
+		{"owner", dave, true},
+		{"organizer", bob, true},
+		{"contract owner", DefaultAccount, true},
+		{"stranger", charlie, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(Config{})
+			eventID := createEvent(t, s, bob, false)
+			nftID := mint(t, s, bob, eventID, dave).NFTID
+
+			var burned bool
+			call(t, s, tt.caller, &burned, "burn", nftID)
+			if burned != tt.burned {
+				t.Fatalf("burned = %v, want %v", burned, tt.burned)
+			}
+
+			result, err := s.Call(context.Background(), "get_nft", nftID)
+			if err != nil {
+				t.Fatalf("get_nft failed: %v", err)
+			}
+			if exists := len(result) > 0; exists == tt.burned {
+				t.Errorf("NFT exists = %v after burned = %v", exists, tt.burned)
+			}
+		})
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	s := New(Config{})
+	eventID := createEvent(t, s, bob, true)
+	snapshot := s.Snapshot()
+
+	nftID := mint(t, s, bob, eventID, dave).NFTID
+	if err := s.Restore(snapshot); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+
+	result, err := s.Call(context.Background(), "get_nft", nftID)
+	if err != nil {
+		t.Fatalf("get_nft failed: %v", err)
+	}
+	if len(result) > 0 {
+		t.Errorf("NFT minted after the snapshot survived Restore: %s", result)
+	}
+	if s.BlockNumber() != 1 {
+		t.Errorf("block = %d after Restore, want 1", s.BlockNumber())
+	}
+}
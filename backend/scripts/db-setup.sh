@@ -31,17 +31,17 @@ if [ $retries -eq $MAX_RETRIES ]; then
     exit 1
 fi
 
-# Build and run the database migration test tool
-echo "Building migration test tool..."
-go build -o bin/db-test ./cmd/db-test
+# Build and run the database check
+echo "Building attendance CLI..."
+go build -o bin/attendance ./cmd/attendance
 
 if [ $? -ne 0 ]; then
-    echo "Error: Failed to build migration test tool. Exiting."
+    echo "Error: Failed to build attendance CLI. Exiting."
     exit 1
 fi
 
 echo "Running database migrations..."
-./bin/db-test
+./bin/attendance db check
 
 if [ $? -ne 0 ]; then
     echo "Error: Migration test failed. Exiting."
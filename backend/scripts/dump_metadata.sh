@@ -1,12 +1,12 @@
 #!/bin/bash
 
-# Build the metadata dump tool
-echo "Building metadata dump tool..."
+# Build the attendance CLI
+echo "Building attendance CLI..."
 cd "$(dirname "$0")/.."
-go build -o bin/metadata_dump cmd/metadata_dump/main.go
+go build -o bin/attendance ./cmd/attendance
 
 # Run the tool on the contract metadata
 echo "Running metadata dump tool..."
-./bin/metadata_dump ../contracts/target/ink/attendance_nft.json metadata_dump.json
+./bin/attendance contract dump-metadata -out metadata_dump.json ../contracts/target/ink/attendance_nft.json
 
 echo "Done! Metadata has been dumped to metadata_dump.json" 
\ No newline at end of file
@@ -16,6 +16,7 @@ mod attendance_nft {
         date: String,        // Event date
         location: String,    // Event location
         organizer: AccountId,// Event organizer
+        transferable: bool,  // Whether badges can change hands (false = soulbound)
     }
 
     /// Represents an NFT token
@@ -66,6 +67,24 @@ mod attendance_nft {
         event_id: u64,
     }
 
+    #[ink(event)]
+    pub struct NFTTransferred {
+        #[ink(topic)]
+        nft_id: u64,
+        #[ink(topic)]
+        from: AccountId,
+        #[ink(topic)]
+        to: AccountId,
+    }
+
+    #[ink(event)]
+    pub struct NFTBurned {
+        #[ink(topic)]
+        nft_id: u64,
+        #[ink(topic)]
+        owner: AccountId,
+    }
+
     impl Default for AttendanceNFT {
         fn default() -> Self {
             Self::new()
@@ -88,7 +107,7 @@ mod attendance_nft {
 
         /// Create a new event
         #[ink(message)]
-        pub fn create_event(&mut self, name: String, date: String, location: String) -> u64 {
+        pub fn create_event(&mut self, name: String, date: String, location: String, transferable: bool) -> u64 {
             let caller = self.env().caller();
             let event_id = self.event_count.checked_add(1).expect("Event count overflow");
 
@@ -97,6 +116,7 @@ mod attendance_nft {
                 date,
                 location,
                 organizer: caller,
+                transferable,
             };
 
             self.events.insert(event_id, &event_info);
@@ -154,6 +174,72 @@ mod attendance_nft {
             }
         }
 
+        /// Transfer an NFT to a new owner
+        #[ink(message)]
+        pub fn transfer(&mut self, nft_id: u64, to: AccountId) -> bool {
+            let caller = self.env().caller();
+
+            let Some(mut nft) = self.nfts.get(nft_id) else {
+                return false;
+            };
+
+            // Only the holder, the event organizer or the contract owner can transfer
+            if !self.can_manage(caller, &nft) {
+                return false;
+            }
+
+            // Soulbound badges never change hands
+            let transferable = self
+                .events
+                .get(nft.event_id)
+                .map(|event| event.transferable)
+                .unwrap_or(false);
+            if !transferable {
+                return false;
+            }
+
+            let from = nft.owner;
+            self.remove_owned(from, nft_id);
+
+            let mut owned = self.owned_nfts.get(to).unwrap_or_default();
+            owned.push(nft_id);
+            self.owned_nfts.insert(to, &owned);
+
+            nft.owner = to;
+            self.nfts.insert(nft_id, &nft);
+
+            // Emit event
+            self.env().emit_event(NFTTransferred { nft_id, from, to });
+
+            true
+        }
+
+        /// Burn an NFT, e.g. to revoke a fraudulent check-in
+        #[ink(message)]
+        pub fn burn(&mut self, nft_id: u64) -> bool {
+            let caller = self.env().caller();
+
+            let Some(nft) = self.nfts.get(nft_id) else {
+                return false;
+            };
+
+            // Only the holder, the event organizer or the contract owner can burn
+            if !self.can_manage(caller, &nft) {
+                return false;
+            }
+
+            self.remove_owned(nft.owner, nft_id);
+            self.nfts.remove(nft_id);
+
+            // Emit event
+            self.env().emit_event(NFTBurned {
+                nft_id,
+                owner: nft.owner,
+            });
+
+            true
+        }
+
         /// Get NFT by ID
         #[ink(message)]
         pub fn get_nft(&self, nft_id: u64) -> Option<Nft> {
@@ -183,6 +269,24 @@ mod attendance_nft {
         pub fn get_nft_count(&self) -> u64 {
             self.nft_count
         }
+
+        /// Check whether an account may transfer or burn an NFT
+        fn can_manage(&self, account: AccountId, nft: &Nft) -> bool {
+            if account == nft.owner || account == self.owner {
+                return true;
+            }
+            self.events
+                .get(nft.event_id)
+                .map(|event| event.organizer == account)
+                .unwrap_or(false)
+        }
+
+        /// Remove an NFT from an account's owned list
+        fn remove_owned(&mut self, owner: AccountId, nft_id: u64) {
+            let mut owned = self.owned_nfts.get(owner).unwrap_or_default();
+            owned.retain(|id| *id != nft_id);
+            self.owned_nfts.insert(owner, &owned);
+        }
     }
 
     #[cfg(test)]
@@ -200,7 +304,8 @@ mod attendance_nft {
             let event_id = contract.create_event(
                 String::from("Polkadot Meetup"),
                 String::from("2023-06-01"),
-                String::from("Berlin")
+                String::from("Berlin"),
+                true
             );
 
             // Verify event ID is 1
@@ -211,6 +316,7 @@ mod attendance_nft {
             assert_eq!(event.name, "Polkadot Meetup");
             assert_eq!(event.date, "2023-06-01");
             assert_eq!(event.location, "Berlin");
+            assert!(event.transferable);
         }
 
         #[ink::test]
@@ -225,7 +331,8 @@ mod attendance_nft {
             let event_id = contract.create_event(
                 String::from("Polkadot Meetup"),
                 String::from("2023-06-01"),
-                String::from("Berlin")
+                String::from("Berlin"),
+                true
             );
 
             // Mint an NFT
@@ -260,7 +367,8 @@ mod attendance_nft {
             let event_id = contract.create_event(
                 String::from("Polkadot Meetup"),
                 String::from("2023-06-01"),
-                String::from("Berlin")
+                String::from("Berlin"),
+                true
             );
 
             // Try to mint as Bob (unauthorized)
@@ -274,5 +382,89 @@ mod attendance_nft {
             // Verify minting failed
             assert!(!success);
         }
+
+        #[ink::test]
+        fn transfer_works() {
+            // Get test accounts
+            let accounts = test::default_accounts::<ink::env::DefaultEnvironment>();
+
+            // Create a new contract and mint an NFT for Bob
+            let mut contract = AttendanceNFT::new();
+            let event_id = contract.create_event(
+                String::from("Polkadot Meetup"),
+                String::from("2023-06-01"),
+                String::from("Berlin"),
+                true
+            );
+            assert!(contract.mint_nft(event_id, accounts.bob, String::from("{}")));
+            let nft_id = contract.get_owned_nfts(accounts.bob)[0];
+
+            // Transfer as Bob to Charlie
+            test::set_caller::<ink::env::DefaultEnvironment>(accounts.bob);
+            assert!(contract.transfer(nft_id, accounts.charlie));
+
+            // Verify ownership moved
+            assert!(contract.get_owned_nfts(accounts.bob).is_empty());
+            assert_eq!(contract.get_owned_nfts(accounts.charlie), vec![nft_id]);
+            assert_eq!(contract.get_nft(nft_id).unwrap().owner, accounts.charlie);
+
+            // Bob can no longer move it
+            assert!(!contract.transfer(nft_id, accounts.bob));
+        }
+
+        #[ink::test]
+        fn soulbound_transfer_fails() {
+            // Get test accounts
+            let accounts = test::default_accounts::<ink::env::DefaultEnvironment>();
+
+            // Create a non-transferable event and mint an NFT for Bob
+            let mut contract = AttendanceNFT::new();
+            let event_id = contract.create_event(
+                String::from("Polkadot Meetup"),
+                String::from("2023-06-01"),
+                String::from("Berlin"),
+                false
+            );
+            assert!(contract.mint_nft(event_id, accounts.bob, String::from("{}")));
+            let nft_id = contract.get_owned_nfts(accounts.bob)[0];
+
+            // Neither the holder nor the contract owner can move it
+            test::set_caller::<ink::env::DefaultEnvironment>(accounts.bob);
+            assert!(!contract.transfer(nft_id, accounts.charlie));
+            test::set_caller::<ink::env::DefaultEnvironment>(accounts.alice);
+            assert!(!contract.transfer(nft_id, accounts.charlie));
+
+            // It can still be revoked
+            assert!(contract.burn(nft_id));
+        }
+
+        #[ink::test]
+        fn burn_works() {
+            // Get test accounts
+            let accounts = test::default_accounts::<ink::env::DefaultEnvironment>();
+
+            // Create a new contract and mint an NFT for Bob
+            let mut contract = AttendanceNFT::new();
+            let event_id = contract.create_event(
+                String::from("Polkadot Meetup"),
+                String::from("2023-06-01"),
+                String::from("Berlin"),
+                true
+            );
+            assert!(contract.mint_nft(event_id, accounts.bob, String::from("{}")));
+            let nft_id = contract.get_owned_nfts(accounts.bob)[0];
+
+            // Charlie can't burn Bob's NFT
+            test::set_caller::<ink::env::DefaultEnvironment>(accounts.charlie);
+            assert!(!contract.burn(nft_id));
+
+            // The organizer (Alice) can revoke it
+            test::set_caller::<ink::env::DefaultEnvironment>(accounts.alice);
+            assert!(contract.burn(nft_id));
+
+            // Verify the NFT is gone
+            assert!(contract.get_nft(nft_id).is_none());
+            assert!(contract.get_owned_nfts(accounts.bob).is_empty());
+        }
     }
 } 
\ No newline at end of file